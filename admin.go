@@ -0,0 +1,167 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/go-playground/errors/v5"
+)
+
+// logAll overrides the LogAll option of the exporters when set with the AdminHandler
+var logAll atomic.Pointer[bool]
+
+// logAllRequests returns if all requests are logged, which is v unless overridden with the AdminHandler
+func logAllRequests(v bool) bool {
+	if o := logAll.Load(); o != nil {
+		return *o
+	}
+
+	return v
+}
+
+// sampleRate overrides the SampleSuccess option of the exporters when set with the AdminHandler
+var sampleRate atomic.Pointer[float64]
+
+// successSampleRate returns the sample rate of successful requests, which is v unless overridden with the AdminHandler
+func successSampleRate(v float64) float64 {
+	if o := sampleRate.Load(); o != nil {
+		return *o
+	}
+
+	return v
+}
+
+// AdminHandler returns an http.Handler that exposes the runtime logging controls as JSON, to be mounted
+// on an internal admin mux (e.g. mux.Handle("/admin/logging", logger.AdminHandler())).
+//
+// GET returns the controls:
+//
+//	{"level": "DEBUG", "log_all": true, "sample_rate": 0.1, "components": {"db": "TRACE"}}
+//
+// PUT changes the controls present in the body and returns the result. The level applies to all loggers
+// (see SetLevel), log_all overrides the LogAll option of the exporters, sample_rate overrides
+// the SampleSuccess option of the exporters, and components sets the level
+// of named components. A null value restores the configuration of the exporter.
+//
+// The handler has no authentication, it must not be exposed publicly.
+func AdminHandler() http.Handler {
+	return &adminHandler{levels: &levels, logAll: &logAll, sampleRate: &sampleRate}
+}
+
+type adminHandler struct {
+	levels     *levelRegistry
+	logAll     *atomic.Pointer[bool]
+	sampleRate *atomic.Pointer[float64]
+}
+
+// adminControls is the JSON document of the AdminHandler, where nil values are not configured
+type adminControls struct {
+	Level      *string            `json:"level"`
+	LogAll     *bool              `json:"log_all"`
+	SampleRate *float64           `json:"sample_rate"`
+	Components map[string]*string `json:"components"`
+}
+
+func (a *adminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		if status, err := a.update(r); err != nil {
+			http.Error(w, err.Error(), status)
+
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(a.controls())
+}
+
+// controls returns the current controls
+func (a *adminHandler) controls() adminControls {
+	c := adminControls{
+		LogAll:     a.logAll.Load(),
+		SampleRate: a.sampleRate.Load(),
+		Components: make(map[string]*string),
+	}
+	for component, level := range a.levels.all() {
+		name := levelName(level)
+		if component == "" {
+			c.Level = &name
+		} else {
+			c.Components[component] = &name
+		}
+	}
+
+	return c
+}
+
+// update applies the controls in the request body, and returns the status code of the error
+func (a *adminHandler) update(r *http.Request) (int, error) {
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return http.StatusBadRequest, errors.Wrap(err, "io.ReadAll()")
+	}
+
+	// present distinguishes a null value, which restores the configuration, from a missing value
+	var present map[string]json.RawMessage
+	if err := json.Unmarshal(b, &present); err != nil {
+		return http.StatusBadRequest, errors.Wrap(err, "json.Unmarshal()")
+	}
+	var c adminControls
+	if err := json.Unmarshal(b, &c); err != nil {
+		return http.StatusBadRequest, errors.Wrap(err, "json.Unmarshal()")
+	}
+
+	if c.SampleRate != nil && (*c.SampleRate < 0 || *c.SampleRate > 1) {
+		return http.StatusBadRequest, errors.Newf("sample_rate %v is not between 0 and 1", *c.SampleRate)
+	}
+
+	levels := make(map[string]*string, len(c.Components)+1)
+	if _, ok := present["level"]; ok {
+		levels[""] = c.Level
+	}
+	for component, level := range c.Components {
+		if component == "" {
+			return http.StatusBadRequest, errors.New("component name is empty, use level to set the level of all loggers")
+		}
+		levels[component] = level
+	}
+
+	// all levels are parsed before any is changed, so an invalid request changes nothing
+	parsed := make(map[string]slog.Level, len(levels))
+	for component, level := range levels {
+		if level == nil {
+			continue
+		}
+		l, err := parseLevel(*level)
+		if err != nil {
+			return http.StatusBadRequest, err
+		}
+		parsed[component] = l
+	}
+
+	for component, level := range levels {
+		if level == nil {
+			a.levels.reset(component)
+		} else {
+			a.levels.set(component, parsed[component])
+		}
+	}
+	if _, ok := present["log_all"]; ok {
+		a.logAll.Store(c.LogAll)
+	}
+	if _, ok := present["sample_rate"]; ok {
+		a.sampleRate.Store(c.SampleRate)
+	}
+
+	return http.StatusOK, nil
+}
@@ -0,0 +1,158 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAdminHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		prepare    func(a *adminHandler)
+		method     string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "get empty",
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"level":null,"log_all":null,"sample_rate":null,"components":{}}`,
+		},
+		{
+			name: "get",
+			prepare: func(a *adminHandler) {
+				a.levels.set("", LevelTrace)
+				a.levels.set("db", LevelCritical)
+				v := false
+				a.logAll.Store(&v)
+				r := 0.25
+				a.sampleRate.Store(&r)
+			},
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"level":"TRACE","log_all":false,"sample_rate":0.25,"components":{"db":"CRITICAL"}}`,
+		},
+		{
+			name:       "put",
+			method:     http.MethodPut,
+			body:       `{"level":"info","log_all":true,"sample_rate":0.5,"components":{"db":"DEBUG","db.pool":"WARN+2"}}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"level":"INFO","log_all":true,"sample_rate":0.5,"components":{"db":"DEBUG","db.pool":"WARN+2"}}`,
+		},
+		{
+			name: "put null restores",
+			prepare: func(a *adminHandler) {
+				a.levels.set("", LevelTrace)
+				a.levels.set("db", LevelCritical)
+				a.levels.set("http", LevelCritical)
+				v := false
+				a.logAll.Store(&v)
+				r := 0.25
+				a.sampleRate.Store(&r)
+			},
+			method:     http.MethodPut,
+			body:       `{"level":null,"log_all":null,"sample_rate":null,"components":{"db":null}}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"level":null,"log_all":null,"sample_rate":null,"components":{"http":"CRITICAL"}}`,
+		},
+		{
+			name: "put missing values are unchanged",
+			prepare: func(a *adminHandler) {
+				a.levels.set("", LevelTrace)
+			},
+			method:     http.MethodPut,
+			body:       `{"components":{"db":"ERROR"}}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"level":"TRACE","log_all":null,"sample_rate":null,"components":{"db":"ERROR"}}`,
+		},
+		{
+			name:       "put invalid level",
+			method:     http.MethodPut,
+			body:       `{"level":"INFO","components":{"db":"LOUD"}}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "put sample rate above 1",
+			method:     http.MethodPut,
+			body:       `{"level":"INFO","sample_rate":1.5}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "put negative sample rate",
+			method:     http.MethodPut,
+			body:       `{"sample_rate":-0.1}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "put empty component",
+			method:     http.MethodPut,
+			body:       `{"components":{"":"INFO"}}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "put invalid json",
+			method:     http.MethodPut,
+			body:       `{"level":`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodPost,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			a := &adminHandler{levels: &levelRegistry{}, logAll: &atomic.Pointer[bool]{}, sampleRate: &atomic.Pointer[float64]{}}
+			if tt.prepare != nil {
+				tt.prepare(a)
+			}
+
+			w := httptest.NewRecorder()
+			a.ServeHTTP(w, httptest.NewRequest(tt.method, "/admin/logging", strings.NewReader(tt.body)))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("ServeHTTP() status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				if _, ok := a.levels.level(""); ok {
+					t.Error("ServeHTTP() changed the level with an invalid request")
+				}
+				if a.sampleRate.Load() != nil {
+					t.Error("ServeHTTP() changed the sample rate with an invalid request")
+				}
+
+				return
+			}
+			if got := strings.TrimSpace(w.Body.String()); got != tt.wantBody {
+				t.Errorf("ServeHTTP() body = %s, want %s", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func Test_logAllRequests(t *testing.T) {
+	t.Parallel()
+
+	if !logAllRequests(true) || logAllRequests(false) {
+		t.Error("logAllRequests() did not return the exporter value without an override")
+	}
+}
+
+func Test_successSampleRate(t *testing.T) {
+	t.Parallel()
+
+	if successSampleRate(0.5) != 0.5 || successSampleRate(1) != 1 {
+		t.Error("successSampleRate() did not return the exporter value without an override")
+	}
+}
@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// keys of the attributes of audit events
+const (
+	auditEventKey    = "audit.event"
+	auditSequenceKey = "audit.sequence"
+	auditPrevHashKey = "audit.prev_hash"
+	auditHashKey     = "audit.hash"
+)
+
+// auditWriter writes an audit event with its attributes to the audit destination of an exporter
+type auditWriter func(ctx context.Context, traceID, event string, attrs map[string]any)
+
+// auditChain writes the audit events of a middleware to its audit destination. Each event is numbered
+// with a sequence and includes the hash of the previous event, so that removed, reordered, or modified
+// events are detected by verifying the chain.
+type auditChain struct {
+	mu    sync.Mutex
+	seq   uint64
+	prev  string
+	write auditWriter
+}
+
+func newAuditChain(write auditWriter) *auditChain {
+	return &auditChain{write: write}
+}
+
+// audit writes the event with the attributes, the sequence number, the hash of the previous event,
+// and the hash of the event. The hash is the Hash of the JSON of the attributes (without audit.hash)
+// with sorted keys, so verifying the chain requires the key set with SetHashKey.
+func (c *auditChain) audit(ctx context.Context, traceID, event string, attrs []slog.Attr) {
+	m := make(map[string]any, len(attrs)+4)
+	for _, a := range attrs {
+		m[a.Key] = a.Value.Resolve().Any()
+	}
+	m[auditEventKey] = event
+
+	// the lock is held while writing, so events are written in the order of the sequence
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.seq++
+	m[auditSequenceKey] = c.seq
+	m[auditPrevHashKey] = c.prev
+	c.prev = auditHash(m)
+	m[auditHashKey] = c.prev
+
+	c.write(ctx, traceID, event, m)
+}
+
+// auditHash returns the Hash of the canonical JSON of the attributes of an audit event. The JSON is decoded
+// and encoded again, so struct fields are sorted like map keys, and the hash of a written event can be
+// verified from its decoded JSON.
+func auditHash(attrs map[string]any) string {
+	b, err := json.Marshal(attrs)
+	if err == nil {
+		var v any
+		if err = json.Unmarshal(b, &v); err == nil {
+			b, err = json.Marshal(v)
+		}
+	}
+	if err != nil {
+		b = []byte(fmt.Sprint(attrs))
+	}
+
+	return Hash(string(b))
+}
+
+// slogAuditWriter returns an auditWriter that writes the audit events to l, with the trace ID in traceKey
+func slogAuditWriter(l *slog.Logger, traceKey string) auditWriter {
+	return func(ctx context.Context, traceID, event string, attrs map[string]any) {
+		keys := make([]string, 0, len(attrs))
+		for k := range attrs {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		logAttrs := make([]slog.Attr, 0, len(attrs)+1)
+		logAttrs = append(logAttrs, slog.String(traceKey, traceID))
+		for _, k := range keys {
+			logAttrs = append(logAttrs, slog.Any(k, attrs[k]))
+		}
+		l.LogAttrs(ctx, slog.LevelInfo, event, logAttrs...)
+	}
+}
+
+// newAuditContext returns a copy of the context with the audit chain of the request, or ctx when c is nil
+func newAuditContext(ctx context.Context, c *auditChain) context.Context {
+	if c == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, auditKey, c)
+}
+
+// auditFromCtx returns the audit chain of the request, or nil if there is none
+func auditFromCtx(ctx context.Context) *auditChain {
+	if ctx == nil {
+		return nil
+	}
+	c, _ := ctx.Value(auditKey).(*auditChain)
+
+	return c
+}
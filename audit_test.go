@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func Test_auditChain(t *testing.T) {
+	t.Parallel()
+
+	var got []map[string]any
+	c := newAuditChain(func(_ context.Context, _, _ string, attrs map[string]any) {
+		got = append(got, attrs)
+	})
+	c.audit(context.Background(), "trace", "user.created", []slog.Attr{slog.String("user.id", "u1")})
+	c.audit(context.Background(), "trace", "user.deleted", []slog.Attr{slog.String("user.id", "u1")})
+
+	if len(got) != 2 {
+		t.Fatalf("audit() wrote %d events, want 2", len(got))
+	}
+	for i, e := range got {
+		if seq := e[auditSequenceKey]; seq != uint64(i+1) {
+			t.Errorf("event %d %s = %v, want %d", i, auditSequenceKey, seq, i+1)
+		}
+		hash := e[auditHashKey]
+		delete(e, auditHashKey)
+		if want := auditHash(e); hash != want {
+			t.Errorf("event %d %s = %v, want %v", i, auditHashKey, hash, want)
+		}
+		e[auditHashKey] = hash
+	}
+	if got[0][auditPrevHashKey] != "" {
+		t.Errorf("first event %s = %v, want empty", auditPrevHashKey, got[0][auditPrevHashKey])
+	}
+	if got[1][auditPrevHashKey] != got[0][auditHashKey] {
+		t.Errorf("second event %s = %v, want %v", auditPrevHashKey, got[1][auditPrevHashKey], got[0][auditHashKey])
+	}
+}
+
+func Test_auditHash_decoded(t *testing.T) {
+	t.Parallel()
+
+	type detail struct {
+		Zeta  string
+		Alpha int
+	}
+	attrs := map[string]any{auditEventKey: "export", "detail": detail{Zeta: "z", Alpha: 1}, auditSequenceKey: uint64(3)}
+
+	b, err := json.Marshal(attrs)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if got, want := auditHash(decoded), auditHash(attrs); got != want {
+		t.Errorf("auditHash() of decoded event = %v, want %v", got, want)
+	}
+}
+
+func TestLogger_Audit(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	c := newAuditChain(slogAuditWriter(slog.New(slog.NewJSONHandler(&buf, nil)), "trace_id"))
+	ctx := newAuditContext(context.Background(), c)
+	l := &Logger{ctx: ctx, lg: &testCtxLogger{}}
+
+	l.Audit("user.deleted", slog.String("user.id", "u1"))
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v, output %q", err, buf.String())
+	}
+	if got["msg"] != "user.deleted" || got[auditEventKey] != "user.deleted" || got["user.id"] != "u1" {
+		t.Errorf("Audit() wrote %v", got)
+	}
+	if got[auditSequenceKey] != float64(1) {
+		t.Errorf("Audit() %s = %v, want 1", auditSequenceKey, got[auditSequenceKey])
+	}
+}
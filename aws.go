@@ -3,10 +3,12 @@ package logger
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"os"
 	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,45 +18,276 @@ import (
 const (
 	awsTraceIDKey        = "trace_id"
 	awsSpanIDKey         = "span_id"
+	awsXRayTraceIDKey    = "xray_trace_id"
+	awsSegmentIDKey      = "segment_id"
 	awsHTTPElapsedKey    = "http.elapsed"
+	awsHTTPElapsedMSKey  = "http.elapsed_ms"
 	awsHTTPMethodKey     = "http.method"
 	awsHTTPURLKey        = "http.url"
 	awsHTTPStatusCodeKey = "http.status_code"
 	awsHTTPRespLengthKey = "http.response.length"
+	awsHTTPReqLengthKey  = "http.request.length"
 	awsHTTPUserAgentKey  = "http.user_agent"
 	awsHTTPRemoteIPKey   = "http.remote_ip"
 	awsHTTPSchemeKey     = "http.scheme"
 	awsHTTPProtoKey      = "http.proto"
+
+	awsXRayTraceHeader = "X-Amzn-Trace-Id"
+
+	awsEMFKey                = "_aws"
+	awsMetricLatencyKey      = "Latency"
+	awsMetricResponseSizeKey = "ResponseSize"
+	awsMetricStatus2xxKey    = "Status2xx"
+	awsMetricStatus3xxKey    = "Status3xx"
+	awsMetricStatus4xxKey    = "Status4xx"
+	awsMetricStatus5xxKey    = "Status5xx"
+)
+
+// AWSTraceFormat controls the format of the trace ID written to the logs
+type AWSTraceFormat int
+
+const (
+	// OTelTraceFormat writes the trace_id in OpenTelemetry format, e.g. 5759e988bd862e3fe1be46a994272793 (default)
+	OTelTraceFormat AWSTraceFormat = iota
+
+	// XRayTraceFormat writes the trace_id in X-Ray format, e.g. 1-5759e988-bd862e3fe1be46a994272793,
+	// along with the segment_id
+	XRayTraceFormat
+
+	// BothTraceFormat writes the trace_id in OpenTelemetry format, and the xray_trace_id
+	// in X-Ray format along with the segment_id
+	BothTraceFormat
+)
+
+// AWSElapsedFormat controls the format of the request latency written to the parent request log
+type AWSElapsedFormat int
+
+const (
+	// StringElapsedFormat writes http.elapsed as a duration string, e.g. 12.3ms (default)
+	StringElapsedFormat AWSElapsedFormat = iota
+
+	// MillisecondsElapsedFormat writes http.elapsed_ms as a number of milliseconds, e.g. 12.3,
+	// which can be aggregated in CloudWatch Logs Insights
+	MillisecondsElapsedFormat
+
+	// BothElapsedFormat writes both http.elapsed and http.elapsed_ms
+	BothElapsedFormat
 )
 
 // AWSExporter is an Exporter that logs to stdout in JSON format to be sent to cloudwatch
 type AWSExporter struct {
-	// logAll controls if this logger will log all requests, or only requests that have child logs
-	logAll bool
+	exporterOptions[*AWSExporter]
+	handler       slog.Handler
+	writer        io.Writer
+	handlerOpts   *slog.HandlerOptions
+	traceFormat   AWSTraceFormat
+	elapsedFormat AWSElapsedFormat
+	// emfNamespace is the CloudWatch metric namespace, embedded metrics are disabled when empty
+	emfNamespace string
+	ecsMetadata  bool
+	attrGroup    string
+	auditHandler slog.Handler
 }
 
 // NewAWSExporter returns a new AWSExporter
+//
+// logAll controls if this logger will log all requests, or only requests that have child logs
 func NewAWSExporter(logAll bool) *AWSExporter {
-	return &AWSExporter{
-		logAll: logAll,
-	}
+	e := &AWSExporter{}
+	e.exporterOptions = exporterOptions[*AWSExporter]{self: e, logAll: logAll}
+
+	return e
+}
+
+// Handler sets the slog.Handler used to write logs. When set, Writer, HandlerOptions, and MinLevel are ignored.
+//
+// If not set, logs are written in JSON format using slog.JSONHandler.
+func (e *AWSExporter) Handler(h slog.Handler) *AWSExporter {
+	e.handler = h
+
+	return e
+}
+
+// Writer sets the destination of the JSON logs (default: os.Stdout)
+func (e *AWSExporter) Writer(w io.Writer) *AWSExporter {
+	e.writer = w
+
+	return e
+}
+
+// HandlerOptions sets the options (e.g. Level, ReplaceAttr) of the JSON log handler.
+// Trace logs are only written when Level is LevelTrace or lower.
+// Use a *slog.LevelVar as the Level to change the minimum level at runtime.
+func (e *AWSExporter) HandlerOptions(opts *slog.HandlerOptions) *AWSExporter {
+	e.handlerOpts = opts
+
+	return e
+}
+
+// MinLevel sets the minimum level of the logs written by the JSON log handler, overriding the Level of
+// HandlerOptions, e.g. LevelTrace writes Trace logs. Levels above Info also suppress the parent request
+// logs written at Info. (default: the Level of HandlerOptions, slog.LevelInfo)
+func (e *AWSExporter) MinLevel(level slog.Level) *AWSExporter {
+	e.SetMinLevel(level)
+
+	return e
+}
+
+// SetMinLevel changes the minimum level of the logs written by the JSON log handler, and is safe to call at
+// runtime after the Middleware is created, e.g. to temporarily write Debug logs
+func (e *AWSExporter) SetMinLevel(level slog.Level) {
+	e.setMinLevel(level)
+}
+
+// TraceFormat controls the format of the trace ID written to the logs (default: OTelTraceFormat)
+//
+// Use XRayTraceFormat or BothTraceFormat to correlate logs with X-Ray traces in CloudWatch Logs Insights.
+func (e *AWSExporter) TraceFormat(f AWSTraceFormat) *AWSExporter {
+	e.traceFormat = f
+
+	return e
+}
+
+// ElapsedFormat controls the format of the request latency written to the parent request log
+// (default: StringElapsedFormat)
+func (e *AWSExporter) ElapsedFormat(f AWSElapsedFormat) *AWSExporter {
+	e.elapsedFormat = f
+
+	return e
+}
+
+// EmbeddedMetrics enables writing the parent request log in CloudWatch Embedded Metric Format (EMF)
+// so CloudWatch extracts request metrics into the given namespace. The metrics are Latency
+// (milliseconds), ResponseSize (bytes), and the Status2xx, Status3xx, Status4xx and Status5xx counts.
+func (e *AWSExporter) EmbeddedMetrics(namespace string) *AWSExporter {
+	e.emfNamespace = namespace
+
+	return e
+}
+
+// ECSMetadata controls if the ECS task metadata endpoint is queried when the Middleware is created
+// to add the cluster, task ARN, and container ID to all parent request logs. The attributes are omitted
+// when the endpoint can't be read, e.g. when ECS_CONTAINER_METADATA_URI_V4 is not set (default: false)
+func (e *AWSExporter) ECSMetadata(v bool) *AWSExporter {
+	e.ecsMetadata = v
+
+	return e
+}
+
+// AttributeGroup sets a group name that the attributes added with AddRequestAttribute and AddAttribute
+// are nested under (e.g. "app" results in "app.key"). Since grouped attributes can never collide with
+// the reserved keys, they are not prefixed with "custom_". (default: no group)
+func (e *AWSExporter) AttributeGroup(name string) *AWSExporter {
+	e.attrGroup = name
+
+	return e
+}
+
+// MaxMessageLength sets the maximum length in bytes of the message of child logs. Longer messages
+// are truncated and end with "...[truncated]" (default: 0, unlimited)
+func (e *AWSExporter) MaxMessageLength(n int) *AWSExporter {
+	e.maxMsgLen = n
+
+	return e
+}
+
+// MaxAttributeSize sets the maximum size in bytes of the JSON serialization of an attribute value.
+// Larger values are replaced with their serialization, truncated and ending with "...[truncated]",
+// so that a single large attribute does not exceed the 256KB log event limit of CloudWatch Logs (default: 0, unlimited)
+func (e *AWSExporter) MaxAttributeSize(n int) *AWSExporter {
+	e.maxAttrSize = n
+
+	return e
+}
+
+// FollowTraceSampling makes the parent logs follow the OpenTelemetry sampling decision of the request. The
+// parent logs of sampled traces are always written, overriding LogAll and SampleSuccess, and the parent logs
+// of other requests are written only if they contain logs, so that every sampled trace has its request log (default: false)
+func (e *AWSExporter) FollowTraceSampling(v bool) *AWSExporter {
+	e.followTrace = v
+
+	return e
+}
+
+// SampleSuccess writes only a fraction (between 0 and 1) of the parent logs of successful requests, reducing the
+// cost of high traffic services. Requests with an error status, Warning or above logs, or slower than SlowRequest
+// are always written. Child logs are not sampled (default: 1, all requests)
+func (e *AWSExporter) SampleSuccess(rate float64) *AWSExporter {
+	e.sampling, e.sampleRate = true, rate
+
+	return e
+}
+
+// AuditHandler sets the slog.Handler that audit events written with Logger.Audit are sent to, e.g. one writing
+// to a separate CloudWatch log stream (default: the Handler of the request logs)
+func (e *AWSExporter) AuditHandler(h slog.Handler) *AWSExporter {
+	e.auditHandler = h
+
+	return e
 }
 
 // Middleware returns a middleware that logs the request and injects a Logger into the context.
 func (e *AWSExporter) Middleware() func(http.Handler) http.Handler {
+	var resourceAttrs []slog.Attr
+	if e.ecsMetadata {
+		// the attributes are omitted when the metadata can't be read, e.g. when not running on ECS
+		attrs, _ := awsECSAttributes(awsECSMetadataClient, os.Getenv(awsECSMetadataEnv))
+		resourceAttrs = append(resourceAttrs, attrs...)
+	}
+
+	auditHandler := e.auditHandler
+	if auditHandler == nil {
+		auditHandler = e.slogHandler()
+	}
+	audit := newAuditChain(slogAuditWriter(slog.New(auditHandler), awsTraceIDKey))
+
 	return func(next http.Handler) http.Handler {
 		return &awsHandler{
-			next:   next,
-			logger: slog.New(slog.NewJSONHandler(os.Stdout, nil)),
-			logAll: e.logAll,
+			handlerOptions: e.handlerOptions(awsPropagator, audit),
+			next:           next,
+			logger:         slog.New(e.slogHandler()),
+			traceFormat:    e.traceFormat,
+			elapsedFormat:  e.elapsedFormat,
+			emfNamespace:   e.emfNamespace,
+			lambda:         awsLambdaFromEnv(os.Getenv),
+			resourceAttrs:  resourceAttrs,
+			attrGroup:      e.attrGroup,
 		}
 	}
 }
 
+// slogHandler returns the configured slog.Handler
+func (e *AWSExporter) slogHandler() slog.Handler {
+	if e.handler != nil {
+		return e.handler
+	}
+
+	w := e.writer
+	if w == nil {
+		w = os.Stdout
+	}
+
+	opts := e.handlerOpts
+	if e.minLevelVar != nil {
+		opts = &slog.HandlerOptions{Level: e.minLevelVar}
+		if e.handlerOpts != nil {
+			opts.AddSource, opts.ReplaceAttr = e.handlerOpts.AddSource, e.handlerOpts.ReplaceAttr
+		}
+	}
+
+	return slog.NewJSONHandler(w, opts)
+}
+
 type awsHandler struct {
-	next   http.Handler
-	logger awslog
-	logAll bool
+	handlerOptions
+	next          http.Handler
+	logger        awslog
+	traceFormat   AWSTraceFormat
+	elapsedFormat AWSElapsedFormat
+	emfNamespace  string
+	lambda        *awsLambdaFunction // nil when not running in AWS Lambda
+	resourceAttrs []slog.Attr        // attributes describing the environment, added to every parent log
+	attrGroup     string
 }
 
 // ServeHTTP implements http.Handler
@@ -62,63 +295,137 @@ type awsHandler struct {
 // This performs pre and post request logic for logging
 func (h *awsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	begin := time.Now()
-	xrayTraceID := awsTraceIDFromRequest(r, generateID)
-	l := newAWSLogger(h.logger, xrayTraceID)
-	r = r.WithContext(newContext(r.Context(), l))
+	r, span := startServerSpan(r, h.tracing, h.propagator)
+	xrayTraceID := traceIDFromRequest(r, h.propagator, generateID)
+	minLevel, logAll := h.route(r)
+	l := h.newLogger(xrayTraceID, minLevel)
+	r, task := startExecutionTask(r, h.execTrace, xrayTraceID)
+	r, reqBody, disconnect := h.startRequest(w, r, l, begin)
 	sw := newResponseRecorder(w)
 
-	h.next.ServeHTTP(sw, r)
+	if v := h.serve(h.next, sw, r, l); v != nil {
+		defer panic(v)
+	}
+	endServerSpan(span, sw.Status())
+	endExecutionTask(task)
+	grpcLevel, ok := h.endRequest(r, sw, l, disconnect, begin)
+	if !ok {
+		return
+	}
 
 	l.mu.Lock()
 	logCount := l.logCount
 	maxLevel := l.maxLevel
-	attributes := l.reqAttributes
+	attributes := h.attrFilter.apply(l.reqAttributes)
+	levelCounts := l.levelCounts.attrs()
+	faasRequestID := l.faasRequestID
 	l.mu.Unlock()
 
-	if !h.logAll && logCount == 0 {
-		return
-	}
+	// status code and gRPC status should also set the minimum maxLevel
+	maxLevel = max(maxLevel, statusLevel(h.statusLevel, sw.Status()), grpcLevel)
 
-	if sw.Status() > 499 && maxLevel < slog.LevelError {
-		maxLevel = slog.LevelError
+	entry, ok := h.parentLog(r, Entry{Time: begin, Level: maxLevel, Attributes: attributes, TraceID: xrayTraceID, Request: true, Status: sw.Status()}, logAll, logCount)
+	if !ok {
+		return
 	}
+	maxLevel, attributes = entry.Level, entry.Attributes
+	elapsed := time.Since(begin)
 
 	sc := trace.SpanFromContext(r.Context()).SpanContext()
 
-	logAttr := []slog.Attr{
-		slog.Any(awsTraceIDKey, xrayTraceID),
-		slog.Any(awsSpanIDKey, sc.SpanID().String()),
-		slog.String(awsHTTPElapsedKey, time.Since(begin).String()),
+	logAttr := l.traceAttributes(sc.SpanID().String())
+	logAttr = append(logAttr, elapsedAttributes(h.elapsedFormat, elapsed)...)
+	logAttr = append(logAttr, httpAttributes(scrubQuery(r, h.scrubParams), sw, remoteIP(r, h.proxies), reqBody.size(r))...)
+	if h.emfNamespace != "" {
+		logAttr = append(logAttr, emfAttributes(h.emfNamespace, begin, elapsed, sw)...)
 	}
-	logAttr = append(logAttr, httpAttributes(r, sw)...)
-	for k, v := range attributes {
-		logAttr = append(logAttr, slog.Any(k, v))
+	if h.lambda != nil {
+		logAttr = append(logAttr, h.lambda.attributes(r)...)
 	}
+	if faasRequestID != "" {
+		logAttr = append(logAttr, slog.String(awsFaaSRequestIDKey, faasRequestID))
+	}
+	logAttr = append(logAttr, h.resourceAttrs...)
+	logAttr = append(logAttr, l.userAttributes(attributes)...)
+	if n := l.dedupe.suppressed(); n > 0 {
+		logAttr = append(logAttr, slog.Int(suppressedCountKey, n))
+	}
+	if errs, n := l.errSummary.summary(); n > 0 {
+		logAttr = append(logAttr, slog.Any(errorsKey, errs), slog.Int(errorsCountKey, n))
+	}
+	logAttr = append(logAttr, levelCounts...)
 
 	h.logger.LogAttrs(r.Context(), maxLevel, parentLogEntry, logAttr...)
 }
 
+// newLogger returns the root logger of a request, with the minimum level of the route, nil for the level of the Handler
+func (h *awsHandler) newLogger(traceID string, minLevel *slog.Level) *awsLogger {
+	l := newAWSLogger(h.logger, traceID)
+	l.minLevel = minLevel
+	l.traceFormat = h.traceFormat
+	l.attrGroup = h.attrGroup
+	l.errorStack = h.errorStack
+	l.spanEvents = h.spanEvents
+	l.metrics = h.metrics
+	l.caller, l.callerSkip = h.caller, h.callerSkip
+	l.compLevels = h.compLevels
+	l.dedupe = newDeduper(h.dedupe)
+	l.errSummary = newErrorSummary(h.parentErrs)
+	l.levelCounts = newLevelCounter(h.levelCounts)
+	l.maxLogs = h.maxLogs
+	l.maxMsgLen, l.maxAttrSize = h.maxMsgLen, h.maxAttrSize
+	l.attrFilter = h.attrFilter
+	l.processors = h.processors
+
+	return l
+}
+
 type awsLogger struct {
 	root          *awsLogger
 	logger        awslog
 	traceID       string
+	traceFormat   AWSTraceFormat
+	attrGroup     string          // group for user attributes, empty for top-level attributes
+	errorStack    bool            // Error and above child logs include the stack trace
+	spanEvents    bool            // child logs are added as events of the span of the context
+	metrics       MetricsRecorder // child logs are recorded by level, nil when disabled
+	caller        bool            // child logs include the source of the caller
+	callerSkip    int             // additional frames skipped to find the caller
+	callerPkg     string          // function name prefix of the adapted library, whose first frames are skipped to find the caller
+	component     string          // name of the component, empty when not Named
+	compLevels    map[string]slog.Level
+	minLevel      *slog.Level   // minimum level of the component, nil to use the level of the handler
+	dedupe        *deduper      // shared by the logger and its children, nil when disabled
+	errSummary    *errorSummary // shared by the logger and its children, nil when disabled
+	maxMsgLen     int           // messages are truncated to maxMsgLen bytes, 0 for unlimited
+	maxAttrSize   int           // attribute values are truncated to maxAttrSize bytes, 0 for unlimited
+	attrFilter    *attrFilter
+	processors    processors
 	rsvdKeys      []string
 	rsvdReqKeys   []string
 	attributes    map[string]any // attributes for child (trace) logs
 	mu            sync.Mutex
 	maxLevel      slog.Level
 	logCount      int
-	reqAttributes map[string]any // attributes for the parent request log
+	maxLogs       int                // child logs after maxLogs are dropped, 0 for unlimited
+	dropped       int                // number of child logs dropped by maxLogs
+	levelCounts   *levelCounter      // child logs counted by level for the parent log, nil when disabled
+	disconnect    *disconnectWatcher // watches the client of the request, nil outside of requests
+	reqAttributes map[string]any     // attributes for the parent request log
+	faasRequestID string             // request ID of the Lambda invocation, set by WrapLambdaHandler
 }
 
 func newAWSLogger(logger awslog, traceID string) *awsLogger {
 	l := &awsLogger{
 		logger:   logger,
 		traceID:  traceID,
-		rsvdKeys: []string{awsTraceIDKey, awsSpanIDKey},
+		rsvdKeys: []string{awsTraceIDKey, awsSpanIDKey, awsXRayTraceIDKey, awsSegmentIDKey},
 		rsvdReqKeys: []string{
-			awsTraceIDKey, awsSpanIDKey,
-			awsHTTPElapsedKey, awsHTTPMethodKey, awsHTTPURLKey, awsHTTPStatusCodeKey, awsHTTPRespLengthKey, awsHTTPUserAgentKey, awsHTTPRemoteIPKey, awsHTTPSchemeKey, awsHTTPProtoKey,
+			awsTraceIDKey, awsSpanIDKey, awsXRayTraceIDKey, awsSegmentIDKey,
+			awsHTTPElapsedKey, awsHTTPElapsedMSKey, awsHTTPMethodKey, awsHTTPURLKey, awsHTTPStatusCodeKey, awsHTTPRespLengthKey, awsHTTPReqLengthKey, awsHTTPUserAgentKey, awsHTTPRemoteIPKey, awsHTTPSchemeKey, awsHTTPProtoKey,
+			awsEMFKey, awsMetricLatencyKey, awsMetricResponseSizeKey, awsMetricStatus2xxKey, awsMetricStatus3xxKey, awsMetricStatus4xxKey, awsMetricStatus5xxKey,
+			awsFaaSRequestIDKey, awsFaaSNameKey, awsFaaSVersionKey, awsFaaSColdStartKey,
+			awsECSClusterKey, awsECSTaskARNKey, awsECSContainerIDKey,
 		},
 		reqAttributes: make(map[string]any),
 		attributes:    make(map[string]any),
@@ -129,6 +436,7 @@ func newAWSLogger(logger awslog, traceID string) *awsLogger {
 }
 
 type awslog interface {
+	Enabled(ctx context.Context, level slog.Level) bool
 	LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr)
 }
 
@@ -138,6 +446,23 @@ func (l *awsLogger) newChild() *awsLogger {
 		root:          l.root,
 		logger:        l.logger,
 		traceID:       l.traceID,
+		traceFormat:   l.traceFormat,
+		attrGroup:     l.attrGroup,
+		errorStack:    l.errorStack,
+		spanEvents:    l.spanEvents,
+		metrics:       l.metrics,
+		caller:        l.caller,
+		callerSkip:    l.callerSkip,
+		callerPkg:     l.callerPkg,
+		component:     l.component,
+		compLevels:    l.compLevels,
+		minLevel:      l.minLevel,
+		dedupe:        l.dedupe,
+		errSummary:    l.errSummary,
+		maxMsgLen:     l.maxMsgLen,
+		maxAttrSize:   l.maxAttrSize,
+		attrFilter:    l.attrFilter,
+		processors:    l.processors,
 		rsvdKeys:      l.rsvdKeys,
 		rsvdReqKeys:   l.rsvdReqKeys,
 		attributes:    make(map[string]any),
@@ -145,6 +470,16 @@ func (l *awsLogger) newChild() *awsLogger {
 	}
 }
 
+// Trace logs a trace message.
+func (l *awsLogger) Trace(ctx context.Context, v any) {
+	l.log(ctx, LevelTrace, fmt.Sprint(v))
+}
+
+// Tracef logs a trace message with format.
+func (l *awsLogger) Tracef(ctx context.Context, format string, v ...any) {
+	l.log(ctx, LevelTrace, fmt.Sprintf(format, v...))
+}
+
 // Debug logs a debug message.
 func (l *awsLogger) Debug(ctx context.Context, v any) {
 	l.log(ctx, slog.LevelDebug, fmt.Sprint(v))
@@ -185,11 +520,29 @@ func (l *awsLogger) Errorf(ctx context.Context, format string, v ...any) {
 	l.log(ctx, slog.LevelError, fmt.Sprintf(format, v...))
 }
 
+// Critical logs a critical message.
+func (l *awsLogger) Critical(ctx context.Context, v any) {
+	l.log(ctx, LevelCritical, fmt.Sprint(v))
+}
+
+// Criticalf logs a critical message with format.
+func (l *awsLogger) Criticalf(ctx context.Context, format string, v ...any) {
+	l.log(ctx, LevelCritical, fmt.Sprintf(format, v...))
+}
+
+// Enabled reports whether logs at the level are written by the slog.Handler
+func (l *awsLogger) Enabled(ctx context.Context, level Level) bool {
+	return l.enabled(level) && l.logger.Enabled(ctx, level)
+}
+
+// Flush is a no-op, the logs are written by the slog.Handler without buffering
+func (l *awsLogger) Flush() {}
+
 // AddRequestAttribute adds an attribute (key, value) for the parent request log
-// If the key matches a reserved key, it will be prefixed with "custom_"
+// If the key matches a reserved key, it will be prefixed with "custom_", unless the attributes are grouped
 // If the key already exists, its value is overwritten
 func (l *awsLogger) AddRequestAttribute(key string, value any) {
-	if slices.Contains(l.rsvdReqKeys, key) {
+	if l.attrGroup == "" && slices.Contains(l.rsvdReqKeys, key) {
 		key = customPrefix + key
 	}
 
@@ -198,6 +551,32 @@ func (l *awsLogger) AddRequestAttribute(key string, value any) {
 	l.root.reqAttributes[key] = value
 }
 
+// setLambdaRequestID sets the reserved request ID attribute of the parent log of the Lambda invocation
+func (l *awsLogger) setLambdaRequestID(id string) {
+	l.root.mu.Lock()
+	defer l.root.mu.Unlock()
+	l.root.faasRequestID = id
+}
+
+// addStaticAttribute adds an attribute (key, value) for the child (trace) logs and the parent request log
+func (l *awsLogger) addStaticAttribute(key string, value any) {
+	(&awsAttributer{logger: l, attributes: l.attributes}).AddAttribute(key, value)
+	l.AddRequestAttribute(key, value)
+}
+
+// setDisconnect sets the watcher of the client of the request
+func (l *awsLogger) setDisconnect(w *disconnectWatcher) {
+	l.disconnect = w
+}
+
+// requestAttribute returns the value of the attribute of the parent request log
+func (l *awsLogger) requestAttribute(key string) any {
+	l.root.mu.Lock()
+	defer l.root.mu.Unlock()
+
+	return l.root.reqAttributes[key]
+}
+
 // WithAttributes returns an attributer that can be used to add child (trace) log attributes
 func (l *awsLogger) WithAttributes() attributer {
 	attrs := make(map[string]any)
@@ -208,28 +587,159 @@ func (l *awsLogger) WithAttributes() attributer {
 	return &awsAttributer{logger: l, attributes: attrs}
 }
 
+// withCaller returns a child ctxLogger that skips skip more frames, and the first frames with the function
+// name prefix pkg, to find the caller
+func (l *awsLogger) withCaller(skip int, pkg string) ctxLogger {
+	child := l.newChild()
+	for k, v := range l.attributes {
+		child.attributes[k] = v
+	}
+	child.callerSkip += skip
+	if pkg != "" {
+		child.callerPkg = pkg
+	}
+
+	return child
+}
+
+// Named returns a child ctxLogger for the component, with the minimum level of the component
+func (l *awsLogger) Named(name string) ctxLogger {
+	child := l.newChild()
+	for k, v := range l.attributes {
+		child.attributes[k] = v
+	}
+	child.component = componentName(l.component, name)
+	child.attributes[componentKey] = child.component
+	child.minLevel = l.root.minLevel
+	if level, ok := componentLevel(l.compLevels, child.component); ok {
+		child.minLevel = &level
+	}
+
+	return child
+}
+
+// enabled reports whether child logs of the level are passed to the handler, which is false below the
+// level of the component set with SetLevel, or the minimum level of the component
+func (l *awsLogger) enabled(level slog.Level) bool {
+	if minLevel, ok := registeredLevel(l.component); ok {
+		return level >= minLevel
+	}
+
+	return l.minLevel == nil || level >= *l.minLevel
+}
+
 // TraceID returns the trace ID of the request logs
 func (l *awsLogger) TraceID() string {
 	return l.traceID
 }
 
+// RawTraceID returns the trace ID of the request logs
+func (l *awsLogger) RawTraceID() string {
+	return l.traceID
+}
+
 func (l *awsLogger) log(ctx context.Context, level slog.Level, message string) {
+	level = l.root.disconnect.downgrade(level)
+	if !l.enabled(level) {
+		return
+	}
+
+	ok, suppressed := l.dedupe.allow(fmt.Sprint(level, message, l.attributes), time.Now())
+	if !ok {
+		return
+	}
+
+	entry, ok := l.processors.process(Entry{Level: level, Message: message, Attributes: l.attrFilter.apply(l.attributes), Component: l.component, TraceID: l.traceID})
+	if !ok {
+		return
+	}
+	level, message = entry.Level, entry.Message
+
 	l.root.mu.Lock()
 	if l.root.maxLevel < level {
 		l.root.maxLevel = level
 	}
+	if l.root.maxLogs > 0 && l.root.logCount >= l.root.maxLogs {
+		l.root.dropped++
+		l.root.mu.Unlock()
+
+		return
+	}
 	l.root.logCount++
+	l.root.levelCounts.add(level)
 	l.root.mu.Unlock()
 
 	span := trace.SpanFromContext(ctx)
-	attr := []slog.Attr{
-		slog.String(awsTraceIDKey, l.traceID),
-		slog.String(awsSpanIDKey, span.SpanContext().SpanID().String()),
+	attr := l.traceAttributes(span.SpanContext().SpanID().String())
+	attr = append(attr, l.userAttributes(entry.Attributes)...)
+	if l.caller {
+		if frame, ok := caller(l.callerSkip, l.callerPkg); ok {
+			attr = append(attr, slog.Any(slog.SourceKey, &slog.Source{Function: frame.Function, File: frame.File, Line: frame.Line}))
+		}
 	}
-	for k, v := range l.attributes {
-		attr = append(attr, slog.Any(k, v))
+	if l.errorStack && level >= slog.LevelError {
+		attr = append(attr, slog.String(stackTraceKey, callerStack()))
+	}
+	if suppressed > 0 {
+		attr = append(attr, slog.Int(suppressedCountKey, suppressed))
+	}
+	if l.metrics != nil {
+		l.metrics.RecordLog(level)
+	}
+	if level >= slog.LevelError {
+		l.errSummary.add(truncate(message, l.maxMsgLen))
+	}
+	if l.spanEvents {
+		addSpanEvent(ctx, level, message, entry.Attributes)
+	}
+	l.logger.LogAttrs(ctx, level, truncate(message, l.maxMsgLen), attr...)
+}
+
+// stopLimit removes the limit of child logs at the end of the request, and returns the number of logs dropped
+func (l *awsLogger) stopLimit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxLogs = 0
+
+	return l.dropped
+}
+
+// userAttributes returns a slice of slog.Attr for the attributes added by the user,
+// nested under the attribute group if one is configured
+func (l *awsLogger) userAttributes(attributes map[string]any) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(attributes))
+	for k, v := range attributes {
+		attrs = append(attrs, slog.Any(k, truncateValue(v, l.maxAttrSize)))
+	}
+	if l.attrGroup == "" || len(attrs) == 0 {
+		return attrs
+	}
+
+	return []slog.Attr{{Key: l.attrGroup, Value: slog.GroupValue(attrs...)}}
+}
+
+// traceAttributes returns the trace correlation attributes in the configured trace format
+func (l *awsLogger) traceAttributes(spanID string) []slog.Attr {
+	switch l.traceFormat {
+	case XRayTraceFormat:
+		return []slog.Attr{
+			slog.String(awsTraceIDKey, xrayTraceID(l.traceID)),
+			slog.String(awsSpanIDKey, spanID),
+			slog.String(awsSegmentIDKey, spanID),
+		}
+	case BothTraceFormat:
+		return []slog.Attr{
+			slog.String(awsTraceIDKey, l.traceID),
+			slog.String(awsSpanIDKey, spanID),
+			slog.String(awsXRayTraceIDKey, xrayTraceID(l.traceID)),
+			slog.String(awsSegmentIDKey, spanID),
+		}
+	default:
+		return []slog.Attr{
+			slog.String(awsTraceIDKey, l.traceID),
+			slog.String(awsSpanIDKey, spanID),
+		}
 	}
-	l.logger.LogAttrs(ctx, level, message, attr...)
 }
 
 var _ attributer = (*awsAttributer)(nil)
@@ -240,10 +750,10 @@ type awsAttributer struct {
 }
 
 // AddAttribute adds an attribute (key, value) for the child (trace) log
-// If the key matches a reserved key, it will be prefixed with "custom_"
+// If the key matches a reserved key, it will be prefixed with "custom_", unless the attributes are grouped
 // If the key already exists, its value is overwritten
 func (a *awsAttributer) AddAttribute(key string, value any) {
-	if slices.Contains(a.logger.rsvdKeys, key) {
+	if a.logger.attrGroup == "" && slices.Contains(a.logger.rsvdKeys, key) {
 		key = customPrefix + key
 	}
 
@@ -260,29 +770,123 @@ func (a *awsAttributer) Logger() ctxLogger {
 	return l
 }
 
-// httpAttributes returns a slice of slog.Attr for the http request and response
-func httpAttributes(r *http.Request, sw responseRecorder) []slog.Attr {
+// httpAttributes returns a slice of slog.Attr for the http request and response, the client IP, and the
+// size of the request body
+func httpAttributes(r *http.Request, sw responseRecorder, remoteIP string, requestSize int64) []slog.Attr {
 	return []slog.Attr{
 		slog.String(awsHTTPMethodKey, r.Method),
 		slog.String(awsHTTPURLKey, r.URL.String()),
 		slog.Int(awsHTTPStatusCodeKey, sw.Status()),
 		slog.Int64(awsHTTPRespLengthKey, sw.Length()),
+		slog.Int64(awsHTTPReqLengthKey, requestSize),
 		slog.String(awsHTTPUserAgentKey, r.UserAgent()),
-		slog.String(awsHTTPRemoteIPKey, r.RemoteAddr),
+		slog.String(awsHTTPRemoteIPKey, remoteIP),
 		slog.String(awsHTTPSchemeKey, r.URL.Scheme),
 		slog.String(awsHTTPProtoKey, r.Proto),
 	}
 }
 
-// awsTraceIDFromRequest retrieves the trace id from the request if possible
-func awsTraceIDFromRequest(r *http.Request, idgen func() string) string {
-	var traceID string
-	sc := trace.SpanFromContext(r.Context()).SpanContext()
-	if sc.IsValid() {
-		traceID = sc.TraceID().String()
-	} else {
-		traceID = idgen()
+// elapsedAttributes returns a slice of slog.Attr for the request latency in the given format
+func elapsedAttributes(f AWSElapsedFormat, elapsed time.Duration) []slog.Attr {
+	ms := slog.Float64(awsHTTPElapsedMSKey, float64(elapsed)/float64(time.Millisecond))
+	switch f {
+	case MillisecondsElapsedFormat:
+		return []slog.Attr{ms}
+	case BothElapsedFormat:
+		return []slog.Attr{slog.String(awsHTTPElapsedKey, elapsed.String()), ms}
+	default:
+		return []slog.Attr{slog.String(awsHTTPElapsedKey, elapsed.String())}
+	}
+}
+
+// emfAttributes returns a slice of slog.Attr that declares and records the request metrics
+// in CloudWatch Embedded Metric Format
+func emfAttributes(namespace string, begin time.Time, elapsed time.Duration, sw responseRecorder) []slog.Attr {
+	statusCount := func(class int) int {
+		if sw.Status()/100 == class {
+			return 1
+		}
+
+		return 0
+	}
+
+	return []slog.Attr{
+		slog.Any(awsEMFKey, map[string]any{
+			"Timestamp": begin.UnixMilli(),
+			"CloudWatchMetrics": []map[string]any{
+				{
+					"Namespace":  namespace,
+					"Dimensions": [][]string{{}},
+					"Metrics": []map[string]string{
+						{"Name": awsMetricLatencyKey, "Unit": "Milliseconds"},
+						{"Name": awsMetricResponseSizeKey, "Unit": "Bytes"},
+						{"Name": awsMetricStatus2xxKey, "Unit": "Count"},
+						{"Name": awsMetricStatus3xxKey, "Unit": "Count"},
+						{"Name": awsMetricStatus4xxKey, "Unit": "Count"},
+						{"Name": awsMetricStatus5xxKey, "Unit": "Count"},
+					},
+				},
+			},
+		}),
+		slog.Float64(awsMetricLatencyKey, float64(elapsed)/float64(time.Millisecond)),
+		slog.Int64(awsMetricResponseSizeKey, sw.Length()),
+		slog.Int(awsMetricStatus2xxKey, statusCount(2)),
+		slog.Int(awsMetricStatus3xxKey, statusCount(3)),
+		slog.Int(awsMetricStatus4xxKey, statusCount(4)),
+		slog.Int(awsMetricStatus5xxKey, statusCount(5)),
+	}
+}
+
+// xrayTraceID converts an OTel formatted trace ID to X-Ray format, e.g.
+// 5759e988bd862e3fe1be46a994272793 => 1-5759e988-bd862e3fe1be46a994272793
+func xrayTraceID(traceID string) string {
+	if len(traceID) != 32 {
+		return traceID
+	}
+
+	return "1-" + traceID[:8] + "-" + traceID[8:]
+}
+
+// xrayTraceHeader contains the fields of an X-Amzn-Trace-Id header
+type xrayTraceHeader struct {
+	traceID  string // trace ID in OTel format (the X-Ray Root without the version and dashes)
+	parentID string
+	sampled  bool
+}
+
+// parseXRayTraceHeader parses an X-Amzn-Trace-Id header value,
+// e.g. "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1"
+func parseXRayTraceHeader(v string) (xrayTraceHeader, bool) {
+	var h xrayTraceHeader
+	for _, part := range strings.Split(v, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Root":
+			version, rest, ok := strings.Cut(value, "-")
+			if !ok || version != "1" {
+				return xrayTraceHeader{}, false
+			}
+			epoch, unique, ok := strings.Cut(rest, "-")
+			if !ok || len(epoch) != 8 || len(unique) != 24 {
+				return xrayTraceHeader{}, false
+			}
+			if _, err := trace.TraceIDFromHex(epoch + unique); err != nil {
+				return xrayTraceHeader{}, false
+			}
+			h.traceID = epoch + unique
+		case "Parent":
+			h.parentID = value
+		case "Sampled":
+			h.sampled = value == "1"
+		}
+	}
+
+	if h.traceID == "" {
+		return xrayTraceHeader{}, false
 	}
 
-	return traceID
+	return h, true
 }
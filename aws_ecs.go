@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/errors/v5"
+)
+
+const (
+	awsECSClusterKey     = "aws.ecs.cluster"
+	awsECSTaskARNKey     = "aws.ecs.task.arn"
+	awsECSContainerIDKey = "container.id"
+
+	// awsECSMetadataEnv is the environment variable containing the ECS container metadata endpoint (version 4)
+	awsECSMetadataEnv = "ECS_CONTAINER_METADATA_URI_V4"
+)
+
+// awsECSMetadataClient is the client used to query the ECS metadata endpoint
+var awsECSMetadataClient = &http.Client{Timeout: 5 * time.Second}
+
+// awsECSAttributes queries the ECS container metadata endpoint and returns a slice of slog.Attr
+// for the cluster, task ARN, and container ID
+func awsECSAttributes(client *http.Client, uri string) ([]slog.Attr, error) {
+	if uri == "" {
+		return nil, errors.Newf("%s is not set", awsECSMetadataEnv)
+	}
+
+	resp, err := client.Get(uri)
+	if err != nil {
+		return nil, errors.Wrap(err, "http.Client.Get()")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Newf("unexpected status code %d from ECS metadata endpoint", resp.StatusCode)
+	}
+
+	var metadata struct {
+		DockerID string            `json:"DockerId"`
+		Labels   map[string]string `json:"Labels"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		return nil, errors.Wrap(err, "json.Decoder.Decode()")
+	}
+
+	return []slog.Attr{
+		slog.String(awsECSClusterKey, metadata.Labels["com.amazonaws.ecs.cluster"]),
+		slog.String(awsECSTaskARNKey, metadata.Labels["com.amazonaws.ecs.task-arn"]),
+		slog.String(awsECSContainerIDKey, metadata.DockerID),
+	}, nil
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_awsECSAttributes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		noURI   bool
+		want    []slog.Attr
+		wantErr bool
+	}{
+		{
+			name:   "success",
+			status: http.StatusOK,
+			body: `{
+				"DockerId": "ea32192c8553fbff06c9340478a2ff089b2bb5646fb718b4ee206641c9086d66",
+				"Labels": {
+					"com.amazonaws.ecs.cluster": "arn:aws:ecs:us-west-2:111122223333:cluster/default",
+					"com.amazonaws.ecs.task-arn": "arn:aws:ecs:us-west-2:111122223333:task/default/158d1c8083dd49d6b527399fd6414f5c"
+				}
+			}`,
+			want: []slog.Attr{
+				slog.String("aws.ecs.cluster", "arn:aws:ecs:us-west-2:111122223333:cluster/default"),
+				slog.String("aws.ecs.task.arn", "arn:aws:ecs:us-west-2:111122223333:task/default/158d1c8083dd49d6b527399fd6414f5c"),
+				slog.String("container.id", "ea32192c8553fbff06c9340478a2ff089b2bb5646fb718b4ee206641c9086d66"),
+			},
+		},
+		{
+			name:    "error status",
+			status:  http.StatusInternalServerError,
+			wantErr: true,
+		},
+		{
+			name:    "invalid body",
+			status:  http.StatusOK,
+			body:    `{"DockerId":`,
+			wantErr: true,
+		},
+		{
+			name:    "no metadata uri",
+			noURI:   true,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			uri := srv.URL
+			if tt.noURI {
+				uri = ""
+			}
+
+			got, err := awsECSAttributes(srv.Client(), uri)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("awsECSAttributes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(got, tt.want, cmp.Comparer(func(a, b slog.Attr) bool { return a.Equal(b) })); diff != "" {
+				t.Errorf("awsECSAttributes() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"sync/atomic"
+
+	"github.com/go-playground/errors/v5"
+)
+
+const (
+	awsFaaSRequestIDKey = "faas.request_id"
+	awsFaaSNameKey      = "faas.name"
+	awsFaaSVersionKey   = "faas.version"
+	awsFaaSColdStartKey = "faas.coldstart"
+	awsFaaSErrorKey     = "faas.error"
+
+	// awsLambdaContextHeader is set by the AWS Lambda Web Adapter with the JSON encoded Lambda context
+	awsLambdaContextHeader = "X-Amzn-Lambda-Context"
+
+	// awsLambdaTraceCtxKey is the key of the X-Ray trace header in the context of invocations of the Lambda runtime
+	awsLambdaTraceCtxKey = "x-amzn-trace-id"
+)
+
+// awsLambdaFunction contains the metadata of the Lambda function serving the requests
+type awsLambdaFunction struct {
+	name    string
+	version string
+	invoked atomic.Bool
+}
+
+// awsLambdaFromEnv returns the Lambda function metadata read from the environment,
+// or nil if not running in AWS Lambda
+func awsLambdaFromEnv(getenv func(string) string) *awsLambdaFunction {
+	name := getenv("AWS_LAMBDA_FUNCTION_NAME")
+	if name == "" {
+		return nil
+	}
+
+	return &awsLambdaFunction{
+		name:    name,
+		version: getenv("AWS_LAMBDA_FUNCTION_VERSION"),
+	}
+}
+
+// attributes returns a slice of slog.Attr for the Lambda invocation. The first
+// invocation of the function instance is reported as a cold start.
+func (f *awsLambdaFunction) attributes(r *http.Request) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String(awsFaaSNameKey, f.name),
+		slog.String(awsFaaSVersionKey, f.version),
+		slog.Bool(awsFaaSColdStartKey, !f.invoked.Swap(true)),
+	}
+	if requestID := awsLambdaRequestID(r); requestID != "" {
+		attrs = append(attrs, slog.String(awsFaaSRequestIDKey, requestID))
+	}
+
+	return attrs
+}
+
+// awsLambdaRequestID returns the Lambda request ID from the Lambda context header, if present
+func awsLambdaRequestID(r *http.Request) string {
+	v := r.Header.Get(awsLambdaContextHeader)
+	if v == "" {
+		return ""
+	}
+
+	var lc struct {
+		RequestID string `json:"request_id"`
+	}
+	if err := json.Unmarshal([]byte(v), &lc); err != nil {
+		return ""
+	}
+
+	return lc.RequestID
+}
+
+// LambdaHandler is the handler of the invocations of an AWS Lambda function. It has the method of the
+// lambda.Handler of github.com/aws/aws-lambda-go, so the handlers of the runtime are wrapped without a dependency.
+type LambdaHandler interface {
+	Invoke(ctx context.Context, payload []byte) ([]byte, error)
+}
+
+// WrapLambdaHandler returns a LambdaHandler that logs each invocation with the middleware of the exporter, with a
+// parent log for the invocation and correlated child logs from the logger of the context of handler, e.g. for
+// the events of SQS or EventBridge:
+//
+//	handler := logger.WrapLambdaHandler(exporter, lambda.NewHandler(processEvent), func(ctx context.Context) string {
+//		lc, _ := lambdacontext.FromContext(ctx)
+//		return lc.AwsRequestID
+//	})
+//	lambda.Start(handler)
+//
+// Each invocation is a request with the INVOKE method and the path of the function name, with the X-Ray trace
+// of the invocation, so the options of the exporter apply to invocations like to HTTP requests. The parent log
+// has the request ID returned by requestID (nil to omit it), the duration as the latency of the request, and
+// the error. Failed invocations have the 500 status. A panic of handler recovered by the middleware
+// (see RecoverPanics) is returned as an error, so the invocation fails.
+func WrapLambdaHandler(e Exporter, handler LambdaHandler, requestID func(ctx context.Context) string) LambdaHandler {
+	return &lambdaHandler{
+		h:         e.Middleware()(http.HandlerFunc(serveLambda)),
+		handler:   handler,
+		requestID: requestID,
+		path:      "/" + os.Getenv("AWS_LAMBDA_FUNCTION_NAME"),
+	}
+}
+
+// lambdaHandler is the LambdaHandler returned by WrapLambdaHandler
+type lambdaHandler struct {
+	h         http.Handler
+	handler   LambdaHandler
+	requestID func(ctx context.Context) string
+	path      string
+}
+
+// Invoke implements LambdaHandler
+func (l *lambdaHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	call := &lambdaInvocation{handler: l.handler, requestID: l.requestID}
+	l.h.ServeHTTP(&statusResponseWriter{header: make(http.Header)}, lambdaRequest(context.WithValue(ctx, lambdaInvocationKey, call), l.path, payload))
+
+	return call.resp, call.err
+}
+
+// lambdaInvocation is an invocation served by the middleware of an exporter
+type lambdaInvocation struct {
+	handler   LambdaHandler
+	requestID func(ctx context.Context) string
+	resp      []byte
+	err       error
+}
+
+// lambdaRequestIDSetter is implemented by the loggers of exporters that reserve the request ID attribute,
+// which is set on the parent log without the prefix of the attributes added with AddRequestAttribute
+type lambdaRequestIDSetter interface {
+	setLambdaRequestID(id string)
+}
+
+// serveLambda invokes the handler with the payload of the request, and writes the response of the invocation
+func serveLambda(w http.ResponseWriter, r *http.Request) {
+	call, ok := r.Context().Value(lambdaInvocationKey).(*lambdaInvocation)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	payload, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+	defer func() {
+		// the panic is raised again for the middleware, which logs it, and recovers it with RecoverPanics
+		if v := recover(); v != nil {
+			call.resp, call.err = nil, errors.Newf("panic: %v", v)
+			panic(v)
+		}
+	}()
+	call.resp, call.err = call.handler.Invoke(r.Context(), payload)
+
+	l := Req(r)
+	if call.requestID != nil {
+		if id := call.requestID(r.Context()); id != "" {
+			if s, ok := l.lg.(lambdaRequestIDSetter); ok {
+				s.setLambdaRequestID(id)
+			} else {
+				l.AddRequestAttribute(awsFaaSRequestIDKey, id)
+			}
+		}
+	}
+	if call.err != nil {
+		l.AddRequestAttribute(awsFaaSErrorKey, call.err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(call.resp)
+}
+
+// lambdaRequest returns the request of the invocation for the middleware of an exporter
+func lambdaRequest(ctx context.Context, path string, payload []byte) *http.Request {
+	r := (&http.Request{
+		Method:        "INVOKE",
+		URL:           &url.URL{Path: path},
+		RequestURI:    path,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          io.NopCloser(bytes.NewReader(payload)),
+		ContentLength: int64(len(payload)),
+	}).WithContext(ctx)
+
+	if traceHeader, ok := ctx.Value(awsLambdaTraceCtxKey).(string); ok && traceHeader != "" {
+		r.Header.Set(awsXRayTraceHeader, traceHeader)
+	}
+
+	return r
+}
@@ -0,0 +1,246 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func Test_awsLambdaFromEnv(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		env         map[string]string
+		wantNil     bool
+		wantName    string
+		wantVersion string
+	}{
+		{
+			name: "running in lambda",
+			env: map[string]string{
+				"AWS_LAMBDA_FUNCTION_NAME":    "my-function",
+				"AWS_LAMBDA_FUNCTION_VERSION": "$LATEST",
+			},
+			wantName:    "my-function",
+			wantVersion: "$LATEST",
+		},
+		{
+			name:    "not running in lambda",
+			env:     map[string]string{},
+			wantNil: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := awsLambdaFromEnv(func(key string) string { return tt.env[key] })
+			if (got == nil) != tt.wantNil {
+				t.Fatalf("awsLambdaFromEnv() = %v, wantNil %v", got, tt.wantNil)
+			}
+			if got == nil {
+				return
+			}
+			if got.name != tt.wantName {
+				t.Errorf("awsLambdaFromEnv().name = %v, want %v", got.name, tt.wantName)
+			}
+			if got.version != tt.wantVersion {
+				t.Errorf("awsLambdaFromEnv().version = %v, want %v", got.version, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func Test_awsLambdaFunction_attributes(t *testing.T) {
+	t.Parallel()
+
+	f := &awsLambdaFunction{name: "my-function", version: "3"}
+
+	r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	r.Header.Set("X-Amzn-Lambda-Context", `{"request_id":"8476a536-e9f4-11e8-9739-2dfe598c3fcd","deadline":1542409706888}`)
+
+	want := []slog.Attr{
+		slog.String("faas.name", "my-function"),
+		slog.String("faas.version", "3"),
+		slog.Bool("faas.coldstart", true),
+		slog.String("faas.request_id", "8476a536-e9f4-11e8-9739-2dfe598c3fcd"),
+	}
+	if diff := cmp.Diff(f.attributes(r), want, cmp.Comparer(func(a, b slog.Attr) bool { return a.Equal(b) })); diff != "" {
+		t.Errorf("awsLambdaFunction.attributes() mismatch (-want +got):\n%s", diff)
+	}
+
+	want = []slog.Attr{
+		slog.String("faas.name", "my-function"),
+		slog.String("faas.version", "3"),
+		slog.Bool("faas.coldstart", false),
+	}
+	if diff := cmp.Diff(f.attributes(httptest.NewRequest(http.MethodGet, "/", http.NoBody)), want, cmp.Comparer(func(a, b slog.Attr) bool { return a.Equal(b) })); diff != "" {
+		t.Errorf("awsLambdaFunction.attributes() second invocation mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_awsLambdaRequestID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{
+			name:   "lambda context header",
+			header: `{"request_id":"8476a536-e9f4-11e8-9739-2dfe598c3fcd"}`,
+			want:   "8476a536-e9f4-11e8-9739-2dfe598c3fcd",
+		},
+		{
+			name:   "invalid header",
+			header: `{"request_id":`,
+		},
+		{
+			name: "no header",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			if tt.header != "" {
+				r.Header.Set("X-Amzn-Lambda-Context", tt.header)
+			}
+			if got := awsLambdaRequestID(r); got != tt.want {
+				t.Errorf("awsLambdaRequestID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type lambdaHandlerFunc func(ctx context.Context, payload []byte) ([]byte, error)
+
+func (f lambdaHandlerFunc) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	return f(ctx, payload)
+}
+
+func TestWrapLambdaHandler(t *testing.T) {
+	t.Parallel()
+
+	errInvoke := errors.New("invalid event")
+	tests := []struct {
+		name       string
+		resp       []byte
+		err        error
+		wantParent map[string]any
+	}{
+		{
+			name: "success",
+			resp: []byte(`{"ok":true}`),
+			wantParent: map[string]any{
+				"level": "INFO", "msg": "INVOKE /", "method": "INVOKE", "path": "/", "status": float64(200),
+				"requestSize": float64(12), "responseSize": float64(11), "logCount": float64(1),
+				awsFaaSRequestIDKey: "c6af9ac6-7b61-11e6-9a41-93e8deadbeef",
+			},
+		},
+		{
+			name: "failure",
+			err:  errInvoke,
+			wantParent: map[string]any{
+				"level": "ERROR", "msg": "INVOKE /", "method": "INVOKE", "path": "/", "status": float64(500),
+				"requestSize": float64(12), "responseSize": float64(0), "logCount": float64(1),
+				awsFaaSRequestIDKey: "c6af9ac6-7b61-11e6-9a41-93e8deadbeef", awsFaaSErrorKey: errInvoke.Error(),
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			handler := WrapLambdaHandler(NewConsoleExporter().Writer(&buf).Format(JSONFormat), lambdaHandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+				Ctx(ctx).Infof("event %s", payload)
+
+				return tt.resp, tt.err
+			}), func(context.Context) string {
+				return "c6af9ac6-7b61-11e6-9a41-93e8deadbeef"
+			})
+
+			resp, err := handler.Invoke(context.Background(), []byte(`{"id":"420"}`))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("WrapLambdaHandler() error = %v, want %v", err, tt.err)
+			}
+			if string(resp) != string(tt.resp) {
+				t.Errorf("WrapLambdaHandler() response = %s, want %s", resp, tt.resp)
+			}
+
+			_, parent := grpcLogs(t, &buf)
+			ignore := cmpopts.IgnoreMapEntries(func(k string, _ any) bool {
+				return k == "time" || k == "elapsed" || k == "traceID"
+			})
+			if diff := cmp.Diff(tt.wantParent, parent, ignore); diff != "" {
+				t.Errorf("WrapLambdaHandler() parent mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWrapLambdaHandler_AWSExporter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	handler := WrapLambdaHandler(NewAWSExporter(true).Writer(&buf), lambdaHandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+		Ctx(ctx).Infof("event %s", payload)
+
+		return payload, nil
+	}), func(context.Context) string {
+		return "c6af9ac6-7b61-11e6-9a41-93e8deadbeef"
+	})
+
+	if _, err := handler.Invoke(context.Background(), []byte(`{"id":"420"}`)); err != nil {
+		t.Fatalf("WrapLambdaHandler() error = %v", err)
+	}
+
+	_, parent := grpcLogs(t, &buf)
+	if got := parent[awsFaaSRequestIDKey]; got != "c6af9ac6-7b61-11e6-9a41-93e8deadbeef" {
+		t.Errorf("WrapLambdaHandler() parent %s = %v, want c6af9ac6-7b61-11e6-9a41-93e8deadbeef", awsFaaSRequestIDKey, got)
+	}
+	if got, ok := parent[customPrefix+awsFaaSRequestIDKey]; ok {
+		t.Errorf("WrapLambdaHandler() parent %s = %v, want none", customPrefix+awsFaaSRequestIDKey, got)
+	}
+}
+
+func TestWrapLambdaHandler_panic(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	handler := WrapLambdaHandler(NewConsoleExporter().Writer(&buf).Format(JSONFormat).RecoverPanics(false), lambdaHandlerFunc(func(context.Context, []byte) ([]byte, error) {
+		panic("event is nil")
+	}), nil)
+
+	if resp, err := handler.Invoke(context.Background(), []byte(`{"id":"420"}`)); err == nil || resp != nil {
+		t.Errorf("WrapLambdaHandler() = %s, %v, want the error of the panic", resp, err)
+	}
+}
+
+func Test_lambdaRequest(t *testing.T) {
+	t.Parallel()
+
+	header := "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1"
+	// the Lambda runtime sets the trace header with a string key
+	ctx := context.WithValue(context.Background(), awsLambdaTraceCtxKey, header)
+
+	r := lambdaRequest(ctx, "/orders", []byte("{}"))
+	if got := r.Header.Get(awsXRayTraceHeader); got != header {
+		t.Errorf("lambdaRequest() %s = %q, want %q", awsXRayTraceHeader, got, header)
+	}
+	if r.ContentLength != 2 || r.URL.Path != "/orders" {
+		t.Errorf("lambdaRequest() ContentLength = %d, path = %q, want 2, /orders", r.ContentLength, r.URL.Path)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -34,18 +35,24 @@ func TestNewAWSExporter(t *testing.T) {
 			args: args{
 				logAll: true,
 			},
-			want: &AWSExporter{
-				logAll: true,
-			},
+			want: func() *AWSExporter {
+				e := &AWSExporter{}
+				e.exporterOptions = exporterOptions[*AWSExporter]{self: e, logAll: true}
+
+				return e
+			}(),
 		},
 		{
 			name: "TestNewAWSExporter with logall false",
 			args: args{
 				logAll: false,
 			},
-			want: &AWSExporter{
-				logAll: false,
-			},
+			want: func() *AWSExporter {
+				e := &AWSExporter{}
+				e.exporterOptions = exporterOptions[*AWSExporter]{self: e, logAll: false}
+
+				return e
+			}(),
 		},
 	}
 	for _, tt := range tests {
@@ -54,7 +61,7 @@ func TestNewAWSExporter(t *testing.T) {
 			t.Parallel()
 			got := NewAWSExporter(tt.args.logAll)
 
-			if diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(AWSExporter{})); diff != "" {
+			if diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(AWSExporter{}, exporterOptions[*AWSExporter]{})); diff != "" {
 				t.Errorf("NewAWSExporter() mismatch (-want +got):\n%s", diff)
 			}
 		})
@@ -79,9 +86,9 @@ func TestAWSExporter_Middleware(t *testing.T) {
 			},
 			want: func(next http.Handler) http.Handler {
 				return &awsHandler{
-					next:   next,
-					logger: slog.New(slog.NewJSONHandler(os.Stdout, nil)).WithGroup("request_parent_log"),
-					logAll: true,
+					handlerOptions: handlerOptions{logAll: true},
+					next:           next,
+					logger:         slog.New(slog.NewJSONHandler(os.Stdout, nil)).WithGroup("request_parent_log"),
 				}
 			},
 		},
@@ -91,9 +98,7 @@ func TestAWSExporter_Middleware(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
-			e := &AWSExporter{
-				logAll: tt.fields.logAll,
-			}
+			e := NewAWSExporter(tt.fields.logAll)
 
 			got := e.Middleware()(next)
 			if diff := cmp.Diff(got, tt.want(next), cmpopts.IgnoreUnexported(awsHandler{})); diff != "" {
@@ -103,6 +108,110 @@ func TestAWSExporter_Middleware(t *testing.T) {
 	}
 }
 
+func TestAWSExporter_slogHandler(t *testing.T) {
+	t.Parallel()
+
+	var handlerBuf bytes.Buffer
+	customHandler := slog.NewTextHandler(&handlerBuf, nil)
+
+	tests := []struct {
+		name      string
+		exporter  func(buf *bytes.Buffer) *AWSExporter
+		wantDebug bool
+		wantInfo  bool
+	}{
+		{
+			name: "custom writer",
+			exporter: func(buf *bytes.Buffer) *AWSExporter {
+				return NewAWSExporter(true).Writer(buf)
+			},
+			wantInfo: true,
+		},
+		{
+			name: "custom writer and handler options",
+			exporter: func(buf *bytes.Buffer) *AWSExporter {
+				return NewAWSExporter(true).Writer(buf).HandlerOptions(&slog.HandlerOptions{Level: slog.LevelDebug})
+			},
+			wantDebug: true,
+			wantInfo:  true,
+		},
+		{
+			name: "min level",
+			exporter: func(buf *bytes.Buffer) *AWSExporter {
+				return NewAWSExporter(true).Writer(buf).HandlerOptions(&slog.HandlerOptions{Level: slog.LevelWarn}).MinLevel(slog.LevelDebug)
+			},
+			wantDebug: true,
+			wantInfo:  true,
+		},
+		{
+			name: "set min level",
+			exporter: func(buf *bytes.Buffer) *AWSExporter {
+				e := NewAWSExporter(true).Writer(buf).MinLevel(slog.LevelDebug)
+				e.SetMinLevel(slog.LevelWarn)
+
+				return e
+			},
+		},
+		{
+			name: "custom handler",
+			exporter: func(buf *bytes.Buffer) *AWSExporter {
+				return NewAWSExporter(true).Writer(buf).Handler(customHandler)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			e := tt.exporter(&buf)
+
+			h := e.slogHandler()
+			if e.handler != nil && h != e.handler {
+				t.Fatalf("AWSExporter.slogHandler() = %v, want %v", h, e.handler)
+			}
+
+			lg := slog.New(h)
+			lg.Debug("debug message")
+			lg.Info("info message")
+
+			if got := strings.Contains(buf.String(), "debug message"); got != tt.wantDebug {
+				t.Errorf("debug message logged = %v, want %v", got, tt.wantDebug)
+			}
+			if got := strings.Contains(buf.String(), "info message"); got != tt.wantInfo {
+				t.Errorf("info message logged = %v, want %v", got, tt.wantInfo)
+			}
+		})
+	}
+}
+
+func TestAWSExporter_SlowRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		want      bool
+	}{
+		{name: "slow request", threshold: time.Nanosecond, want: true},
+		{name: "fast request", threshold: time.Hour},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+			h := NewAWSExporter(false).Writer(&buf).SlowRequest(tt.threshold).Middleware()(next)
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+			if got := strings.Contains(buf.String(), slowRequestKey); got != tt.want {
+				t.Errorf("slow request logged = %v, want %v: %s", got, tt.want, buf.String())
+			}
+		})
+	}
+}
+
 func Test_awsHandler_ServeHTTP(t *testing.T) {
 	t.Parallel()
 
@@ -187,8 +296,8 @@ func Test_awsHandler_ServeHTTP(t *testing.T) {
 
 			l := &captureSLogger{}
 			handler := &awsHandler{
-				logger: l,
-				logAll: tt.fields.logAll,
+				handlerOptions: handlerOptions{logAll: tt.fields.logAll, propagator: awsPropagator, sampleRate: 1},
+				logger:         l,
 				next: http.HandlerFunc(
 					func(w http.ResponseWriter, r *http.Request) {
 						awsLgr, ok := Req(r).lg.(*awsLogger)
@@ -228,8 +337,8 @@ func Test_awsHandler_ServeHTTP(t *testing.T) {
 			if l.level != tt.wantLevel {
 				t.Errorf("Level = %v, want %v", l.level, tt.wantLevel)
 			}
-			if len(l.attrs) != 13 {
-				t.Errorf("Expected %d request attributes, got %d", 13, len(l.attrs))
+			if len(l.attrs) != 14 {
+				t.Errorf("Expected %d request attributes, got %d", 14, len(l.attrs))
 			}
 			if l.msg != "Parent Log Entry" {
 				t.Errorf("Message = %v, want %v", l.msg, "Parent Log Entry")
@@ -238,6 +347,98 @@ func Test_awsHandler_ServeHTTP(t *testing.T) {
 	}
 }
 
+func Test_elapsedAttributes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		format AWSElapsedFormat
+		want   []slog.Attr
+	}{
+		{
+			name:   "string format",
+			format: StringElapsedFormat,
+			want: []slog.Attr{
+				slog.String("http.elapsed", "12.5ms"),
+			},
+		},
+		{
+			name:   "milliseconds format",
+			format: MillisecondsElapsedFormat,
+			want: []slog.Attr{
+				slog.Float64("http.elapsed_ms", 12.5),
+			},
+		},
+		{
+			name:   "both formats",
+			format: BothElapsedFormat,
+			want: []slog.Attr{
+				slog.String("http.elapsed", "12.5ms"),
+				slog.Float64("http.elapsed_ms", 12.5),
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := elapsedAttributes(tt.format, 12500*time.Microsecond)
+			if diff := cmp.Diff(got, tt.want, cmp.Comparer(func(a, b slog.Attr) bool { return a.Equal(b) })); diff != "" {
+				t.Errorf("elapsedAttributes() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_emfAttributes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		status   int
+		wantJSON []string
+	}{
+		{
+			name:   "success",
+			status: http.StatusOK,
+			wantJSON: []string{
+				`"_aws":{"CloudWatchMetrics":[{"Dimensions":[[]],"Metrics":[{"Name":"Latency","Unit":"Milliseconds"}`,
+				`"Namespace":"my-service"`,
+				`"Timestamp":1700000000000`,
+				`"Latency":12.5`,
+				`"ResponseSize":5`,
+				`"Status2xx":1,"Status3xx":0,"Status4xx":0,"Status5xx":0`,
+			},
+		},
+		{
+			name:   "server error",
+			status: http.StatusInternalServerError,
+			wantJSON: []string{
+				`"Status2xx":0,"Status3xx":0,"Status4xx":0,"Status5xx":1`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			sw := newResponseRecorder(httptest.NewRecorder())
+			sw.WriteHeader(tt.status)
+			_, _ = sw.Write([]byte("hello"))
+
+			var buf bytes.Buffer
+			attrs := emfAttributes("my-service", time.UnixMilli(1700000000000), 12500*time.Microsecond, sw)
+			slog.New(slog.NewJSONHandler(&buf, nil)).LogAttrs(context.Background(), slog.LevelInfo, "test", attrs...)
+
+			for _, want := range tt.wantJSON {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("emfAttributes() = %s, missing: %s", buf.String(), want)
+				}
+			}
+		})
+	}
+}
+
 func Test_awsTraceIDFromRequest(t *testing.T) {
 	t.Parallel()
 	type args struct {
@@ -278,6 +479,18 @@ func Test_awsTraceIDFromRequest(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with X-Amzn-Trace-Id in headers",
+			args: args{
+				mockReq: func(wantTraceStr string) (r *http.Request, traceStr string) {
+					r = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+					r.Header.Add("X-Amzn-Trace-Id", "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1")
+
+					return r, wantTraceStr
+				},
+			},
+			wantTraceStr: "5759e988bd862e3fe1be46a994272793",
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -285,8 +498,153 @@ func Test_awsTraceIDFromRequest(t *testing.T) {
 			t.Parallel()
 			r, want := tt.args.mockReq(tt.wantTraceStr)
 
-			if got := awsTraceIDFromRequest(r, func() string { return tt.args.traceStr }); got != want && (got == "0000000000000000") != tt.wantBlankStr {
-				t.Errorf("awsTraceIDFromRequest() = %v, want %v", got, want)
+			if got := traceIDFromRequest(r, awsPropagator, func() string { return tt.args.traceStr }); got != want && (got == "0000000000000000") != tt.wantBlankStr {
+				t.Errorf("traceIDFromRequest() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_parseXRayTraceHeader(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		header string
+		want   xrayTraceHeader
+		wantOK bool
+	}{
+		{
+			name:   "full header",
+			header: "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1",
+			want: xrayTraceHeader{
+				traceID:  "5759e988bd862e3fe1be46a994272793",
+				parentID: "53995c3f42cd8ad8",
+				sampled:  true,
+			},
+			wantOK: true,
+		},
+		{
+			name:   "root only",
+			header: "Root=1-5759e988-bd862e3fe1be46a994272793",
+			want: xrayTraceHeader{
+				traceID: "5759e988bd862e3fe1be46a994272793",
+			},
+			wantOK: true,
+		},
+		{
+			name:   "not sampled",
+			header: "Root=1-5759e988-bd862e3fe1be46a994272793; Parent=53995c3f42cd8ad8; Sampled=0",
+			want: xrayTraceHeader{
+				traceID:  "5759e988bd862e3fe1be46a994272793",
+				parentID: "53995c3f42cd8ad8",
+			},
+			wantOK: true,
+		},
+		{
+			name:   "invalid root",
+			header: "Root=1-5759e988-xyz;Sampled=1",
+		},
+		{
+			name:   "no root",
+			header: "Parent=53995c3f42cd8ad8;Sampled=1",
+		},
+		{
+			name: "empty header",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := parseXRayTraceHeader(tt.header)
+			if ok != tt.wantOK {
+				t.Fatalf("parseXRayTraceHeader() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(xrayTraceHeader{})); diff != "" {
+				t.Errorf("parseXRayTraceHeader() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_xrayTraceID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		traceID string
+		want    string
+	}{
+		{
+			name:    "otel trace id",
+			traceID: "5759e988bd862e3fe1be46a994272793",
+			want:    "1-5759e988-bd862e3fe1be46a994272793",
+		},
+		{
+			name:    "unknown format",
+			traceID: "1234567890",
+			want:    "1234567890",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := xrayTraceID(tt.traceID); got != tt.want {
+				t.Errorf("xrayTraceID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_awsLogger_traceAttributes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		traceFormat AWSTraceFormat
+		want        []slog.Attr
+	}{
+		{
+			name:        "otel format",
+			traceFormat: OTelTraceFormat,
+			want: []slog.Attr{
+				slog.String("trace_id", "5759e988bd862e3fe1be46a994272793"),
+				slog.String("span_id", "53995c3f42cd8ad8"),
+			},
+		},
+		{
+			name:        "x-ray format",
+			traceFormat: XRayTraceFormat,
+			want: []slog.Attr{
+				slog.String("trace_id", "1-5759e988-bd862e3fe1be46a994272793"),
+				slog.String("span_id", "53995c3f42cd8ad8"),
+				slog.String("segment_id", "53995c3f42cd8ad8"),
+			},
+		},
+		{
+			name:        "both formats",
+			traceFormat: BothTraceFormat,
+			want: []slog.Attr{
+				slog.String("trace_id", "5759e988bd862e3fe1be46a994272793"),
+				slog.String("span_id", "53995c3f42cd8ad8"),
+				slog.String("xray_trace_id", "1-5759e988-bd862e3fe1be46a994272793"),
+				slog.String("segment_id", "53995c3f42cd8ad8"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			l := &awsLogger{
+				traceID:     "5759e988bd862e3fe1be46a994272793",
+				traceFormat: tt.traceFormat,
+			}
+			got := l.traceAttributes("53995c3f42cd8ad8")
+			if diff := cmp.Diff(got, tt.want, cmp.Comparer(func(a, b slog.Attr) bool { return a.Equal(b) })); diff != "" {
+				t.Errorf("awsLogger.traceAttributes() mismatch (-want +got):\n%s", diff)
 			}
 		})
 	}
@@ -313,8 +671,8 @@ func Test_newAWSLogger(t *testing.T) {
 			want: &awsLogger{
 				logger:        &testSlogger{},
 				traceID:       "1234567890",
-				rsvdKeys:      []string{"trace_id", "span_id"},
-				rsvdReqKeys:   []string{"trace_id", "span_id", "http.elapsed", "http.method", "http.url", "http.status_code", "http.response.length", "http.user_agent", "http.remote_ip", "http.scheme", "http.proto"},
+				rsvdKeys:      []string{"trace_id", "span_id", "xray_trace_id", "segment_id"},
+				rsvdReqKeys:   []string{"trace_id", "span_id", "xray_trace_id", "segment_id", "http.elapsed", "http.elapsed_ms", "http.method", "http.url", "http.status_code", "http.response.length", "http.request.length", "http.user_agent", "http.remote_ip", "http.scheme", "http.proto", "_aws", "Latency", "ResponseSize", "Status2xx", "Status3xx", "Status4xx", "Status5xx", "faas.request_id", "faas.name", "faas.version", "faas.coldstart", "aws.ecs.cluster", "aws.ecs.task.arn", "container.id"},
 				reqAttributes: map[string]any{},
 				attributes:    map[string]any{},
 			},
@@ -483,6 +841,7 @@ func Test_awsLogger_AddRequestAttribute(t *testing.T) {
 	type fields struct {
 		root        *awsLogger
 		rsvdReqKeys []string
+		attrGroup   string
 	}
 	type args struct {
 		key   string
@@ -494,6 +853,21 @@ func Test_awsLogger_AddRequestAttribute(t *testing.T) {
 		args   args
 		want   map[string]any
 	}{
+		{
+			name: "reserved key in attribute group",
+			fields: fields{
+				root: &awsLogger{
+					reqAttributes: map[string]any{"test_key_2": "test_value_2"},
+				},
+				rsvdReqKeys: []string{"test_key 1", "test_key"},
+				attrGroup:   "app",
+			},
+			args: args{
+				key:   "test_key",
+				value: 512,
+			},
+			want: map[string]any{"test_key_2": "test_value_2", "test_key": 512},
+		},
 		{
 			name: "prefix reserved key",
 			fields: fields{
@@ -544,6 +918,7 @@ func Test_awsLogger_AddRequestAttribute(t *testing.T) {
 			l := &awsLogger{
 				root:        tt.fields.root,
 				rsvdReqKeys: tt.fields.rsvdReqKeys,
+				attrGroup:   tt.fields.attrGroup,
 			}
 			l.AddRequestAttribute(tt.args.key, tt.args.value)
 			if diff := cmp.Diff(l.root.reqAttributes, tt.want); diff != "" {
@@ -553,6 +928,50 @@ func Test_awsLogger_AddRequestAttribute(t *testing.T) {
 	}
 }
 
+func Test_awsLogger_userAttributes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		attrGroup  string
+		attributes map[string]any
+		want       []slog.Attr
+	}{
+		{
+			name:       "no group",
+			attributes: map[string]any{"test_key_1": "test_value_1"},
+			want: []slog.Attr{
+				slog.String("test_key_1", "test_value_1"),
+			},
+		},
+		{
+			name:       "with group",
+			attrGroup:  "app",
+			attributes: map[string]any{"test_key_1": "test_value_1"},
+			want: []slog.Attr{
+				slog.Group("app", slog.String("test_key_1", "test_value_1")),
+			},
+		},
+		{
+			name:       "with group and no attributes",
+			attrGroup:  "app",
+			attributes: map[string]any{},
+			want:       []slog.Attr{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			l := &awsLogger{attrGroup: tt.attrGroup}
+			got := l.userAttributes(tt.attributes)
+			if diff := cmp.Diff(got, tt.want, cmp.Comparer(func(a, b slog.Attr) bool { return a.Equal(b) })); diff != "" {
+				t.Errorf("awsLogger.userAttributes() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func Test_awsLogger_WithAttributes(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -742,10 +1161,65 @@ func Test_awsAttributer_Logger(t *testing.T) {
 	}
 }
 
+func Test_awsLogger_errorStack(t *testing.T) {
+	t.Parallel()
+
+	c := &captureSLogger{}
+	l := newAWSLogger(c, "1234567890")
+	l.errorStack = true
+
+	l.Info(context.Background(), "message")
+	for _, a := range c.attrs {
+		if a.Key == "stack_trace" {
+			t.Errorf("Info() stack_trace = %v, want none", a.Value)
+		}
+	}
+
+	l.Error(context.Background(), "message")
+	var stack string
+	for _, a := range c.attrs {
+		if a.Key == "stack_trace" {
+			stack = a.Value.String()
+		}
+	}
+	if !strings.Contains(stack, "Test_awsLogger_errorStack") {
+		t.Errorf("Error() stack_trace = %q, want the test function", stack)
+	}
+}
+
+func Test_awsLogger_caller(t *testing.T) {
+	t.Parallel()
+
+	c := &captureSLogger{}
+	l := newAWSLogger(c, "1234567890")
+	l.caller = true
+	l.newChild().Info(context.Background(), "message")
+
+	var source *slog.Source
+	for _, a := range c.attrs {
+		if a.Key == slog.SourceKey {
+			source, _ = a.Value.Any().(*slog.Source)
+		}
+	}
+	if source == nil {
+		t.Fatalf("attrs = %v, want source", c.attrs)
+	}
+	if !strings.HasSuffix(source.Function, ".Test_awsLogger_caller") {
+		t.Errorf("source.Function = %q, want Test_awsLogger_caller", source.Function)
+	}
+	if !strings.HasSuffix(source.File, "aws_test.go") {
+		t.Errorf("source.File = %q, want aws_test.go", source.File)
+	}
+}
+
 type testSlogger struct {
 	buf *bytes.Buffer
 }
 
+func (t *testSlogger) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
 func (t *testSlogger) LogAttrs(_ context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
 	_, _ = fmt.Fprint(t.buf, msg, "level="+level.String(), attrs)
 }
@@ -757,6 +1231,10 @@ type captureSLogger struct {
 	attrs []slog.Attr
 }
 
+func (c *captureSLogger) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
 func (c *captureSLogger) LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
 	c.ctx = ctx
 	c.level = level
@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+)
+
+// requestBodyKey is the attribute of the request body captured for parent logs
+const requestBodyKey = "http.request.body"
+
+// defaultBodyContentTypes are the content types of the request bodies captured by a new BodyCapture
+var defaultBodyContentTypes = []string{"application/json", "application/*+json", "application/x-www-form-urlencoded", "application/xml", "text/*"}
+
+// defaultBodyMaxBytes is the number of bytes of the request bodies captured by a new BodyCapture
+const defaultBodyMaxBytes = 4096
+
+// BodyCapture records the request body in the parent log, e.g. to debug webhook integrations.
+// It is added to an exporter with CaptureRequestBody, e.g.
+//
+//	exporter.CaptureRequestBody(logger.NewBodyCapture().MaxBytes(1024))
+type BodyCapture struct {
+	contentTypes []string
+	maxBytes     int
+	redact       func(contentType string, body []byte) []byte
+}
+
+// NewBodyCapture returns a BodyCapture that records up to 4096 bytes of JSON, XML, form, and text request bodies
+func NewBodyCapture() *BodyCapture {
+	return &BodyCapture{contentTypes: slices.Clone(defaultBodyContentTypes), maxBytes: defaultBodyMaxBytes}
+}
+
+// ContentTypes replaces the content types of the request bodies that are captured, which use the syntax
+// of path.Match, e.g. "application/json" or "text/*"
+func (c *BodyCapture) ContentTypes(types ...string) *BodyCapture {
+	c.contentTypes = slices.Clone(types)
+
+	return c
+}
+
+// MaxBytes sets the number of bytes of the request body that are captured. Longer bodies are
+// truncated (default: 4096)
+func (c *BodyCapture) MaxBytes(n int) *BodyCapture {
+	c.maxBytes = n
+
+	return c
+}
+
+// Redact sets a function that masks sensitive data in the captured body before it is logged. The body
+// passed to the function may be truncated to MaxBytes.
+func (c *BodyCapture) Redact(f func(contentType string, body []byte) []byte) *BodyCapture {
+	c.redact = f
+
+	return c
+}
+
+// clone returns a copy of the BodyCapture, so changes after the middleware is created are not applied
+func (c *BodyCapture) clone() *BodyCapture {
+	if c == nil {
+		return nil
+	}
+
+	return &BodyCapture{contentTypes: slices.Clone(c.contentTypes), maxBytes: c.maxBytes, redact: c.redact}
+}
+
+// capture reads up to MaxBytes of the request body when its content type is captured, and replaces the body,
+// so the handler reads the complete body. It returns false when the body is not captured.
+func (c *BodyCapture) capture(r *http.Request) (string, bool) {
+	if c == nil || c.maxBytes <= 0 || r.Body == nil || r.Body == http.NoBody {
+		return "", false
+	}
+
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || !slices.ContainsFunc(c.contentTypes, func(pattern string) bool {
+		ok, _ := path.Match(pattern, contentType)
+
+		return ok
+	}) {
+		return "", false
+	}
+
+	// read one more byte than captured to know if the body was truncated
+	buf, err := io.ReadAll(io.LimitReader(r.Body, int64(c.maxBytes)+1))
+	r.Body = &replayBody{Reader: io.MultiReader(bytes.NewReader(buf), r.Body), Closer: r.Body}
+	if err != nil {
+		return "", false
+	}
+
+	body, truncated := buf, len(buf) > c.maxBytes
+	if truncated {
+		body = buf[:c.maxBytes]
+	}
+	if c.redact != nil {
+		body = c.redact(contentType, slices.Clone(body))
+	}
+	if truncated {
+		return string(body) + truncatedMarker, true
+	}
+
+	return string(body), true
+}
+
+// replayBody is a request body that replays the bytes read by BodyCapture before the rest of the body
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBodyCapture_capture(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		capture     *BodyCapture
+		contentType string
+		body        string
+		want        string
+		wantOK      bool
+	}{
+		{
+			name:        "disabled",
+			contentType: "application/json",
+			body:        `{"id":1}`,
+		},
+		{
+			name:        "json",
+			capture:     NewBodyCapture(),
+			contentType: "application/json; charset=utf-8",
+			body:        `{"id":1}`,
+			want:        `{"id":1}`,
+			wantOK:      true,
+		},
+		{
+			name:        "text pattern",
+			capture:     NewBodyCapture(),
+			contentType: "text/plain",
+			body:        "hello",
+			want:        "hello",
+			wantOK:      true,
+		},
+		{
+			name:        "content type not captured",
+			capture:     NewBodyCapture(),
+			contentType: "application/octet-stream",
+			body:        "binary",
+		},
+		{
+			name:        "truncated",
+			capture:     NewBodyCapture().MaxBytes(4),
+			contentType: "text/plain",
+			body:        "hello world",
+			want:        "hell" + truncatedMarker,
+			wantOK:      true,
+		},
+		{
+			name:        "redacted",
+			capture:     NewBodyCapture().Redact(func(_ string, b []byte) []byte { return bytes.ReplaceAll(b, []byte("secret"), []byte("***")) }),
+			contentType: "application/x-www-form-urlencoded",
+			body:        "user=a&pass=secret",
+			want:        "user=a&pass=***",
+			wantOK:      true,
+		},
+		{
+			name:        "custom content types",
+			capture:     NewBodyCapture().ContentTypes("application/octet-stream"),
+			contentType: "application/json",
+			body:        `{"id":1}`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", tt.contentType)
+
+			got, ok := tt.capture.capture(r)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("capture() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+
+			// the handler must still read the complete body
+			b, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			if string(b) != tt.body {
+				t.Errorf("Body = %q, want %q", b, tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"os"
+	"runtime/debug"
+	"sync"
+)
+
+const (
+	serviceVersionKey = "service.version"
+	hostNameKey       = "host.name"
+	processPIDKey     = "process.pid"
+)
+
+// buildAttributes returns the attributes describing the build and the host of the process,
+// added to parent logs when IncludeBuildInfo is enabled
+var buildAttributes = sync.OnceValue(func() map[string]any {
+	attrs := map[string]any{processPIDKey: os.Getpid()}
+	if v := buildVersion(debug.ReadBuildInfo()); v != "" {
+		attrs[serviceVersionKey] = v
+	}
+	if host, err := os.Hostname(); err == nil {
+		attrs[hostNameKey] = host
+	}
+
+	return attrs
+})
+
+// buildVersion returns the version of the main module, or the VCS revision when the module
+// is not versioned, e.g. built from a checkout. A "-dirty" suffix marks modified sources.
+func buildVersion(info *debug.BuildInfo, ok bool) string {
+	if !ok {
+		return ""
+	}
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		return v
+	}
+
+	var revision, modified string
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			modified = s.Value
+		}
+	}
+	if revision != "" && modified == "true" {
+		revision += "-dirty"
+	}
+
+	return revision
+}
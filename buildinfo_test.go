@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func Test_buildVersion(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		info *debug.BuildInfo
+		ok   bool
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "no build info",
+			args: args{ok: false},
+		},
+		{
+			name: "module version",
+			args: args{info: &debug.BuildInfo{Main: debug.Module{Version: "v1.2.3"}}, ok: true},
+			want: "v1.2.3",
+		},
+		{
+			name: "vcs revision",
+			args: args{info: &debug.BuildInfo{Main: debug.Module{Version: "(devel)"}, Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "4f1c2a9"},
+				{Key: "vcs.modified", Value: "false"},
+			}}, ok: true},
+			want: "4f1c2a9",
+		},
+		{
+			name: "modified vcs revision",
+			args: args{info: &debug.BuildInfo{Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "4f1c2a9"},
+				{Key: "vcs.modified", Value: "true"},
+			}}, ok: true},
+			want: "4f1c2a9-dirty",
+		},
+		{
+			name: "unversioned",
+			args: args{info: &debug.BuildInfo{Main: debug.Module{Version: "(devel)"}}, ok: true},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := buildVersion(tt.args.info, tt.args.ok); got != tt.want {
+				t.Errorf("buildVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
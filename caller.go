@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// pkgPrefix is the function name prefix of this package, e.g. "github.com/cccteam/logger."
+var pkgPrefix = func() string {
+	pc, _, _, _ := runtime.Caller(0)
+	name := runtime.FuncForPC(pc).Name()
+
+	i := strings.LastIndex(name, "/") + 1
+
+	return name[:i+strings.Index(name[i:], ".")+1]
+}()
+
+// callerFrames returns the stack frames of the calling goroutine, starting at the first frame
+// outside of this package (test files excluded) so the logger internals are not included
+func callerFrames() []runtime.Frame {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	var callers []runtime.Frame
+	for more := true; more; {
+		var frame runtime.Frame
+		frame, more = frames.Next()
+		if len(callers) == 0 && strings.HasPrefix(frame.Function, pkgPrefix) && !strings.HasSuffix(frame.File, "_test.go") {
+			continue
+		}
+		callers = append(callers, frame)
+	}
+
+	return callers
+}
+
+// caller returns the frame of the caller of the logger, skipping the first frames with the function name
+// prefix pkg when not empty (e.g. of the library of an adapter), and skip additional frames (e.g. for logging
+// helpers), and false if the stack is not that deep
+func caller(skip int, pkg string) (runtime.Frame, bool) {
+	frames := callerFrames()
+	for pkg != "" && len(frames) > 0 && strings.HasPrefix(frames[0].Function, pkg) {
+		frames = frames[1:]
+	}
+	if skip < 0 || skip >= len(frames) {
+		return runtime.Frame{}, false
+	}
+
+	return frames[skip], true
+}
+
+// callerSkipper is implemented by the ctxLoggers that write the caller, so that the adapters of this package
+// skip the frames of the library they adapt
+type callerSkipper interface {
+	// withCaller returns a child ctxLogger that skips skip more frames, and the first frames with the
+	// function name prefix pkg, to find the caller
+	withCaller(skip int, pkg string) ctxLogger
+}
+
+// withCaller returns the logger skipping skip more frames, and the first frames with the function name
+// prefix pkg, to find the caller, or l when its ctxLogger does not write the caller
+func (l *Logger) withCaller(skip int, pkg string) *Logger {
+	cs, ok := l.lg.(callerSkipper)
+	if !ok {
+		return l
+	}
+
+	return &Logger{ctx: l.ctx, lg: cs.withCaller(skip, pkg)}
+}
+
+// stackTraceKey is the attribute of the stack trace added to Error logs when ErrorStack is enabled,
+// which is the field Google Cloud Error Reporting reads the stack trace from
+const stackTraceKey = "stack_trace"
+
+// callerStack returns the stack trace of the calling goroutine in the format of a Go panic, starting at
+// the first frame outside of this package
+func callerStack() string {
+	var header [64]byte
+	goroutine, _, _ := strings.Cut(string(header[:runtime.Stack(header[:], false)]), "\n")
+
+	var b strings.Builder
+	b.WriteString(goroutine + "\n")
+	for _, frame := range callerFrames() {
+		fmt.Fprintf(&b, "%s(...)\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+	}
+
+	return b.String()
+}
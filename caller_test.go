@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_callerStack(t *testing.T) {
+	t.Parallel()
+
+	lines := strings.Split(callerStack(), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("callerStack() = %q, want a goroutine header and frames", lines)
+	}
+	if !strings.HasPrefix(lines[0], "goroutine ") || !strings.HasSuffix(lines[0], "[running]:") {
+		t.Errorf("callerStack() header = %q, want goroutine header", lines[0])
+	}
+	if want := pkgPrefix + "Test_callerStack(...)"; lines[1] != want {
+		t.Errorf("callerStack() first frame = %q, want %q", lines[1], want)
+	}
+	if !strings.Contains(lines[2], "caller_test.go:") {
+		t.Errorf("callerStack() first frame file = %q, want caller_test.go", lines[2])
+	}
+}
+
+func Test_caller(t *testing.T) {
+	t.Parallel()
+
+	frame, ok := caller(0, "")
+	if !ok {
+		t.Fatal("caller(0) ok = false, want true")
+	}
+	if want := pkgPrefix + "Test_caller"; frame.Function != want {
+		t.Errorf("caller(0).Function = %q, want %q", frame.Function, want)
+	}
+	if !strings.HasSuffix(frame.File, "caller_test.go") {
+		t.Errorf("caller(0).File = %q, want caller_test.go", frame.File)
+	}
+
+	if frame, ok := caller(1, ""); !ok || frame.Function == pkgPrefix+"Test_caller" {
+		t.Errorf("caller(1) = %v, %v, want the caller of Test_caller", frame.Function, ok)
+	}
+
+	if frame, ok := caller(0, pkgPrefix); !ok || !strings.HasPrefix(frame.Function, "testing.") {
+		t.Errorf("caller(0, pkgPrefix) = %v, %v, want the testing caller of Test_caller", frame.Function, ok)
+	}
+
+	if _, ok := caller(1000, ""); ok {
+		t.Error("caller(1000) ok = true, want false")
+	}
+}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"cloud.google.com/go/logging"
+	"github.com/go-playground/errors/v5"
+)
+
+// keys of the attributes of the parent log of requests canceled by the client
+const (
+	clientDisconnectedKey        = "client_disconnected"
+	clientDisconnectedElapsedKey = "client_disconnected_elapsed"
+)
+
+// disconnectWatcher records when the client of a request disconnects, which cancels the request context
+type disconnectWatcher struct {
+	ctx     context.Context
+	begin   time.Time
+	level   *slog.Level  // level of Error and above logs after the disconnect, nil to keep their level
+	elapsed atomic.Int64 // time from begin to the disconnect, 0 while connected
+	stop    func() bool
+}
+
+// watchDisconnect watches the request context for a disconnect of the client until disconnected is called
+func watchDisconnect(ctx context.Context, begin time.Time, level *slog.Level) *disconnectWatcher {
+	w := &disconnectWatcher{ctx: ctx, begin: begin, level: level}
+	w.stop = context.AfterFunc(ctx, func() {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			w.elapsed.CompareAndSwap(0, int64(max(time.Since(begin), 1)))
+		}
+	})
+
+	return w
+}
+
+// disconnected stops watching the request context, and returns the time from the beginning of the request
+// to the disconnect of the client, and whether the client disconnected
+func (w *disconnectWatcher) disconnected() (time.Duration, bool) {
+	if w == nil {
+		return 0, false
+	}
+
+	w.stop()
+	if !errors.Is(w.ctx.Err(), context.Canceled) {
+		return 0, false
+	}
+	// the AfterFunc may still be running if the context was just canceled
+	w.elapsed.CompareAndSwap(0, int64(max(time.Since(w.begin), 1)))
+
+	return time.Duration(w.elapsed.Load()), true
+}
+
+// downgrade returns the level of a child log, which is lowered for Error and above logs written after the
+// client disconnected, since they are usually caused by the canceled context
+func (w *disconnectWatcher) downgrade(level slog.Level) slog.Level {
+	if w == nil || w.level == nil || level < slog.LevelError || level <= *w.level || !errors.Is(w.ctx.Err(), context.Canceled) {
+		return level
+	}
+
+	return *w.level
+}
+
+// downgradeSeverity is downgrade for the severity of the GoogleCloudExporter and ConsoleExporter logs
+func (w *disconnectWatcher) downgradeSeverity(severity logging.Severity) logging.Severity {
+	if level := severityLevel(severity); w.downgrade(level) != level {
+		return levelSeverity(w.downgrade(level))
+	}
+
+	return severity
+}
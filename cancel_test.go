@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/logging"
+)
+
+func Test_disconnectWatcher(t *testing.T) {
+	t.Parallel()
+
+	warn := slog.LevelWarn
+	tests := []struct {
+		name             string
+		cancel           bool
+		level            *slog.Level
+		wantDisconnected bool
+		wantLevel        slog.Level
+		wantSeverity     logging.Severity
+	}{
+		{
+			name:         "connected",
+			level:        &warn,
+			wantLevel:    slog.LevelError,
+			wantSeverity: logging.Error,
+		},
+		{
+			name:             "disconnected",
+			cancel:           true,
+			wantDisconnected: true,
+			wantLevel:        slog.LevelError,
+			wantSeverity:     logging.Error,
+		},
+		{
+			name:             "disconnected downgrade",
+			cancel:           true,
+			level:            &warn,
+			wantDisconnected: true,
+			wantLevel:        slog.LevelWarn,
+			wantSeverity:     logging.Warning,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			w := watchDisconnect(ctx, time.Now(), tt.level)
+			if tt.cancel {
+				cancel()
+			}
+
+			elapsed, ok := w.disconnected()
+			if ok != tt.wantDisconnected {
+				t.Errorf("disconnected() = %v, want %v", ok, tt.wantDisconnected)
+			}
+			if ok && elapsed <= 0 {
+				t.Errorf("disconnected() elapsed = %v, want > 0", elapsed)
+			}
+			if got := w.downgrade(slog.LevelError); got != tt.wantLevel {
+				t.Errorf("downgrade() = %v, want %v", got, tt.wantLevel)
+			}
+			if got := w.downgradeSeverity(logging.Error); got != tt.wantSeverity {
+				t.Errorf("downgradeSeverity() = %v, want %v", got, tt.wantSeverity)
+			}
+			if got := w.downgrade(slog.LevelInfo); got != slog.LevelInfo {
+				t.Errorf("downgrade(Info) = %v, want %v", got, slog.LevelInfo)
+			}
+		})
+	}
+}
+
+func Test_disconnectWatcher_deadline(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	if _, ok := watchDisconnect(ctx, time.Now(), nil).disconnected(); ok {
+		t.Error("disconnected() = true for an expired deadline, want false")
+	}
+}
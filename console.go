@@ -1,41 +1,133 @@
 package logger
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
+	"maps"
 	"net/http"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"cloud.google.com/go/logging"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
 	cslReqSize  = "requestSize"
 	cslRespSize = "responseSize"
 	cslLogCount = "logCount"
+	cslMethod   = "method"
+	cslPath     = "path"
+	cslStatus   = "status"
+	cslElapsed  = "elapsed"
+	cslTraceID  = "traceID"
+	cslSpanID   = "spanID"
 )
 
+// ConsoleFormat is the output format of the ConsoleExporter
+type ConsoleFormat int
+
+const (
+	// TextFormat writes human readable log lines, with the log level highlighted in color (default)
+	TextFormat ConsoleFormat = iota
+
+	// JSONFormat writes one JSON object per log line
+	JSONFormat
+
+	// LogfmtFormat writes one logfmt (key=value) record per log line
+	LogfmtFormat
+
+	// PrettyFormat writes human readable log lines like TextFormat, with the attributes (including
+	// nested maps, structs and slices) indented across multiple lines
+	PrettyFormat
+)
+
+// ConsoleRequest contains the details of a request used to format the parent request log line
+type ConsoleRequest struct {
+	Request      *http.Request
+	Begin        time.Time
+	Elapsed      time.Duration
+	Status       int
+	RequestSize  int64
+	ResponseSize int64
+	LogCount     int
+	RemoteIP     string // IP address of the client, resolved with the TrustedProxies option
+}
+
+// DefaultRequestFormat formats the parent request log line as
+// "{method} {path} {status} {elapsed} requestSize={n} responseSize={n} logCount={n}"
+func DefaultRequestFormat(req ConsoleRequest) string {
+	return fmt.Sprintf("%s %s %d %s %s=%d %s=%d %s=%d", req.Request.Method, req.Request.URL.Path, req.Status, req.Elapsed,
+		cslReqSize, req.RequestSize, cslRespSize, req.ResponseSize, cslLogCount, req.LogCount,
+	)
+}
+
+// CombinedRequestFormat formats the parent request log line in the Apache Combined Log Format, e.g.
+// 127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326 "http://www.example.com/start.html" "Mozilla/4.08"
+func CombinedRequestFormat(req ConsoleRequest) string {
+	r := req.Request
+	user := "-"
+	if u, _, ok := r.BasicAuth(); ok && u != "" {
+		user = u
+	}
+	size := "-"
+	if req.ResponseSize > 0 {
+		size = strconv.FormatInt(req.ResponseSize, 10)
+	}
+	dash := func(v string) string {
+		if v == "" {
+			return "-"
+		}
+
+		return v
+	}
+
+	return fmt.Sprintf("%s - %s [%s] \"%s %s %s\" %d %s \"%s\" \"%s\"", dash(cmp.Or(req.RemoteIP, hostFromAddr(r.RemoteAddr))), user,
+		req.Begin.Format("02/Jan/2006:15:04:05 -0700"), r.Method, r.URL.RequestURI(), r.Proto, req.Status, size,
+		dash(r.Referer()), dash(r.UserAgent()),
+	)
+}
+
+// consoleTrace is the severity of Trace logs, the console logger tracks severity
+// with logging.Severity which has no level below Debug other than Default
+const consoleTrace = logging.Default
+
 type color int
 
 const (
 	red    color = 31
 	yellow color = 33
 	blue   color = 34
+	cyan   color = 36
 	gray   color = 37
 )
 
 // ConsoleExporter implements exporting to the console
 type ConsoleExporter struct {
+	exporterOptions[*ConsoleExporter]
 	noColor bool
+	writer  io.Writer
+	format  ConsoleFormat
+	// timeFormat is the timestamp layout, the default timestamp of the format is used when nil
+	timeFormat  *string
+	utc         bool
+	reqFormat   func(ConsoleRequest) string
+	auditWriter io.Writer
 }
 
 // NewConsoleExporter returns a configured ConsoleExporter
 func NewConsoleExporter() *ConsoleExporter {
-	return &ConsoleExporter{}
+	e := &ConsoleExporter{}
+	e.exporterOptions = exporterOptions[*ConsoleExporter]{self: e, logAll: true, minLevelVar: newLevelVar(slog.LevelDebug)}
+
+	return e
 }
 
 // NoColor controls if this logger will use color to highlight log level
@@ -45,66 +137,314 @@ func (e *ConsoleExporter) NoColor(v bool) *ConsoleExporter {
 	return e
 }
 
+// Writer sets the destination of the console logs (default: the output of the standard log package)
+func (e *ConsoleExporter) Writer(w io.Writer) *ConsoleExporter {
+	e.writer = w
+
+	return e
+}
+
+// Format sets the output format of the console logs (default: TextFormat)
+func (e *ConsoleExporter) Format(f ConsoleFormat) *ConsoleExporter {
+	e.format = f
+
+	return e
+}
+
+// TimeFormat sets the layout of the timestamp written with each console log (e.g. time.RFC3339).
+// An empty layout omits the timestamp. (default: the standard log package timestamp for TextFormat,
+// and RFC3339 with nanoseconds for JSONFormat and LogfmtFormat)
+func (e *ConsoleExporter) TimeFormat(layout string) *ConsoleExporter {
+	e.timeFormat = &layout
+
+	return e
+}
+
+// UTC controls if the timestamp of the console logs is written in UTC instead of local time (default: false)
+func (e *ConsoleExporter) UTC(v bool) *ConsoleExporter {
+	e.utc = v
+
+	return e
+}
+
+// MinLevel sets the minimum level of the child logs written to the console, e.g. slog.LevelInfo hides
+// Debug logs, and LevelTrace shows Trace logs. The parent request log is always written. (default: slog.LevelDebug)
+func (e *ConsoleExporter) MinLevel(level slog.Level) *ConsoleExporter {
+	e.SetMinLevel(level)
+
+	return e
+}
+
+// SetMinLevel changes the minimum level of the child logs written to the console, and is safe to call at
+// runtime after the Middleware is created. The level applies to the following requests.
+func (e *ConsoleExporter) SetMinLevel(level slog.Level) {
+	e.setMinLevel(level)
+}
+
+// RequestFormat sets the function that formats the parent request log line of TextFormat, e.g.
+// CombinedRequestFormat. The trace and request attributes are appended to the line. (default: DefaultRequestFormat)
+func (e *ConsoleExporter) RequestFormat(f func(ConsoleRequest) string) *ConsoleExporter {
+	e.reqFormat = f
+
+	return e
+}
+
+// AuditWriter sets the destination of audit events written with Logger.Audit, which are written in the
+// logfmt format (default: the Writer of the request logs)
+func (e *ConsoleExporter) AuditWriter(w io.Writer) *ConsoleExporter {
+	e.auditWriter = w
+
+	return e
+}
+
 // Middleware returns a middleware that exports logs to the console
 func (e *ConsoleExporter) Middleware() func(http.Handler) http.Handler {
+	cfg := *e // options changed after the middleware is created are not applied
+	out := cfg.logger()
+
+	var structured *slog.Logger
+	switch cfg.format {
+	case JSONFormat:
+		structured = slog.New(slog.NewJSONHandler(out.Writer(), &slog.HandlerOptions{Level: LevelTrace, ReplaceAttr: cfg.replaceAttr}))
+	case LogfmtFormat:
+		structured = slog.New(slog.NewTextHandler(out.Writer(), &slog.HandlerOptions{Level: LevelTrace, ReplaceAttr: cfg.replaceAttr}))
+	}
+
+	var timestamp func(time.Time) string
+	if cfg.timeFormat != nil {
+		timestamp = cfg.timestamp
+	}
+
+	auditOut := cfg.auditWriter
+	if auditOut == nil {
+		auditOut = out.Writer()
+	}
+	audit := newAuditChain(slogAuditWriter(slog.New(slog.NewTextHandler(auditOut, nil)), cslTraceID))
+
 	return func(next http.Handler) http.Handler {
 		return &consoleHandler{
-			next:    next,
-			noColor: e.noColor,
+			handlerOptions: cfg.handlerOptions(consolePropagator, audit),
+			next:           next,
+			noColor:        cfg.noColor,
+			out:            out,
+			structured:     structured,
+			timestamp:      timestamp,
+			reqFormat:      cfg.reqFormat,
+			pretty:         cfg.format == PrettyFormat,
+		}
+	}
+}
+
+// logger returns the log.Logger that console logs are written to
+func (e *ConsoleExporter) logger() *log.Logger {
+	w := e.writer
+	if w == nil {
+		if e.timeFormat == nil && !e.utc {
+			return log.Default()
+		}
+		w = log.Writer()
+	}
+
+	flags := log.LstdFlags
+	switch {
+	case e.timeFormat != nil:
+		flags = 0 // the timestamp is written by the consoleLogger
+	case e.utc:
+		flags |= log.LUTC
+	}
+
+	return log.New(w, "", flags)
+}
+
+// timestamp returns t formatted with the configured layout
+func (e *ConsoleExporter) timestamp(t time.Time) string {
+	if e.utc {
+		t = t.UTC()
+	}
+
+	return t.Format(*e.timeFormat)
+}
+
+// replaceAttr applies the timestamp and level options to the JSONFormat and LogfmtFormat built-in attributes
+func (e *ConsoleExporter) replaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) != 0 {
+		return a
+	}
+
+	switch a.Key {
+	case slog.TimeKey:
+		switch {
+		case e.timeFormat == nil:
+			if e.utc {
+				a.Value = slog.TimeValue(a.Value.Time().UTC())
+			}
+		case *e.timeFormat == "":
+			return slog.Attr{}
+		default:
+			a.Value = slog.StringValue(e.timestamp(a.Value.Time()))
+		}
+	case slog.LevelKey:
+		if level, ok := a.Value.Any().(slog.Level); ok {
+			a.Value = slog.StringValue(levelName(level))
+		}
+		if e.format == LogfmtFormat {
+			// logfmt convention is a lowercase level (level=info)
+			a.Value = slog.StringValue(strings.ToLower(a.Value.String()))
 		}
 	}
+
+	return a
 }
 
 type consoleHandler struct {
-	next    http.Handler
-	noColor bool
+	handlerOptions
+	next       http.Handler
+	noColor    bool
+	out        *log.Logger
+	structured *slog.Logger                // nil for TextFormat
+	timestamp  func(time.Time) string      // nil when the timestamp is written by out
+	reqFormat  func(ConsoleRequest) string // nil for DefaultRequestFormat
+	pretty     bool
 }
 
 func (c *consoleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	begin := time.Now()
-	l := newConsoleLogger(r, c.noColor)
-	r = r.WithContext(newContext(r.Context(), l))
+	r, span := startServerSpan(r, c.tracing, c.propagator)
+	minLevel, logAll := c.route(r)
+	l := c.newLogger(r, traceIDFromRequest(r, c.propagator, generateID), minLevel)
+	r, task := startExecutionTask(r, c.execTrace, l.traceID)
+	r, reqBody, disconnect := c.startRequest(w, r, l, begin)
 	sw := newResponseRecorder(w)
 
-	c.next.ServeHTTP(sw, r)
+	if v := c.serve(c.next, sw, r, l); v != nil {
+		defer panic(v)
+	}
+	endServerSpan(span, sw.Status())
+	endExecutionTask(task)
+	grpcLevel, ok := c.endRequest(r, sw, l, disconnect, begin)
+	if !ok {
+		return
+	}
 
-	l.mu.Lock()
-	logCount := l.logCount
-	maxSeverity := l.maxSeverity
-	attributes := l.reqAttributes
-	l.mu.Unlock()
+	logCount, maxSeverity, attributes := l.parentAttributes(max(statusLevel(c.statusLevel, sw.Status()), grpcLevel))
 
-	// status code should also set the minimum maxSeverity to Error
-	if sw.Status() > 499 && maxSeverity < logging.Error {
-		maxSeverity = logging.Error
+	entry, ok := c.parentLog(r, Entry{Time: begin, Level: severityLevel(maxSeverity), Attributes: attributes, TraceID: l.traceID, Request: true, Status: sw.Status()}, logAll, logCount)
+	if !ok {
+		return
 	}
+	maxSeverity, attributes = levelSeverity(entry.Level), entry.Attributes
+
+	if c.structured != nil {
+		attrs := []slog.Attr{
+			slog.String(cslMethod, r.Method),
+			slog.String(cslPath, r.URL.Path),
+			slog.Int(cslStatus, sw.Status()),
+			slog.Duration(cslElapsed, time.Since(begin)),
+			slog.Int64(cslReqSize, reqBody.size(r)),
+			slog.Int64(cslRespSize, sw.Length()),
+			slog.Int(cslLogCount, logCount),
+		}
+		attrs = append(attrs, l.traceAttributes(r.Context())...)
+		attrs = append(attrs, consoleAttributes(attributes)...)
+		c.structured.LogAttrs(r.Context(), severityLevel(maxSeverity), r.Method+" "+r.URL.Path, attrs...)
 
-	msg := fmt.Sprintf("%s %s %d %s %s=%d %s=%d %s=%d", r.Method, r.URL.Path, sw.Status(), time.Since(begin),
-		cslReqSize, requestSize(r.Header.Get("Content-Length")), cslRespSize, sw.Length(), cslLogCount, logCount,
-	)
+		return
+	}
+
+	reqFormat := c.reqFormat
+	if reqFormat == nil {
+		reqFormat = DefaultRequestFormat
+	}
+	msg := reqFormat(ConsoleRequest{
+		Request:      scrubQuery(r, c.scrubParams),
+		Begin:        begin,
+		Elapsed:      time.Since(begin),
+		Status:       sw.Status(),
+		RequestSize:  reqBody.size(r),
+		ResponseSize: sw.Length(),
+		LogCount:     logCount,
+		RemoteIP:     remoteIP(r, c.proxies),
+	})
+	if c.pretty {
+		attrs := append(l.traceAttributes(r.Context()), consoleAttributes(attributes)...)
+		l.print(maxSeverity, severityColor(maxSeverity), sanitize(msg, `\n`)+prettyAttributes(attrs, c.noColor))
+
+		return
+	}
+
+	for _, a := range l.traceAttributes(r.Context()) {
+		msg += fmt.Sprintf(" %s=%v", a.Key, a.Value)
+	}
 	for k, v := range attributes {
-		msg += fmt.Sprintf(" %s=%v", k, v)
+		msg += fmt.Sprintf(" %s=%v", k, slog.AnyValue(v).Resolve())
 	}
-	l.console(maxSeverity, severityColor(maxSeverity), msg)
+	l.print(maxSeverity, severityColor(maxSeverity), sanitize(msg, `\n`))
+}
+
+// newLogger returns the root logger of a request, with the minimum level of the route, nil for MinLevel
+func (c *consoleHandler) newLogger(r *http.Request, traceID string, minLevel *slog.Level) *consoleLogger {
+	l := newConsoleLogger(r, c.noColor, c.out, traceID)
+	l.structured = c.structured
+	l.timestamp = c.timestamp
+	l.minSeverity = minSeverity(c.minLevelVar)
+	if minLevel != nil {
+		l.minSeverity = levelSeverity(*minLevel)
+	}
+	l.pretty = c.pretty
+	l.errorStack = c.errorStack
+	l.spanEvents = c.spanEvents
+	l.metrics = c.metrics
+	l.caller, l.callerSkip = c.caller, c.callerSkip
+	l.compLevels = c.compLevels
+	l.dedupe = newDeduper(c.dedupe)
+	l.errSummary = newErrorSummary(c.parentErrs)
+	l.levelCounts = newLevelCounter(c.levelCounts)
+	l.maxLogs = c.maxLogs
+	l.attrFilter = c.attrFilter
+	l.processors = c.processors
+
+	return l
 }
 
 type consoleLogger struct {
 	root          *consoleLogger
 	r             *http.Request
+	traceID       string
 	noColor       bool
+	out           *log.Logger
+	structured    *slog.Logger           // nil for TextFormat
+	timestamp     func(time.Time) string // nil when the timestamp is written by out
+	minSeverity   logging.Severity       // child logs below minSeverity are dropped
+	pretty        bool                   // attributes are written indented across multiple lines
+	errorStack    bool                   // Error and above child logs include the stack trace
+	spanEvents    bool                   // child logs are added as events of the span of the context
+	metrics       MetricsRecorder        // child logs are recorded by level, nil when disabled
+	caller        bool                   // child logs include the source of the caller
+	callerSkip    int                    // additional frames skipped to find the caller
+	callerPkg     string                 // function name prefix of the adapted library, whose first frames are skipped to find the caller
+	component     string                 // name of the component, empty when not Named
+	compLevels    map[string]slog.Level
+	dedupe        *deduper      // shared by the logger and its children, nil when disabled
+	errSummary    *errorSummary // shared by the logger and its children, nil when disabled
+	attrFilter    *attrFilter
+	processors    processors
 	rsvdReqKeys   []string
 	attributes    map[string]any // attributes for child (trace) logs
 	mu            sync.Mutex
 	maxSeverity   logging.Severity
 	logCount      int
-	reqAttributes map[string]any // attributes for the parent request log
+	maxLogs       int                // child logs after maxLogs are dropped, 0 for unlimited
+	dropped       int                // number of child logs dropped by maxLogs
+	levelCounts   *levelCounter      // child logs counted by level for the parent log, nil when disabled
+	disconnect    *disconnectWatcher // watches the client of the request, nil outside of requests
+	reqAttributes map[string]any     // attributes for the parent request log
 }
 
 // newConsoleLogger logs all output to console
-func newConsoleLogger(r *http.Request, noColor bool) *consoleLogger {
+func newConsoleLogger(r *http.Request, noColor bool, out *log.Logger, traceID string) *consoleLogger {
 	l := &consoleLogger{
-		r: r, noColor: noColor,
-		rsvdReqKeys:   []string{cslReqSize, cslRespSize, cslLogCount},
+		r: r, noColor: noColor, out: out, traceID: traceID,
+		rsvdReqKeys:   []string{cslReqSize, cslRespSize, cslLogCount, cslMethod, cslPath, cslStatus, cslElapsed, cslTraceID, cslSpanID},
 		maxSeverity:   logging.Info,
 		reqAttributes: make(map[string]any),
 		attributes:    make(map[string]any),
@@ -119,7 +459,25 @@ func (l *consoleLogger) newChild() *consoleLogger {
 	return &consoleLogger{
 		root:          l.root,
 		r:             l.r,
+		traceID:       l.traceID,
 		noColor:       l.noColor,
+		out:           l.out,
+		structured:    l.structured,
+		timestamp:     l.timestamp,
+		minSeverity:   l.minSeverity,
+		pretty:        l.pretty,
+		errorStack:    l.errorStack,
+		spanEvents:    l.spanEvents,
+		metrics:       l.metrics,
+		caller:        l.caller,
+		callerSkip:    l.callerSkip,
+		callerPkg:     l.callerPkg,
+		component:     l.component,
+		compLevels:    l.compLevels,
+		dedupe:        l.dedupe,
+		errSummary:    l.errSummary,
+		attrFilter:    l.attrFilter,
+		processors:    l.processors,
 		rsvdReqKeys:   l.rsvdReqKeys,
 		maxSeverity:   logging.Debug,
 		attributes:    make(map[string]any),
@@ -127,46 +485,74 @@ func (l *consoleLogger) newChild() *consoleLogger {
 	}
 }
 
+// Trace logs a trace message.
+func (l *consoleLogger) Trace(ctx context.Context, v any) {
+	l.console(ctx, consoleTrace, gray, fmt.Sprint(v))
+}
+
+// Tracef logs a trace message with format.
+func (l *consoleLogger) Tracef(ctx context.Context, format string, v ...any) {
+	l.console(ctx, consoleTrace, gray, fmt.Sprintf(format, v...))
+}
+
 // Debug logs a debug message.
-func (l *consoleLogger) Debug(_ context.Context, v any) {
-	l.console(logging.Debug, gray, fmt.Sprint(v))
+func (l *consoleLogger) Debug(ctx context.Context, v any) {
+	l.console(ctx, logging.Debug, gray, fmt.Sprint(v))
 }
 
 // Debugf logs a debug message with format.
-func (l *consoleLogger) Debugf(_ context.Context, format string, v ...any) {
-	l.console(logging.Debug, gray, fmt.Sprintf(format, v...))
+func (l *consoleLogger) Debugf(ctx context.Context, format string, v ...any) {
+	l.console(ctx, logging.Debug, gray, fmt.Sprintf(format, v...))
 }
 
 // Info logs a info message.
-func (l *consoleLogger) Info(_ context.Context, v any) {
-	l.console(logging.Info, blue, fmt.Sprint(v))
+func (l *consoleLogger) Info(ctx context.Context, v any) {
+	l.console(ctx, logging.Info, blue, fmt.Sprint(v))
 }
 
 // Infof logs a info message with format.
-func (l *consoleLogger) Infof(_ context.Context, format string, v ...any) {
-	l.console(logging.Info, blue, fmt.Sprintf(format, v...))
+func (l *consoleLogger) Infof(ctx context.Context, format string, v ...any) {
+	l.console(ctx, logging.Info, blue, fmt.Sprintf(format, v...))
 }
 
 // Warn logs a warning message.
-func (l *consoleLogger) Warn(_ context.Context, v any) {
-	l.console(logging.Warning, yellow, fmt.Sprint(v))
+func (l *consoleLogger) Warn(ctx context.Context, v any) {
+	l.console(ctx, logging.Warning, yellow, fmt.Sprint(v))
 }
 
 // Warnf logs a warning message with format.
-func (l *consoleLogger) Warnf(_ context.Context, format string, v ...any) {
-	l.console(logging.Warning, yellow, fmt.Sprintf(format, v...))
+func (l *consoleLogger) Warnf(ctx context.Context, format string, v ...any) {
+	l.console(ctx, logging.Warning, yellow, fmt.Sprintf(format, v...))
 }
 
 // Error logs an error message.
-func (l *consoleLogger) Error(_ context.Context, v any) {
-	l.console(logging.Error, red, fmt.Sprint(v))
+func (l *consoleLogger) Error(ctx context.Context, v any) {
+	l.console(ctx, logging.Error, red, fmt.Sprint(v))
 }
 
 // Errorf logs an error message with format.
-func (l *consoleLogger) Errorf(_ context.Context, format string, v ...any) {
-	l.console(logging.Error, red, fmt.Sprintf(format, v...))
+func (l *consoleLogger) Errorf(ctx context.Context, format string, v ...any) {
+	l.console(ctx, logging.Error, red, fmt.Sprintf(format, v...))
+}
+
+// Critical logs a critical message.
+func (l *consoleLogger) Critical(ctx context.Context, v any) {
+	l.console(ctx, logging.Critical, red, fmt.Sprint(v))
+}
+
+// Criticalf logs a critical message with format.
+func (l *consoleLogger) Criticalf(ctx context.Context, format string, v ...any) {
+	l.console(ctx, logging.Critical, red, fmt.Sprintf(format, v...))
 }
 
+// Enabled reports whether logs at the level are written, which is false below the minimum level
+func (l *consoleLogger) Enabled(_ context.Context, level Level) bool {
+	return l.enabled(levelSeverity(level))
+}
+
+// Flush is a no-op, the console logs are not buffered
+func (l *consoleLogger) Flush() {}
+
 // AddRequestAttribute adds an attribute (key, value) for the parent request log
 // If the key matches a reserved key, it will be prefixed with "custom_"
 // If the key already exists, its value is overwritten
@@ -180,6 +566,25 @@ func (l *consoleLogger) AddRequestAttribute(key string, value any) {
 	l.root.reqAttributes[key] = value
 }
 
+// addStaticAttribute adds an attribute (key, value) for the child (trace) logs and the parent request log
+func (l *consoleLogger) addStaticAttribute(key string, value any) {
+	(&consoleAttributer{logger: l, attributes: l.attributes}).AddAttribute(key, value)
+	l.AddRequestAttribute(key, value)
+}
+
+// setDisconnect sets the watcher of the client of the request
+func (l *consoleLogger) setDisconnect(w *disconnectWatcher) {
+	l.disconnect = w
+}
+
+// requestAttribute returns the value of the attribute of the parent request log
+func (l *consoleLogger) requestAttribute(key string) any {
+	l.root.mu.Lock()
+	defer l.root.mu.Unlock()
+
+	return l.root.reqAttributes[key]
+}
+
 // WithAttributes returns an attributer that can be used to add child (trace) log attributes
 func (l *consoleLogger) WithAttributes() attributer {
 	attrs := make(map[string]any)
@@ -190,37 +595,247 @@ func (l *consoleLogger) WithAttributes() attributer {
 	return &consoleAttributer{logger: l, attributes: attrs}
 }
 
-// TraceID returns an empty string for the console logger
-func (l *consoleLogger) TraceID() string {
-	return ""
+// withCaller returns a child ctxLogger that skips skip more frames, and the first frames with the function
+// name prefix pkg, to find the caller
+func (l *consoleLogger) withCaller(skip int, pkg string) ctxLogger {
+	child := l.newChild()
+	for k, v := range l.attributes {
+		child.attributes[k] = v
+	}
+	child.callerSkip += skip
+	if pkg != "" {
+		child.callerPkg = pkg
+	}
+
+	return child
 }
 
-func (l *consoleLogger) console(level logging.Severity, c color, msg string) {
+// Named returns a child ctxLogger for the component, with the minimum level of the component
+func (l *consoleLogger) Named(name string) ctxLogger {
+	child := l.newChild()
 	for k, v := range l.attributes {
-		msg += fmt.Sprintf(", %s=%v", k, v)
+		child.attributes[k] = v
+	}
+	child.component = componentName(l.component, name)
+	child.attributes[componentKey] = child.component
+	child.minSeverity = l.root.minSeverity
+	if level, ok := componentLevel(l.compLevels, child.component); ok {
+		child.minSeverity = levelSeverity(level)
 	}
 
-	log.Printf(l.colorPrint(level, c)+": %s", msg)
+	return child
 }
 
-func (l *consoleLogger) colorPrint(level logging.Severity, c color) string {
-	l.root.mu.Lock()
-	if l.root.maxSeverity < level {
-		l.root.maxSeverity = level
+// enabled reports whether child logs of the severity are written, which is false below the
+// level of the component set with SetLevel, or the minimum level of the logger
+func (l *consoleLogger) enabled(severity logging.Severity) bool {
+	if level, ok := registeredLevel(l.component); ok {
+		return severity >= levelSeverity(level)
+	}
+
+	return severity >= l.minSeverity
+}
+
+// TraceID returns the trace ID of the request logs
+func (l *consoleLogger) TraceID() string {
+	return l.traceID
+}
+
+// RawTraceID returns the trace ID of the request logs
+func (l *consoleLogger) RawTraceID() string {
+	return l.traceID
+}
+
+func (l *consoleLogger) console(ctx context.Context, level logging.Severity, c color, msg string) {
+	if severity := l.root.disconnect.downgradeSeverity(level); severity != level {
+		level, c = severity, severityColor(severity)
+	}
+	if !l.enabled(level) {
+		return
+	}
+
+	ok, suppressed := l.dedupe.allow(fmt.Sprint(level, msg, l.attributes), time.Now())
+	if !ok {
+		return
+	}
+
+	entry, ok := l.processors.process(Entry{Level: severityLevel(level), Message: msg, Attributes: l.attrFilter.apply(l.attributes), Component: l.component, TraceID: l.traceID})
+	if !ok {
+		return
+	}
+	if severity := levelSeverity(entry.Level); severity != level {
+		level, c = severity, severityColor(severity)
+	}
+	msg, attributes := entry.Message, entry.Attributes
+
+	if !l.root.count(level) {
+		return
+	}
+
+	if l.metrics != nil {
+		l.metrics.RecordLog(severityLevel(level))
+	}
+	if level >= logging.Error {
+		l.errSummary.add(msg)
+	}
+	if l.spanEvents {
+		addSpanEvent(ctx, severityLevel(level), msg, attributes)
+	}
+
+	var source, stack string
+	if l.caller {
+		if frame, ok := caller(l.callerSkip, l.callerPkg); ok {
+			source = fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+	}
+	if l.errorStack && level >= logging.Error {
+		stack = callerStack()
+	}
+
+	if l.structured != nil || l.pretty {
+		attrs := append(l.traceAttributes(ctx), consoleAttributes(attributes)...)
+		if source != "" {
+			attrs = append(attrs, slog.String(slog.SourceKey, source))
+		}
+		if stack != "" {
+			attrs = append(attrs, slog.String(stackTraceKey, stack))
+		}
+		if suppressed > 0 {
+			attrs = append(attrs, slog.Int(suppressedCountKey, suppressed))
+		}
+		if l.structured != nil {
+			l.structured.LogAttrs(ctx, severityLevel(level), msg, attrs...)
+		} else {
+			l.print(level, c, sanitize(msg, `\n`)+prettyAttributes(attrs, l.noColor))
+		}
+
+		return
 	}
-	l.root.logCount++
-	l.root.mu.Unlock()
 
+	l.print(level, c, l.text(ctx, msg, attributes, source, stack, suppressed))
+}
+
+// text returns the message of a child log for the text format, followed by its attributes as key=value pairs,
+// and the stack trace on the lines that follow
+func (l *consoleLogger) text(ctx context.Context, msg string, attributes map[string]any, source, stack string, suppressed int) string {
+	for k, v := range attributes {
+		msg += fmt.Sprintf(", %s=%v", k, slog.AnyValue(v).Resolve())
+	}
+	for _, a := range l.traceAttributes(ctx) {
+		msg += fmt.Sprintf(", %s=%v", a.Key, a.Value)
+	}
+	msg = sanitize(msg, `\n`)
+	if source != "" {
+		msg += fmt.Sprintf(", %s=%s", slog.SourceKey, source)
+	}
+	if suppressed > 0 {
+		msg += fmt.Sprintf(", %s=%d", suppressedCountKey, suppressed)
+	}
+	if stack != "" {
+		msg += "\n" + strings.TrimSuffix(stack, "\n")
+	}
+
+	return msg
+}
+
+// count records a child log of the severity in the root logger, and returns false when the request reached the
+// MaxLogs limit and the log is dropped
+func (l *consoleLogger) count(severity logging.Severity) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.maxSeverity < severity {
+		l.maxSeverity = severity
+	}
+	if l.maxLogs > 0 && l.logCount >= l.maxLogs {
+		l.dropped++
+
+		return false
+	}
+	l.logCount++
+	l.levelCounts.add(severityLevel(severity))
+
+	return true
+}
+
+// parentAttributes raises the maximum severity of the child logs to the level of the status, and returns the number
+// of child logs, their maximum severity, and the attributes of the parent request log
+func (l *consoleLogger) parentAttributes(status slog.Level) (int, logging.Severity, map[string]any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if severity := levelSeverity(status); l.maxSeverity < severity {
+		l.maxSeverity = severity
+	}
+	attributes := make(map[string]any)
+	maps.Copy(attributes, l.attrFilter.apply(l.reqAttributes))
+	if n := l.dedupe.suppressed(); n > 0 {
+		attributes[suppressedCountKey] = n
+	}
+	if errs, n := l.errSummary.summary(); n > 0 {
+		attributes[errorsKey], attributes[errorsCountKey] = errs, n
+	}
+	for _, a := range l.levelCounts.attrs() {
+		attributes[a.Key] = a.Value.Any()
+	}
+
+	return l.logCount, l.maxSeverity, attributes
+}
+
+// stopLimit removes the limit of child logs at the end of the request, and returns the number of logs dropped
+func (l *consoleLogger) stopLimit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxLogs = 0
+
+	return l.dropped
+}
+
+// traceAttributes returns the trace ID, and the span ID of the span in ctx when it is valid
+func (l *consoleLogger) traceAttributes(ctx context.Context) []slog.Attr {
+	if l.traceID == "" {
+		return nil
+	}
+
+	attrs := []slog.Attr{slog.String(cslTraceID, l.traceID)}
+	if sc := trace.SpanFromContext(ctx).SpanContext(); sc.HasSpanID() {
+		attrs = append(attrs, slog.String(cslSpanID, sc.SpanID().String()))
+	}
+
+	return attrs
+}
+
+// print writes the text formatted log line
+func (l *consoleLogger) print(level logging.Severity, c color, msg string) {
+	prefix := l.colorPrint(level, c)
+	if l.timestamp != nil {
+		if ts := l.timestamp(time.Now()); ts != "" {
+			prefix = ts + " " + prefix
+		}
+	}
+
+	l.out.Printf(prefix+": %s", msg)
+}
+
+func (l *consoleLogger) colorPrint(level logging.Severity, c color) string {
 	strLevel := strings.ToUpper(level.String())
-	if level == logging.Warning {
+	switch level {
+	case logging.Warning, logging.Critical:
 		strLevel = strLevel[:4]
+	case consoleTrace:
+		strLevel = "TRACE"
 	}
 
 	if l.noColor {
 		return fmt.Sprintf("%-5s", strLevel)
 	}
 
-	return fmt.Sprintf("%s%-5s%s", string([]byte{0x1b, '[', byte('0' + c/10), byte('0' + c%10), 'm'}), strLevel, "\x1b[0m")
+	return colorString(c, fmt.Sprintf("%-5s", strLevel))
+}
+
+// colorString wraps s in the ANSI escape codes for color c
+func colorString(c color, s string) string {
+	return string([]byte{0x1b, '[', byte('0' + c/10), byte('0' + c%10), 'm'}) + s + "\x1b[0m"
 }
 
 var _ attributer = (*consoleAttributer)(nil)
@@ -246,9 +861,20 @@ func (a *consoleAttributer) Logger() ctxLogger {
 	return l
 }
 
+// consoleAttributes returns a slice of slog.Attr for the attributes, sorted by key
+func consoleAttributes(attributes map[string]any) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(attributes))
+	for k, v := range attributes {
+		attrs = append(attrs, slog.Any(k, v))
+	}
+	slices.SortFunc(attrs, func(a, b slog.Attr) int { return strings.Compare(a.Key, b.Key) })
+
+	return attrs
+}
+
 func severityColor(level logging.Severity) color {
 	switch level {
-	case logging.Error:
+	case logging.Error, logging.Critical:
 		return red
 	case logging.Warning:
 		return yellow
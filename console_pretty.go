@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// prettyMaxDepth limits the nesting rendered by prettyValue, protecting against reference cycles
+const prettyMaxDepth = 10
+
+// prettyAttributes renders the attributes for PrettyFormat, one per line and indented, with
+// nested maps, structs and slices expanded
+func prettyAttributes(attrs []slog.Attr, noColor bool) string {
+	var b strings.Builder
+	for _, a := range attrs {
+		prettyValue(&b, a.Key, reflect.ValueOf(a.Value.Any()), 1, noColor)
+	}
+
+	return b.String()
+}
+
+// prettyValue writes key and the value of v on a new line indented for depth. Maps (sorted by key),
+// exported struct fields, and slice elements are written on the lines that follow, indented one level deeper.
+func prettyValue(b *strings.Builder, key string, v reflect.Value, depth int, noColor bool) {
+	key = sanitize(key, `\n`)
+	if !noColor {
+		key = colorString(cyan, key)
+	}
+	b.WriteString("\n" + strings.Repeat("  ", depth) + key + ":")
+	newline := "\n" + strings.Repeat("  ", depth+1) // lines of multi-line values are indented below the key
+
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && !v.IsNil() {
+		if s, ok := prettyString(v); ok {
+			b.WriteString(" " + sanitize(s, newline))
+
+			return
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() || ((v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil()) {
+		b.WriteString(" <nil>")
+
+		return
+	}
+	if s, ok := prettyString(v); ok {
+		b.WriteString(" " + sanitize(s, newline))
+
+		return
+	}
+	if depth >= prettyMaxDepth {
+		b.WriteString(" ...")
+
+		return
+	}
+
+	prettyElements(b, v, depth, noColor, newline)
+}
+
+// prettyElements writes the map entries, struct fields or slice elements of v, each on its own line
+// indented for depth+1. Other values are written after the key, with their lines indented by newline.
+func prettyElements(b *strings.Builder, v reflect.Value, depth int, noColor bool, newline string) {
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Len() == 0 {
+			b.WriteString(" {}")
+
+			return
+		}
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int { return strings.Compare(fmt.Sprint(a), fmt.Sprint(b)) })
+		for _, k := range keys {
+			prettyValue(b, fmt.Sprint(k), v.MapIndex(k), depth+1, noColor)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.IsExported() {
+				prettyValue(b, f.Name, v.Field(i), depth+1, noColor)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			b.WriteString(" " + sanitize(string(v.Bytes()), newline))
+
+			return
+		}
+		if v.Len() == 0 {
+			b.WriteString(" []")
+
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			prettyValue(b, fmt.Sprintf("[%d]", i), v.Index(i), depth+1, noColor)
+		}
+	default:
+		b.WriteString(" " + sanitize(fmt.Sprint(v.Interface()), newline))
+	}
+}
+
+// prettyString returns the string of v if it implements error or fmt.Stringer
+func prettyString(v reflect.Value) (string, bool) {
+	if !v.CanInterface() {
+		return "", false
+	}
+
+	switch x := v.Interface().(type) {
+	case error:
+		return x.Error(), true
+	case fmt.Stringer:
+		return x.String(), true
+	}
+
+	return "", false
+}
@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func Test_prettyAttributes(t *testing.T) {
+	t.Parallel()
+
+	type inner struct {
+		Name  string
+		Tags  []string
+		score int
+	}
+	type outer struct {
+		ID    int
+		Inner *inner
+		Empty map[string]int
+	}
+
+	tests := []struct {
+		name    string
+		attrs   []slog.Attr
+		noColor bool
+		want    string
+	}{
+		{
+			name:    "scalars",
+			attrs:   []slog.Attr{slog.String("a", "b"), slog.Int("c", 1), slog.Any("d", nil)},
+			noColor: true,
+			want:    "\n  a: b\n  c: 1\n  d: <nil>",
+		},
+		{
+			name: "nested map",
+			attrs: []slog.Attr{slog.Any("payload", map[string]any{
+				"z": 1,
+				"a": map[string]any{"b": true},
+			})},
+			noColor: true,
+			want:    "\n  payload:\n    a:\n      b: true\n    z: 1",
+		},
+		{
+			name:    "struct with slice and pointer",
+			attrs:   []slog.Attr{slog.Any("user", outer{ID: 7, Inner: &inner{Name: "n", Tags: []string{"x", "y"}, score: 3}})},
+			noColor: true,
+			want:    "\n  user:\n    ID: 7\n    Inner:\n      Name: n\n      Tags:\n        [0]: x\n        [1]: y\n    Empty: {}",
+		},
+		{
+			name:    "error, stringer and bytes",
+			attrs:   []slog.Attr{slog.Any("err", errors.New("boom")), slog.Any("url", &url.URL{Scheme: "https", Host: "a.b"}), slog.Any("raw", []byte("hi"))},
+			noColor: true,
+			want:    "\n  err: boom\n  url: https://a.b\n  raw: hi",
+		},
+		{
+			name:    "control characters",
+			attrs:   []slog.Attr{slog.String("a\nb", "line 1\nline 2\x1b[2J")},
+			noColor: true,
+			want:    "\n  a\\nb: line 1\n    line 2\\x1b[2J",
+		},
+		{
+			name:  "color",
+			attrs: []slog.Attr{slog.String("a", "b")},
+			want:  "\n  \x1b[36ma\x1b[0m: b",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := prettyAttributes(tt.attrs, tt.noColor); got != tt.want {
+				t.Errorf("prettyAttributes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_prettyAttributes_cycle(t *testing.T) {
+	t.Parallel()
+
+	type node struct{ Next *node }
+	n := &node{}
+	n.Next = n
+
+	got := prettyAttributes([]slog.Attr{slog.Any("node", n)}, true)
+	want := "\n  node:"
+	for depth := 2; depth <= prettyMaxDepth; depth++ {
+		want += "\n" + strings.Repeat("  ", depth) + "Next:"
+	}
+	want += " ..."
+	if got != want {
+		t.Errorf("prettyAttributes() = %q, want %q", got, want)
+	}
+}
@@ -3,21 +3,27 @@ package logger
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
-	"os"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"cloud.google.com/go/logging"
 	"github.com/go-test/deep"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestNewConsoleExporter(t *testing.T) {
@@ -29,7 +35,12 @@ func TestNewConsoleExporter(t *testing.T) {
 	}{
 		{
 			name: "Simple Constructor",
-			want: &ConsoleExporter{},
+			want: func() *ConsoleExporter {
+				e := &ConsoleExporter{}
+				e.exporterOptions = exporterOptions[*ConsoleExporter]{self: e, logAll: true, minLevelVar: newLevelVar(slog.LevelDebug)}
+
+				return e
+			}(),
 		},
 	}
 	for _, tt := range tests {
@@ -97,6 +108,442 @@ func TestConsoleExporter_NoColor(t *testing.T) {
 	}
 }
 
+func TestConsoleExporter_Writer(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	e := NewConsoleExporter().NoColor(true).Writer(&buf)
+	if e.writer != &buf {
+		t.Fatalf("ConsoleExporter.Writer() writer = %v, want %v", e.writer, &buf)
+	}
+
+	handler := e.Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		Req(r).Info("some log")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/path", http.NoBody))
+
+	for _, want := range []string{"INFO : some log, traceID=", "INFO : GET /path 200 "} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("ConsoleExporter.Writer() output = %q, missing %q", buf.String(), want)
+		}
+	}
+}
+
+func TestConsoleExporter_Format(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	e := NewConsoleExporter().Writer(&buf).Format(JSONFormat)
+	if e.format != JSONFormat {
+		t.Fatalf("ConsoleExporter.Format() format = %v, want %v", e.format, JSONFormat)
+	}
+
+	handler := e.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Req(r).AddRequestAttribute("test_req_key", "test_req_value")
+		Req(r).WithAttributes().AddAttribute("test_key", "test_value").Logger().Warn("some log")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/path", http.NoBody))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("ConsoleExporter.Format() output = %q, want 2 lines", buf.String())
+	}
+
+	var child, parent map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &child); err != nil {
+		t.Fatalf("json.Unmarshal() child error = %v", err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &parent); err != nil {
+		t.Fatalf("json.Unmarshal() parent error = %v", err)
+	}
+
+	if child["traceID"] == nil || child["traceID"] != parent["traceID"] {
+		t.Errorf("ConsoleExporter.Format() child traceID = %v, parent traceID = %v", child["traceID"], parent["traceID"])
+	}
+
+	wantChild := map[string]any{"level": "WARN", "msg": "some log", "test_key": "test_value"}
+	if diff := cmp.Diff(child, wantChild, cmpopts.IgnoreMapEntries(func(k string, _ any) bool { return k == "time" || k == "traceID" })); diff != "" {
+		t.Errorf("ConsoleExporter.Format() child mismatch (-want +got):\n%s", diff)
+	}
+
+	wantParent := map[string]any{
+		"level": "WARN", "msg": "GET /path", "method": "GET", "path": "/path", "status": float64(http.StatusCreated),
+		"requestSize": float64(0), "responseSize": float64(0), "logCount": float64(1), "test_req_key": "test_req_value",
+	}
+	if diff := cmp.Diff(parent, wantParent, cmpopts.IgnoreMapEntries(func(k string, _ any) bool { return k == "time" || k == "elapsed" || k == "traceID" })); diff != "" {
+		t.Errorf("ConsoleExporter.Format() parent mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestConsoleExporter_TraceIDHeader(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	e := NewConsoleExporter().Writer(&buf).Format(JSONFormat).TraceIDHeader("X-Trace-Id")
+
+	var traceID string
+	handler := e.Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		traceID = Req(r).TraceID()
+	}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/path", http.NoBody))
+
+	if traceID == "" {
+		t.Fatal("Logger.TraceID() is empty")
+	}
+	if got := w.Header().Get("X-Trace-Id"); got != traceID {
+		t.Errorf("ConsoleExporter.TraceIDHeader() header = %q, want %q", got, traceID)
+	}
+
+	var parent map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &parent); err != nil {
+		t.Fatalf("json.Unmarshal() parent error = %v", err)
+	}
+	if parent["traceID"] != traceID {
+		t.Errorf("ConsoleExporter.TraceIDHeader() parent traceID = %v, want %v", parent["traceID"], traceID)
+	}
+}
+
+func TestConsoleExporter_SpanEvents(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+	ctx, span := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test").Start(context.Background(), "request")
+
+	e := NewConsoleExporter().Writer(io.Discard).Format(JSONFormat).SpanEvents(true)
+	handler := e.Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		Req(r).With("user", "42").Info("signed in")
+		Req(r).Error("query failed")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/path", http.NoBody).WithContext(ctx))
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("ConsoleExporter.SpanEvents() spans = %d, want 1", len(spans))
+	}
+	var names []string
+	for _, event := range spans[0].Events() {
+		names = append(names, event.Name)
+	}
+	if diff := cmp.Diff([]string{"signed in", "query failed"}, names); diff != "" {
+		t.Errorf("ConsoleExporter.SpanEvents() events mismatch (-want +got):\n%s", diff)
+	}
+	if got := spans[0].Status(); got.Code != codes.Error || got.Description != "query failed" {
+		t.Errorf("ConsoleExporter.SpanEvents() span status = %v, want Error with %q", got, "query failed")
+	}
+}
+
+func TestConsoleExporter_ParentErrors(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	e := NewConsoleExporter().Writer(&buf).Format(JSONFormat).ParentErrors(2)
+	handler := e.Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		Req(r).Info("signed in")
+		Req(r).Error("query failed")
+		Req(r).Warn("retrying")
+		Req(r).Error("retry failed")
+		Req(r).Log(LevelCritical, "giving up")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/path", http.NoBody))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var parent map[string]any
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &parent); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if diff := cmp.Diff([]any{"query failed", "giving up"}, parent[errorsKey]); diff != "" {
+		t.Errorf("ConsoleExporter.ParentErrors() errors mismatch (-want +got):\n%s", diff)
+	}
+	if got, _ := parent[errorsCountKey].(float64); got != 3 {
+		t.Errorf("ConsoleExporter.ParentErrors() errors_count = %v, want 3", parent[errorsCountKey])
+	}
+}
+
+func TestConsoleExporter_LevelCounts(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	e := NewConsoleExporter().Writer(&buf).Format(JSONFormat).LevelCounts(true)
+	handler := e.Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		Req(r).Debug("loading")
+		Req(r).Info("signed in")
+		Req(r).Warn("retrying")
+		Req(r).Warn("retrying")
+		Req(r).Error("query failed")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/path", http.NoBody))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var parent map[string]any
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &parent); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	for key, want := range map[string]float64{debugCountKey: 1, infoCountKey: 1, warnCountKey: 2, errorCountKey: 1} {
+		if got, _ := parent[key].(float64); got != want {
+			t.Errorf("ConsoleExporter.LevelCounts() %s = %v, want %v", key, parent[key], want)
+		}
+	}
+}
+
+func TestConsoleExporter_Format_logfmt(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	e := NewConsoleExporter().Writer(&buf).Format(LogfmtFormat)
+
+	handler := e.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Req(r).AddRequestAttribute("test_req_key", "test_req_value")
+		Req(r).WithAttributes().AddAttribute("test_key", "test_value").Logger().Warn("some log")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/path", http.NoBody))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("ConsoleExporter.Format() output = %q, want 2 lines", buf.String())
+	}
+
+	for _, want := range []string{` level=warn msg="some log"`, " test_key=test_value"} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("ConsoleExporter.Format() child = %q, missing %q", lines[0], want)
+		}
+	}
+	for _, want := range []string{
+		` level=warn msg="GET /path"`, " method=GET", " path=/path", " status=201", " requestSize=0",
+		" responseSize=0", " logCount=1", " test_req_key=test_req_value",
+	} {
+		if !strings.Contains(lines[1], want) {
+			t.Errorf("ConsoleExporter.Format() parent = %q, missing %q", lines[1], want)
+		}
+	}
+}
+
+func TestConsoleExporter_TimeFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		format  ConsoleFormat
+		layout  string
+		wantLog func(line string) bool
+	}{
+		{
+			name:    "no timestamp",
+			format:  TextFormat,
+			layout:  "",
+			wantLog: func(line string) bool { return strings.HasPrefix(line, "INFO : some log, traceID=") },
+		},
+		{
+			name:   "RFC3339 in UTC",
+			format: TextFormat,
+			layout: time.RFC3339,
+			wantLog: func(line string) bool {
+				ts, msg, _ := strings.Cut(line, " ")
+				_, err := time.Parse(time.RFC3339, ts)
+
+				return err == nil && strings.HasSuffix(ts, "Z") && strings.HasPrefix(msg, "INFO : some log, traceID=")
+			},
+		},
+		{
+			name:    "logfmt no timestamp",
+			format:  LogfmtFormat,
+			layout:  "",
+			wantLog: func(line string) bool { return strings.HasPrefix(line, `level=info msg="some log" traceID=`) },
+		},
+		{
+			name:   "JSON custom layout",
+			format: JSONFormat,
+			layout: "2006",
+			wantLog: func(line string) bool {
+				return strings.HasPrefix(line, `{"time":"`+time.Now().UTC().Format("2006")+`",`)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			e := NewConsoleExporter().NoColor(true).Writer(&buf).Format(tt.format).TimeFormat(tt.layout).UTC(true)
+			handler := e.Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				Req(r).Info("some log")
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/path", http.NoBody))
+
+			line, _, _ := strings.Cut(buf.String(), "\n")
+			if !tt.wantLog(line) {
+				t.Errorf("ConsoleExporter.TimeFormat() log = %q", line)
+			}
+		})
+	}
+}
+
+func TestConsoleExporter_MinLevel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		level     slog.Level
+		wantLevel []string
+	}{
+		{
+			name:      "trace",
+			level:     LevelTrace,
+			wantLevel: []string{"TRACE", "DEBUG", "INFO", "WARN"},
+		},
+		{
+			name:      "debug",
+			level:     slog.LevelDebug,
+			wantLevel: []string{"DEBUG", "INFO", "WARN"},
+		},
+		{
+			name:      "info",
+			level:     slog.LevelInfo,
+			wantLevel: []string{"INFO", "WARN"},
+		},
+		{
+			name:      "warn",
+			level:     slog.LevelWarn,
+			wantLevel: []string{"WARN"},
+		},
+		{
+			name:      "error",
+			level:     slog.LevelError,
+			wantLevel: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			e := NewConsoleExporter().Writer(&buf).Format(JSONFormat).MinLevel(tt.level)
+			handler := e.Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				Req(r).Trace("some log")
+				Req(r).Debug("some log")
+				Req(r).Info("some log")
+				Req(r).Warn("some log")
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/path", http.NoBody))
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			var gotLevel []string
+			for _, line := range lines[:len(lines)-1] {
+				var entry struct{ Level string }
+				if err := json.Unmarshal([]byte(line), &entry); err != nil {
+					t.Fatalf("json.Unmarshal() error = %v", err)
+				}
+				gotLevel = append(gotLevel, entry.Level)
+			}
+			if diff := cmp.Diff(gotLevel, tt.wantLevel); diff != "" {
+				t.Errorf("ConsoleExporter.MinLevel() mismatch (-want +got):\n%s", diff)
+			}
+			if parent := lines[len(lines)-1]; !strings.Contains(parent, `"msg":"GET /path"`) {
+				t.Errorf("ConsoleExporter.MinLevel() parent log = %q", parent)
+			}
+		})
+	}
+}
+
+func TestConsoleExporter_SetMinLevel(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	e := NewConsoleExporter().Writer(&buf).Format(JSONFormat)
+	handler := e.Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		Req(r).Debug("some log")
+	}))
+
+	for _, tt := range []struct {
+		level     slog.Level
+		wantLines int
+	}{
+		{level: slog.LevelDebug, wantLines: 2},
+		{level: slog.LevelInfo, wantLines: 1},
+		{level: slog.LevelDebug, wantLines: 2},
+	} {
+		buf.Reset()
+		e.SetMinLevel(tt.level)
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/path", http.NoBody))
+
+		if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != tt.wantLines {
+			t.Errorf("SetMinLevel(%v) lines = %d, want %d: %q", tt.level, len(lines), tt.wantLines, lines)
+		}
+	}
+}
+
+func TestConsoleExporter_RequestFormat(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	e := NewConsoleExporter().NoColor(true).Writer(&buf).TimeFormat("").RequestFormat(func(req ConsoleRequest) string {
+		return fmt.Sprintf("%s %s -> %d", req.Request.Method, req.Request.URL.Path, req.Status)
+	})
+	handler := e.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Req(r).AddRequestAttribute("test_req_key", "test_req_value")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/path", http.NoBody))
+
+	if want := "INFO : POST /path -> 202 traceID="; !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("ConsoleExporter.RequestFormat() log = %q, missing prefix %q", buf.String(), want)
+	}
+	if want := " test_req_key=test_req_value\n"; !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("ConsoleExporter.RequestFormat() log = %q, missing suffix %q", buf.String(), want)
+	}
+}
+
+func TestRequestFormat(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/apache_pb.gif?q=1", http.NoBody)
+	r.RemoteAddr = "127.0.0.1:1234"
+	r.SetBasicAuth("frank", "secret")
+	r.Header.Set("Referer", "http://www.example.com/start.html")
+	r.Header.Set("User-Agent", "Mozilla/4.08")
+	begin := time.Date(2000, time.October, 10, 13, 55, 36, 0, time.FixedZone("", -7*60*60))
+
+	tests := []struct {
+		name   string
+		format func(ConsoleRequest) string
+		req    ConsoleRequest
+		want   string
+	}{
+		{
+			name:   "default",
+			format: DefaultRequestFormat,
+			req:    ConsoleRequest{Request: r, Begin: begin, Elapsed: time.Second, Status: 200, RequestSize: 10, ResponseSize: 2326, LogCount: 2},
+			want:   "GET /apache_pb.gif 200 1s requestSize=10 responseSize=2326 logCount=2",
+		},
+		{
+			name:   "combined",
+			format: CombinedRequestFormat,
+			req:    ConsoleRequest{Request: r, Begin: begin, Elapsed: time.Second, Status: 200, RequestSize: 10, ResponseSize: 2326, LogCount: 2},
+			want:   `127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif?q=1 HTTP/1.1" 200 2326 "http://www.example.com/start.html" "Mozilla/4.08"`,
+		},
+		{
+			name:   "combined empty values",
+			format: CombinedRequestFormat,
+			req:    ConsoleRequest{Request: httptest.NewRequest(http.MethodGet, "/", http.NoBody), Begin: begin, Status: 204},
+			want:   `192.0.2.1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.1" 204 - "-" "-"`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.format(tt.req); got != tt.want {
+				t.Errorf("RequestFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestConsoleExporter_Middleware(t *testing.T) {
 	t.Parallel()
 
@@ -117,6 +564,7 @@ func TestConsoleExporter_Middleware(t *testing.T) {
 				return &consoleHandler{
 					next:    next,
 					noColor: true,
+					out:     log.Default(),
 				}
 			},
 		},
@@ -189,6 +637,8 @@ func Test_consoleHandler_ServeHTTP(t *testing.T) {
 			var handlerCalled bool
 			var l *consoleLogger
 			handler := &consoleHandler{
+				handlerOptions: handlerOptions{logAll: true, propagator: consolePropagator, sampleRate: 1},
+				out:            log.New(io.Discard, "", log.LstdFlags),
 				next: http.HandlerFunc(
 					func(w http.ResponseWriter, r *http.Request) {
 						switch tt.args.level {
@@ -239,6 +689,8 @@ func TestNewConsoleLogger(t *testing.T) {
 	type args struct {
 		r       *http.Request
 		noColor bool
+		out     *log.Logger
+		traceID string
 	}
 	tests := []struct {
 		name string
@@ -250,12 +702,16 @@ func TestNewConsoleLogger(t *testing.T) {
 			args: args{
 				r:       &http.Request{},
 				noColor: true,
+				out:     log.Default(),
+				traceID: "4bf92f3577b34da6a3ce929d0e0e4736",
 			},
 			want: &consoleLogger{
 				r:             &http.Request{},
+				traceID:       "4bf92f3577b34da6a3ce929d0e0e4736",
 				noColor:       true,
+				out:           log.Default(),
 				maxSeverity:   logging.Info,
-				rsvdReqKeys:   []string{"requestSize", "responseSize", "logCount"},
+				rsvdReqKeys:   []string{"requestSize", "responseSize", "logCount", "method", "path", "status", "elapsed", "traceID", "spanID"},
 				reqAttributes: map[string]any{},
 				attributes:    map[string]any{},
 			},
@@ -265,8 +721,8 @@ func TestNewConsoleLogger(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			got := newConsoleLogger(tt.args.r, tt.args.noColor)
-			if diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(consoleLogger{}), cmpopts.IgnoreFields(consoleLogger{}, "r", "mu", "root")); diff != "" {
+			got := newConsoleLogger(tt.args.r, tt.args.noColor, tt.args.out, tt.args.traceID)
+			if diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(consoleLogger{}), cmpopts.IgnoreFields(consoleLogger{}, "r", "mu", "root"), cmp.Comparer(func(a, b *log.Logger) bool { return a == b })); diff != "" {
 				t.Errorf("NewConsoleLogger() mismatch (-want +got):\n%s", diff)
 			}
 			if got.root != got {
@@ -319,11 +775,9 @@ func Test_consoleLogger(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			var buf bytes.Buffer
 			ctx := context.Background()
-			log.SetOutput(&buf)
-			t.Cleanup(func() { log.SetOutput(os.Stderr) })
 
 			u, _ := url.Parse("http://some.domain.com/path")
-			l := &consoleLogger{r: &http.Request{Method: http.MethodGet, URL: u}, noColor: tt.fields.noColor, attributes: tt.fields.attributes}
+			l := &consoleLogger{r: &http.Request{Method: http.MethodGet, URL: u}, noColor: tt.fields.noColor, out: log.New(&buf, "", log.LstdFlags), attributes: tt.fields.attributes}
 			l.root = l
 			format := "Formatted %s"
 
@@ -379,6 +833,110 @@ func Test_consoleLogger(t *testing.T) {
 	}
 }
 
+func Test_consoleTraceIDFromRequest(t *testing.T) {
+	t.Parallel()
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	})
+
+	tests := []struct {
+		name string
+		req  func() *http.Request
+		want string
+	}{
+		{
+			name: "no trace in request",
+			req: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			},
+			want: "105445aa7843bc8bf206b12000100000",
+		},
+		{
+			name: "with traceparent in headers",
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+				r.Header.Add("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+				return r
+			},
+			want: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name: "span in context takes priority over traceparent",
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+				r.Header.Add("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+				return r.WithContext(trace.ContextWithSpanContext(r.Context(), sc))
+			},
+			want: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := traceIDFromRequest(tt.req(), consolePropagator, func() string { return "105445aa7843bc8bf206b12000100000" }); got != tt.want {
+				t.Errorf("traceIDFromRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_consoleLogger_traceAttributes(t *testing.T) {
+	t.Parallel()
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	})
+
+	tests := []struct {
+		name    string
+		traceID string
+		ctx     context.Context
+		want    []slog.Attr
+	}{
+		{
+			name:    "no trace ID",
+			traceID: "",
+			ctx:     context.Background(),
+			want:    nil,
+		},
+		{
+			name:    "no span in context",
+			traceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			ctx:     context.Background(),
+			want:    []slog.Attr{slog.String("traceID", "4bf92f3577b34da6a3ce929d0e0e4736")},
+		},
+		{
+			name:    "span in context",
+			traceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			ctx:     trace.ContextWithSpanContext(context.Background(), sc),
+			want: []slog.Attr{
+				slog.String("traceID", "4bf92f3577b34da6a3ce929d0e0e4736"),
+				slog.String("spanID", "00f067aa0ba902b7"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			l := &consoleLogger{traceID: tt.traceID}
+			got := l.traceAttributes(tt.ctx)
+			if diff := cmp.Diff(got, tt.want, cmp.Comparer(func(a, b slog.Attr) bool { return a.Equal(b) })); diff != "" {
+				t.Errorf("consoleLogger.traceAttributes() mismatch (-want +got):\n%s", diff)
+			}
+			if l.TraceID() != tt.traceID {
+				t.Errorf("consoleLogger.TraceID() = %v, want %v", l.TraceID(), tt.traceID)
+			}
+		})
+	}
+}
+
 func Test_consoleLogger_AddRequestAttribute(t *testing.T) {
 	t.Parallel()
 	type fields struct {
@@ -9,26 +9,40 @@ type key int
 
 const (
 	logKey key = iota
+	auditKey
+	requestIDCtxKey
+	grpcCallKey
+	messageCallKey
+	jobRunKey
+	lambdaInvocationKey
+	correlationCtxKey
+	propagatorCtxKey
 )
 
 // fromCtx gets the logger out of the context.
-// If no logger is stored in the context, a stderr logger is returned.
+// If no logger is stored in the context, the fallback logger is returned.
 func fromCtx(ctx context.Context) ctxLogger {
+	if l, ok := lookupCtx(ctx); ok {
+		return l
+	}
+
+	return fallbackLogger()
+}
+
+// lookupCtx gets the logger out of the context, and reports whether it was found
+func lookupCtx(ctx context.Context) (ctxLogger, bool) {
 	if ctx == nil {
-		return newStdErrLogger()
+		return nil, false
 	}
 	l, ok := ctx.Value(logKey).(ctxLogger)
-	if !ok {
-		return newStdErrLogger()
-	}
 
-	return l
+	return l, ok
 }
 
 // fromReq gets the logger in the request's context.
 func fromReq(r *http.Request) ctxLogger {
 	if r == nil {
-		return newStdErrLogger()
+		return fallbackLogger()
 	}
 
 	return fromCtx(r.Context())
@@ -41,6 +55,10 @@ func newContext(ctx context.Context, l ctxLogger) context.Context {
 
 // ctxLogger defines the logging interface with context
 type ctxLogger interface {
+	// Trace logs a trace message.
+	Trace(ctx context.Context, v any)
+	// Tracef logs a trace message with format.
+	Tracef(ctx context.Context, format string, v ...any)
 	// Debug logs a debug message.
 	Debug(ctx context.Context, v any)
 	// Debugf logs a debug message with format.
@@ -57,6 +75,16 @@ type ctxLogger interface {
 	Error(ctx context.Context, v any)
 	// Errorf logs an error message with format.
 	Errorf(ctx context.Context, format string, v ...any)
+	// Critical logs a critical message.
+	Critical(ctx context.Context, v any)
+	// Criticalf logs a critical message with format.
+	Criticalf(ctx context.Context, format string, v ...any)
+
+	// Enabled reports whether logs at the level are written
+	Enabled(ctx context.Context, level Level) bool
+
+	// Flush writes any buffered logs
+	Flush()
 
 	// AddRequestAttribute adds an attribute (kv) for the parent request log
 	// If the key matches a reserved key, it will be prefixed with "custom_"
@@ -66,8 +94,15 @@ type ctxLogger interface {
 	// WithAttributes returns an attributer that can be used to add child (trace) log attributes
 	WithAttributes() attributer
 
+	// Named returns a child ctxLogger for the component within the component of this ctxLogger
+	Named(name string) ctxLogger
+
 	// TraceID returns the trace ID of the request logs
 	TraceID() string
+
+	// RawTraceID returns the trace ID of the request logs without any
+	// exporter specific formatting (e.g. the GCP trace resource name prefix)
+	RawTraceID() string
 }
 
 // attributer defines the interface for adding attributes for child (trace) logs
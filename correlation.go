@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+)
+
+// correlationIDKey is the attribute of the correlation ID in the parent and child logs
+const correlationIDKey = "correlation_id"
+
+// correlation is the correlation ID of a request, with the header that propagates it to outbound requests
+type correlation struct {
+	header string
+	id     string
+}
+
+// correlationID returns the correlation ID from the header of the request. Unlike request IDs, correlation IDs
+// are set by the client and are not generated, so it returns false when the request has none, it is not valid,
+// or header is empty, i.e. correlation IDs are disabled.
+func correlationID(r *http.Request, header string) (string, bool) {
+	if header == "" {
+		return "", false
+	}
+	if id := r.Header.Get(header); validRequestID(id) {
+		return id, true
+	}
+
+	return "", false
+}
+
+// newCorrelationContext returns a copy of ctx with the correlation ID and its header
+func newCorrelationContext(ctx context.Context, header, id string) context.Context {
+	if header == "" || id == "" {
+		return ctx
+	}
+
+	return context.WithValue(ctx, correlationCtxKey, correlation{header: header, id: id})
+}
+
+// correlationFromCtx returns the correlation ID of the request and its header, or false if there is none
+func correlationFromCtx(ctx context.Context) (correlation, bool) {
+	if ctx == nil {
+		return correlation{}, false
+	}
+	c, ok := ctx.Value(correlationCtxKey).(correlation)
+
+	return c, ok
+}
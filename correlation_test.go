@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_correlationID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		want   string
+		wantOK bool
+	}{
+		{
+			name:  "disabled",
+			value: "corr-123",
+		},
+		{
+			name:   "propagated",
+			header: "X-Correlation-ID",
+			value:  "corr-123",
+			want:   "corr-123",
+			wantOK: true,
+		},
+		{
+			name:   "missing",
+			header: "X-Correlation-ID",
+		},
+		{
+			name:   "invalid characters",
+			header: "X-Correlation-ID",
+			value:  "corr 123\n",
+		},
+		{
+			name:   "too long",
+			header: "X-Correlation-ID",
+			value:  strings.Repeat("a", maxRequestIDLength+1),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			if tt.value != "" {
+				r.Header.Set("X-Correlation-ID", tt.value)
+			}
+
+			got, ok := correlationID(r, tt.header)
+			if ok != tt.wantOK {
+				t.Fatalf("correlationID() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("correlationID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogger_CorrelationID(t *testing.T) {
+	t.Parallel()
+
+	ctx := newCorrelationContext(context.Background(), "X-Correlation-ID", "corr-123")
+	if got := Ctx(ctx).CorrelationID(); got != "corr-123" {
+		t.Errorf("Logger.CorrelationID() = %q, want %q", got, "corr-123")
+	}
+	if got := Ctx(context.Background()).CorrelationID(); got != "" {
+		t.Errorf("Logger.CorrelationID() = %q, want empty", got)
+	}
+}
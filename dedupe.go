@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// suppressedCountKey is the attribute of the number of identical logs that were suppressed
+const suppressedCountKey = "suppressed_count"
+
+// deduper suppresses identical child logs of a request within a window
+type deduper struct {
+	window  time.Duration
+	mu      sync.Mutex
+	entries map[string]*dedupeEntry
+	total   int // number of logs suppressed during the request
+}
+
+type dedupeEntry struct {
+	written    time.Time // time the log was last written
+	suppressed int       // number of logs suppressed since written
+}
+
+// newDeduper returns a deduper for the window, or nil when the window is not positive
+func newDeduper(window time.Duration) *deduper {
+	if window <= 0 {
+		return nil
+	}
+
+	return &deduper{window: window, entries: make(map[string]*dedupeEntry)}
+}
+
+// allow reports whether the log identified by key is written at now, and the number of identical
+// logs suppressed since it was last written. A nil deduper allows all logs.
+func (d *deduper) allow(key string, now time.Time) (ok bool, suppressed int) {
+	if d == nil {
+		return true, 0
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	e, exists := d.entries[key]
+	if exists && now.Sub(e.written) < d.window {
+		e.suppressed++
+		d.total++
+
+		return false, 0
+	}
+	if exists {
+		suppressed = e.suppressed
+	}
+	d.entries[key] = &dedupeEntry{written: now}
+
+	return true, suppressed
+}
+
+// suppressed returns the number of logs suppressed during the request
+func (d *deduper) suppressed() int {
+	if d == nil {
+		return 0
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.total
+}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_deduper_allow(t *testing.T) {
+	t.Parallel()
+
+	begin := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	type log struct {
+		key            string
+		at             time.Duration
+		wantOK         bool
+		wantSuppressed int
+	}
+	tests := []struct {
+		name      string
+		window    time.Duration
+		logs      []log
+		wantTotal int
+	}{
+		{
+			name:   "disabled",
+			window: 0,
+			logs: []log{
+				{key: "a", wantOK: true},
+				{key: "a", wantOK: true},
+			},
+		},
+		{
+			name:   "suppressed within window",
+			window: time.Second,
+			logs: []log{
+				{key: "a", wantOK: true},
+				{key: "a", at: 100 * time.Millisecond},
+				{key: "b", at: 200 * time.Millisecond, wantOK: true},
+				{key: "a", at: 999 * time.Millisecond},
+			},
+			wantTotal: 2,
+		},
+		{
+			name:   "count after window",
+			window: time.Second,
+			logs: []log{
+				{key: "a", wantOK: true},
+				{key: "a", at: 500 * time.Millisecond},
+				{key: "a", at: 600 * time.Millisecond},
+				{key: "a", at: time.Second, wantOK: true, wantSuppressed: 2},
+				{key: "a", at: 1500 * time.Millisecond},
+				{key: "a", at: 3 * time.Second, wantOK: true, wantSuppressed: 1},
+				{key: "a", at: 5 * time.Second, wantOK: true},
+			},
+			wantTotal: 3,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			d := newDeduper(tt.window)
+			for i, l := range tt.logs {
+				ok, suppressed := d.allow(l.key, begin.Add(l.at))
+				if ok != l.wantOK || suppressed != l.wantSuppressed {
+					t.Errorf("log %d: allow() = (%v, %d), want (%v, %d)", i, ok, suppressed, l.wantOK, l.wantSuppressed)
+				}
+			}
+			if got := d.suppressed(); got != tt.wantTotal {
+				t.Errorf("suppressed() = %d, want %d", got, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"slices"
+	"sync"
+)
+
+// keys of the attributes of the parent log with the Error and above child logs of the request
+const (
+	errorsKey      = "errors"
+	errorsCountKey = "errors_count"
+)
+
+// errorSummary collects the messages of the Error and above child logs of a request for the parent log
+type errorSummary struct {
+	max      int
+	mu       sync.Mutex
+	messages []string
+	count    int // number of Error and above child logs of the request
+}
+
+// newErrorSummary returns an errorSummary of up to max messages, or nil when max is not positive
+func newErrorSummary(max int) *errorSummary {
+	if max <= 0 {
+		return nil
+	}
+
+	return &errorSummary{max: max}
+}
+
+// add adds the message of an Error or above child log. When the summary is full, the last message is replaced,
+// so the summary has the first max-1 messages and the last message. A nil errorSummary is a no-op.
+func (s *errorSummary) add(msg string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.count++
+	if len(s.messages) < s.max {
+		s.messages = append(s.messages, msg)
+
+		return
+	}
+	s.messages[s.max-1] = msg
+}
+
+// summary returns the messages and the number of the Error and above child logs of the request.
+// A nil errorSummary has none.
+func (s *errorSummary) summary() ([]string, int) {
+	if s == nil {
+		return nil, 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.messages), s.count
+}
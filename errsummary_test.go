@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_errorSummary(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		max       int
+		messages  []string
+		want      []string
+		wantCount int
+	}{
+		{
+			name:     "disabled",
+			messages: []string{"query failed"},
+		},
+		{
+			name: "no errors",
+			max:  3,
+		},
+		{
+			name:      "below max",
+			max:       3,
+			messages:  []string{"query failed", "retry failed"},
+			want:      []string{"query failed", "retry failed"},
+			wantCount: 2,
+		},
+		{
+			name:      "first and last",
+			max:       3,
+			messages:  []string{"error 1", "error 2", "error 3", "error 4", "error 5"},
+			want:      []string{"error 1", "error 2", "error 5"},
+			wantCount: 5,
+		},
+		{
+			name:      "last",
+			max:       1,
+			messages:  []string{"error 1", "error 2"},
+			want:      []string{"error 2"},
+			wantCount: 2,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newErrorSummary(tt.max)
+			for _, msg := range tt.messages {
+				s.add(msg)
+			}
+			got, count := s.summary()
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("errorSummary.summary() messages mismatch (-want +got):\n%s", diff)
+			}
+			if count != tt.wantCount {
+				t.Errorf("errorSummary.summary() count = %d, want %d", count, tt.wantCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"net/http"
+	"runtime/trace"
+)
+
+// executionTaskType is the type of the runtime/trace tasks of the requests, which groups them in go tool trace
+const executionTaskType = "http.server.request"
+
+// keys of the annotations of the runtime/trace tasks of the requests
+const (
+	executionTraceIDKey = "trace_id"
+	executionMethodKey  = "http.request.method"
+	executionPathKey    = "url.path"
+)
+
+// startExecutionTask starts a runtime/trace task of the request annotated with the trace ID of the request logs,
+// so the Go execution traces line up with the logs, and returns the request with the context of the task. The task
+// is nil when disabled, or when no execution trace is being recorded.
+func startExecutionTask(r *http.Request, enabled bool, traceID string) (*http.Request, *trace.Task) {
+	if !enabled || !trace.IsEnabled() {
+		return r, nil
+	}
+
+	ctx, task := trace.NewTask(r.Context(), executionTaskType)
+	trace.Log(ctx, executionTraceIDKey, traceID)
+	trace.Log(ctx, executionMethodKey, r.Method)
+	trace.Log(ctx, executionPathKey, r.URL.Path)
+
+	return r.WithContext(ctx), task
+}
+
+// endExecutionTask ends the runtime/trace task of the request. It is a no-op when the task is nil.
+func endExecutionTask(task *trace.Task) {
+	if task != nil {
+		task.End()
+	}
+}
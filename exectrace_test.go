@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"runtime/trace"
+	"testing"
+)
+
+func Test_startExecutionTask(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/users/42", http.NoBody)
+	if _, task := startExecutionTask(r, false, "4bf92f3577b34da6a3ce929d0e0e4736"); task != nil {
+		t.Errorf("startExecutionTask() task = %v, want nil when disabled", task)
+	}
+
+	var buf bytes.Buffer
+	if err := trace.Start(&buf); err != nil {
+		t.Fatalf("trace.Start() error = %v", err)
+	}
+	got, task := startExecutionTask(r, true, "4bf92f3577b34da6a3ce929d0e0e4736")
+	endExecutionTask(task)
+	trace.Stop()
+
+	if task == nil {
+		t.Fatalf("startExecutionTask() task = nil, want a task")
+	}
+	if got.Context() == r.Context() {
+		t.Errorf("startExecutionTask() request context is unchanged, want the context of the task")
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("4bf92f3577b34da6a3ce929d0e0e4736")) {
+		t.Errorf("startExecutionTask() execution trace is missing the trace ID")
+	}
+}
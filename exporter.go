@@ -0,0 +1,712 @@
+package logger
+
+import (
+	"cmp"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/netip"
+	"os"
+	"regexp"
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// exporterOptions are the options shared by the exporters, which embed them. The options return the exporter E
+// that embeds them, so they can be chained with the options of the exporter, which must be created with its constructor.
+type exporterOptions[E any] struct {
+	self        E // the exporter embedding the options
+	logAll      bool
+	minLevelVar *slog.LevelVar
+	statusLevel func(status int) slog.Level
+	errorStack  bool
+	spanEvents  bool
+	metrics     MetricsRecorder
+	tracing     trace.TracerProvider
+	execTrace   bool
+	propagator  propagation.TextMapPropagator
+	caller      bool
+	callerSkip  int
+	compLevels  map[string]slog.Level
+	dedupe      time.Duration
+	maxLogs     int
+	parentErrs  int
+	levelCounts bool
+	maxMsgLen   int
+	maxAttrSize int
+	attrFilter  *attrFilter
+	processors  processors
+	scrubParams []string
+	staticAttrs map[string]any
+	buildInfo   bool
+	k8sMetadata bool
+	excludes    *pathFilter
+	routes      []*RouteConfig
+	sampling    bool
+	sampleRate  float64
+	slowRequest time.Duration
+	followTrace bool
+	pathSamples pathSamples
+	bodyCapture *BodyCapture
+	reqHeaders  []string
+	respHeaders []string
+	proxies     []netip.Prefix
+	identity    IdentityExtractor
+	reqIDHeader string
+	corrHeader  string
+	traceHeader string
+	recovers    bool
+	repanic     bool
+	cancelLevel *slog.Level
+	phaseTiming bool
+}
+
+// setMinLevel sets the level of minLevelVar, which is safe to call at runtime after the Middleware is created
+func (o *exporterOptions[E]) setMinLevel(level slog.Level) {
+	if o.minLevelVar == nil {
+		o.minLevelVar = new(slog.LevelVar)
+	}
+	o.minLevelVar.Set(level)
+}
+
+// StatusLevel sets the mapping of the HTTP response status to the minimum level of the parent request log,
+// e.g. ClientErrorStatusLevel. For the GoogleCloudExporter, levels of Info and below keep the Default severity
+// of requests without logs (default: DefaultStatusLevel)
+func (o *exporterOptions[E]) StatusLevel(f func(status int) slog.Level) E {
+	o.statusLevel = f
+
+	return o.self
+}
+
+// ComponentLevel sets the minimum level of the child logs of the component, and its sub components, created with
+// Logger.Named. It overrides MinLevel, e.g. to write the Debug logs of a single component. The AWSExporter does
+// not write the logs below the level of its Handler, which must be lowered as well. (default: MinLevel)
+func (o *exporterOptions[E]) ComponentLevel(name string, level slog.Level) E {
+	if o.compLevels == nil {
+		o.compLevels = make(map[string]slog.Level)
+	}
+	o.compLevels[name] = level
+
+	return o.self
+}
+
+// ErrorStack controls if a stack trace of the caller is captured and written in the "stack_trace"
+// attribute of Error and above logs (default: false)
+//
+// The stack trace is in the format of a Go panic, so Error Reporting groups the errors of the GoogleCloudExporter
+// by stack trace. In the TextFormat of the ConsoleExporter, the stack trace is written on the lines following the log.
+func (o *exporterOptions[E]) ErrorStack(v bool) E {
+	o.errorStack = v
+
+	return o.self
+}
+
+// IncludeCaller controls if the file, line, and function of the caller are recorded on every
+// child log (default: false)
+//
+// The GoogleCloudExporter writes the caller to the sourceLocation of the log entry. The AWSExporter writes it in
+// the "source" attribute, in the same format as slog.HandlerOptions.AddSource, which records the logger internals
+// instead of the caller and should not be used together. The ConsoleExporter writes it in the "source" attribute
+// as file:line.
+func (o *exporterOptions[E]) IncludeCaller(v bool) E {
+	o.caller = v
+
+	return o.self
+}
+
+// CallerSkip sets the number of additional stack frames to skip when IncludeCaller is enabled,
+// so that helpers wrapping the Logger are not recorded as the caller (default: 0)
+func (o *exporterOptions[E]) CallerSkip(skip int) E {
+	o.callerSkip = skip
+
+	return o.self
+}
+
+// Dedupe suppresses identical child logs (same level, message, and attributes) of a request that are
+// written within the window of the last one written, e.g. from a retry loop. The next identical log
+// written after the window has a "suppressed_count" attribute with the number of logs suppressed,
+// and the parent request log has the total (default: 0, disabled)
+func (o *exporterOptions[E]) Dedupe(window time.Duration) E {
+	o.dedupe = window
+
+	return o.self
+}
+
+// MaxLogs limits the number of child logs written per request, protecting the backend when a handler
+// logs in a tight loop. Logs after the limit are dropped, and a final Warning log reports the number
+// of logs dropped (default: 0, unlimited)
+func (o *exporterOptions[E]) MaxLogs(n int) E {
+	o.maxLogs = n
+
+	return o.self
+}
+
+// ParentErrors adds the messages of the Error and above child logs of the request to the parent log in the errors
+// attribute, with their number in errors_count, so the parent log alone tells what failed. Up to n messages are
+// added: the first n-1 messages and the last message (default: 0, disabled)
+func (o *exporterOptions[E]) ParentErrors(n int) E {
+	o.parentErrs = n
+
+	return o.self
+}
+
+// LevelCounts adds the number of child logs of the request by level to the parent log, in the debug_count (Trace
+// and Debug), info_count, warn_count, and error_count (Error and Critical) attributes (default: false)
+func (o *exporterOptions[E]) LevelCounts(v bool) E {
+	o.levelCounts = v
+
+	return o.self
+}
+
+// AllowAttributes only writes the user attributes with a key matching one of the patterns, which use
+// the syntax of path.Match, e.g. "http.*" (default: all attributes)
+func (o *exporterOptions[E]) AllowAttributes(patterns ...string) E {
+	if o.attrFilter == nil {
+		o.attrFilter = &attrFilter{}
+	}
+	o.attrFilter.allow = append(o.attrFilter.allow, patterns...)
+
+	return o.self
+}
+
+// DenyAttributes drops the user attributes with a key matching one of the patterns, which use the syntax
+// of path.Match, e.g. "internal.*" to strip internal fields in production. It takes precedence over AllowAttributes.
+func (o *exporterOptions[E]) DenyAttributes(patterns ...string) E {
+	if o.attrFilter == nil {
+		o.attrFilter = &attrFilter{}
+	}
+	o.attrFilter.deny = append(o.attrFilter.deny, patterns...)
+
+	return o.self
+}
+
+// IncludeBuildInfo controls if the parent logs include the "service.version" attribute, read from the
+// module version or VCS revision of the build, and the "host.name" and "process.pid" attributes (default: false)
+func (o *exporterOptions[E]) IncludeBuildInfo(v bool) E {
+	o.buildInfo = v
+
+	return o.self
+}
+
+// KubernetesMetadata controls if the pod name, namespace, and node name are added to all parent request logs
+// when running in a Kubernetes cluster. They are read from the POD_NAME, POD_NAMESPACE, and NODE_NAME
+// environment variables, which are set with the downward API (default: false)
+func (o *exporterOptions[E]) KubernetesMetadata(v bool) E {
+	o.k8sMetadata = v
+
+	return o.self
+}
+
+// SlowRequest sets the latency at which the severity of the parent log of a request is raised to at least
+// Warning, independent of the status code, and a slow_request=true attribute is added. The parent logs of slow
+// requests are written even when requests without logs are not logged (default: 0, disabled)
+func (o *exporterOptions[E]) SlowRequest(threshold time.Duration) E {
+	o.slowRequest = threshold
+
+	return o.self
+}
+
+// SamplePath writes the parent log of only 1 in n requests with a path matching the pattern, which uses the
+// syntax of path.Match, keeping some visibility into health checks and probes that are not excluded with
+// ExcludePaths. Requests with an error status or Warning or above logs are always written. The parent logs
+// include the number of matching requests in the sample_count attribute.
+func (o *exporterOptions[E]) SamplePath(pattern string, n int) E {
+	o.pathSamples = append(o.pathSamples, pathSample{pattern: pattern, n: uint64(max(n, 1))})
+
+	return o.self
+}
+
+// ExcludePaths excludes the requests with a path matching one of the patterns, which use the syntax of path.Match
+// (e.g. "/healthz" or "/debug/*"), from parent logs, so health checks and probes do not dominate the logs.
+// Child logs written during the requests are still written.
+func (o *exporterOptions[E]) ExcludePaths(patterns ...string) E {
+	if o.excludes == nil {
+		o.excludes = &pathFilter{}
+	}
+	o.excludes.globs = append(o.excludes.globs, patterns...)
+
+	return o.self
+}
+
+// ExcludePathsRegexp excludes the requests with a path matching one of the regular expressions from parent logs,
+// like ExcludePaths
+func (o *exporterOptions[E]) ExcludePathsRegexp(patterns ...*regexp.Regexp) E {
+	if o.excludes == nil {
+		o.excludes = &pathFilter{}
+	}
+	o.excludes.regexps = append(o.excludes.regexps, patterns...)
+
+	return o.self
+}
+
+// Routes overrides the LogAll and MinLevel options for the requests matching the routes, e.g. to mute
+// noisy routes, or fully log critical routes. The ConsoleExporter logs all requests unless the route sets
+// LogAll(false). The first matching route is used.
+func (o *exporterOptions[E]) Routes(routes ...*RouteConfig) E {
+	o.routes = append(o.routes, routes...)
+
+	return o.self
+}
+
+// CaptureRequestBody records the request bodies allowed by the BodyCapture in the http.request.body attribute
+// of the parent logs (default: nil, bodies are not captured)
+func (o *exporterOptions[E]) CaptureRequestBody(c *BodyCapture) E {
+	o.bodyCapture = c
+
+	return o.self
+}
+
+// RequestHeaders records the request headers in the http.request.header.<name> attributes of the parent
+// logs, e.g. X-Request-ID or Accept. The values of sensitive headers, e.g. Authorization and Cookie, are masked.
+func (o *exporterOptions[E]) RequestHeaders(names ...string) E {
+	o.reqHeaders = append(o.reqHeaders, names...)
+
+	return o.self
+}
+
+// ResponseHeaders records the response headers in the http.response.header.<name> attributes of the parent
+// logs, e.g. Content-Type or Cache-Control. The values of sensitive headers, e.g. Set-Cookie, are masked.
+func (o *exporterOptions[E]) ResponseHeaders(names ...string) E {
+	o.respHeaders = append(o.respHeaders, names...)
+
+	return o.self
+}
+
+// TrustedProxies sets the addresses of the proxies in front of the service, e.g. netip.MustParsePrefix("10.0.0.0/8"),
+// so the client IP is the right-most address of the X-Forwarded-For (or Forwarded) header that is not a trusted
+// proxy, which can not be spoofed by the client (default: none, the address of the peer is used)
+func (o *exporterOptions[E]) TrustedProxies(proxies ...netip.Prefix) E {
+	o.proxies = append(o.proxies, proxies...)
+
+	return o.self
+}
+
+// WithIdentityExtractor adds the attributes returned by extract, e.g. the user ID, tenant, or organization,
+// to the parent logs. It is called before the handler, so authentication middleware must wrap the logger
+// middleware. Use IdentityMiddleware when authentication runs inside the logger middleware.
+func (o *exporterOptions[E]) WithIdentityExtractor(extract IdentityExtractor) E {
+	o.identity = extract
+
+	return o.self
+}
+
+// RecoverPanics recovers panics of the handler and writes a 500 response. The parent log is written for panics
+// with or without this option, with the panic logged as an Error child log with the stack, but without it the
+// panic is raised again after the parent log is written. When repanic is set, the panic is also raised again,
+// e.g. for an outer recovery middleware (default: panics are raised again)
+func (o *exporterOptions[E]) RecoverPanics(repanic bool) E {
+	o.recovers, o.repanic = true, repanic
+
+	return o.self
+}
+
+// CanceledErrorLevel sets the level of the Error and above child logs written after the client disconnected,
+// e.g. LevelWarn, since these errors are usually caused by the canceled request context. The parent logs of
+// these requests always have the client_disconnected and client_disconnected_elapsed attributes
+// (default: the level is not changed)
+func (o *exporterOptions[E]) CanceledErrorLevel(level Level) E {
+	o.cancelLevel = &level
+
+	return o.self
+}
+
+// PhaseTiming adds the latency breakdown of the response to the parent logs, in milliseconds: the time to the
+// first byte (latency.first_byte_ms), the time in the handler without writing (latency.handler_ms), and the
+// time writing the response (latency.write_ms), to distinguish slow backends from slow clients (default: false)
+func (o *exporterOptions[E]) PhaseTiming(v bool) E {
+	o.phaseTiming = v
+
+	return o.self
+}
+
+// RequestID propagates the request ID in the header (e.g. "X-Request-ID"), or generates one when the request
+// has none, adds it to the parent and child logs as request_id, and sets it in the response header, so clients
+// can report it. The request ID is returned by Logger.RequestID (default: disabled)
+func (o *exporterOptions[E]) RequestID(header string) E {
+	o.reqIDHeader = header
+
+	return o.self
+}
+
+// CorrelationID propagates the correlation ID in the header (e.g. "X-Correlation-ID") set by the client, adds it
+// to the parent and child logs as correlation_id, and sets it in the header of the outbound requests of Transport.
+// Unlike the request ID, no correlation ID is generated when the request has none. The correlation ID is returned
+// by Logger.CorrelationID (default: disabled)
+func (o *exporterOptions[E]) CorrelationID(header string) E {
+	o.corrHeader = header
+
+	return o.self
+}
+
+// TraceIDHeader sets the trace ID of the request in the response header (e.g. "X-Trace-Id"), so frontend error
+// reports can link to the logs of the request. The value of the GoogleCloudExporter is the trace resource name
+// of the logs, i.e. "projects/{projectID}/traces/{traceID}" (default: disabled)
+func (o *exporterOptions[E]) TraceIDHeader(header string) E {
+	o.traceHeader = header
+
+	return o.self
+}
+
+// SpanEvents controls if the child logs are added as events of the OpenTelemetry span of their context, and Error
+// and above logs set the status of the span to Error, so trace views show the logs inline (default: false)
+func (o *exporterOptions[E]) SpanEvents(v bool) E {
+	o.spanEvents = v
+
+	return o.self
+}
+
+// Metrics records the metrics of the requests and child logs with the recorder, from the same pass of the middleware
+// as the logs, e.g. loggerprom.NewMetrics for Prometheus (default: nil, no metrics are recorded)
+func (o *exporterOptions[E]) Metrics(m MetricsRecorder) E {
+	o.metrics = m
+
+	return o.self
+}
+
+// WithTracing starts an OpenTelemetry server span for each request with the tracer provider, when the application
+// is not otherwise instrumented, so the logs are correlated to a real trace instead of a generated trace ID. The
+// parent of the span is the span propagated by the client. Requests already in a recording span, e.g. of
+// otelhttp, do not start a span (default: nil, no spans are started)
+func (o *exporterOptions[E]) WithTracing(tp trace.TracerProvider) E {
+	o.tracing = tp
+
+	return o.self
+}
+
+// ExecutionTrace starts a runtime/trace task for each request annotated with the trace ID of the request logs,
+// so the Go execution traces of performance investigations line up with the logs. Tasks are only started while an
+// execution trace is recorded, e.g. with runtime/trace.Start or net/http/pprof (default: false)
+func (o *exporterOptions[E]) ExecutionTrace(v bool) E {
+	o.execTrace = v
+
+	return o.self
+}
+
+// Propagator sets the propagator of the traces of the requests, e.g. propagation.NewCompositeTextMapPropagator(
+// propagation.TraceContext{}, b3.New()) of go.opentelemetry.io/contrib/propagators/b3 to also follow B3 headers.
+// The propagator is shared by the trace IDs of the logs, FollowTraceSampling, and the spans of WithTracing
+// (default: traceparent, or X-Cloud-Trace-Context without traceparent for the GoogleCloudExporter, and
+// X-Amzn-Trace-Id, or traceparent without X-Amzn-Trace-Id for the AWSExporter)
+func (o *exporterOptions[E]) Propagator(p propagation.TextMapPropagator) E {
+	o.propagator = p
+
+	return o.self
+}
+
+// WithStaticAttributes adds attributes written in every parent and child log, e.g. the service name,
+// version, region, and environment. Attributes added during the request with the same key take precedence.
+func (o *exporterOptions[E]) WithStaticAttributes(attrs map[string]any) E {
+	if o.staticAttrs == nil {
+		o.staticAttrs = make(map[string]any)
+	}
+	maps.Copy(o.staticAttrs, attrs)
+
+	return o.self
+}
+
+// Process adds processors that are run in order on every log entry before it is written, e.g. to
+// enrich, scrub, sample, or route the logs. The entry is dropped when a processor returns false.
+func (o *exporterOptions[E]) Process(p ...Processor) E {
+	o.processors = append(o.processors, p...)
+
+	return o.self
+}
+
+// HashAttributes replaces the values of the attributes with the keys (case-insensitive) with their Hash,
+// so that values like emails can be correlated across logs without storing them (see SetHashKey). It is run in order
+// with the processors added with Process.
+func (o *exporterOptions[E]) HashAttributes(keys ...string) E {
+	o.processors = append(o.processors, hashProcessor(keys))
+
+	return o.self
+}
+
+// SeverityRules sets the level of the log entries to the level of the first rule they match, e.g. to downgrade
+// "context canceled" errors to Info. It is run in order with the processors added with Process.
+func (o *exporterOptions[E]) SeverityRules(rules ...SeverityRule) E {
+	o.processors = append(o.processors, severityProcessor(rules))
+
+	return o.self
+}
+
+// DropLogs drops the child logs that match one of the rules, e.g. known noisy logs of vendored libraries.
+// It is run in order with the processors added with Process.
+func (o *exporterOptions[E]) DropLogs(rules ...DropRule) E {
+	o.processors = append(o.processors, dropProcessor(rules))
+
+	return o.self
+}
+
+// RedactQuery masks the values of the query parameters in the URL of the request log, e.g. "token"
+// or "api_key". The parameter names are case-insensitive (default: none)
+func (o *exporterOptions[E]) RedactQuery(params ...string) E {
+	o.scrubParams = append(o.scrubParams, params...)
+
+	return o.self
+}
+
+// handlerOptions copies the options for the handler of the Middleware, so options changed after the Middleware
+// is created are not applied. propagator is used when the Propagator option is not set.
+func (o *exporterOptions[E]) handlerOptions(propagator propagation.TextMapPropagator, audit *auditChain) handlerOptions {
+	sampleRate := 1.0
+	if o.sampling {
+		sampleRate = o.sampleRate
+	}
+
+	parentAttrs := make(map[string]any)
+	if o.buildInfo {
+		maps.Copy(parentAttrs, buildAttributes())
+	}
+	if o.k8sMetadata {
+		maps.Copy(parentAttrs, k8sAttributes(os.Getenv, os.ReadFile))
+	}
+
+	propagator = cmp.Or(o.propagator, propagator)
+
+	return handlerOptions{
+		logAll:      o.logAll,
+		minLevelVar: o.minLevelVar,
+		statusLevel: o.statusLevel,
+		errorStack:  o.errorStack,
+		spanEvents:  o.spanEvents,
+		metrics:     o.metrics,
+		tracing:     o.tracing,
+		execTrace:   o.execTrace,
+		propagator:  propagator,
+		caller:      o.caller,
+		callerSkip:  o.callerSkip,
+		compLevels:  maps.Clone(o.compLevels),
+		dedupe:      o.dedupe,
+		maxLogs:     o.maxLogs,
+		parentErrs:  o.parentErrs,
+		levelCounts: o.levelCounts,
+		maxMsgLen:   o.maxMsgLen,
+		maxAttrSize: o.maxAttrSize,
+		attrFilter:  o.attrFilter.clone(),
+		processors:  slices.Clone(o.processors),
+		scrubParams: slices.Clone(o.scrubParams),
+		staticAttrs: maps.Clone(o.staticAttrs),
+		parentAttrs: parentAttrs,
+		excludes:    o.excludes.clone(),
+		routes:      cloneRoutes(o.routes),
+		sampleRate:  sampleRate,
+		slowRequest: o.slowRequest,
+		followTrace: o.followTrace,
+		pathSamples: o.pathSamples.clone(),
+		bodyCapture: o.bodyCapture.clone(),
+		reqHeaders:  slices.Clone(o.reqHeaders),
+		respHeaders: slices.Clone(o.respHeaders),
+		proxies:     slices.Clone(o.proxies),
+		identity:    o.identity,
+		reqIDHeader: o.reqIDHeader,
+		corrHeader:  o.corrHeader,
+		traceHeader: o.traceHeader,
+		recovers:    o.recovers,
+		repanic:     o.repanic,
+		cancelLevel: o.cancelLevel,
+		phaseTiming: o.phaseTiming,
+		audit:       audit,
+	}
+}
+
+// handlerOptions are the exporterOptions of the handler of an exporter
+type handlerOptions struct {
+	logAll      bool
+	minLevelVar *slog.LevelVar              // nil for slog.LevelDebug
+	statusLevel func(status int) slog.Level // nil for DefaultStatusLevel
+	errorStack  bool
+	spanEvents  bool
+	metrics     MetricsRecorder
+	tracing     trace.TracerProvider
+	execTrace   bool
+	propagator  propagation.TextMapPropagator
+	caller      bool
+	callerSkip  int
+	compLevels  map[string]slog.Level
+	dedupe      time.Duration
+	maxLogs     int
+	parentErrs  int
+	levelCounts bool
+	maxMsgLen   int
+	maxAttrSize int
+	attrFilter  *attrFilter // nil to write all attributes
+	processors  processors
+	scrubParams []string
+	staticAttrs map[string]any
+	parentAttrs map[string]any // attributes added to every parent log
+	excludes    *pathFilter    // nil to log all paths
+	routes      []RouteConfig
+	sampleRate  float64 // fraction of successful parent logs written
+	slowRequest time.Duration
+	followTrace bool
+	pathSamples pathSamples
+	bodyCapture *BodyCapture // nil to not capture request bodies
+	reqHeaders  []string
+	respHeaders []string
+	proxies     []netip.Prefix // trusted proxies, nil to use the address of the peer
+	identity    IdentityExtractor
+	reqIDHeader string
+	corrHeader  string
+	traceHeader string
+	recovers    bool
+	repanic     bool
+	cancelLevel *slog.Level // level of Error logs after the client disconnected, nil to keep
+	phaseTiming bool
+	audit       *auditChain
+}
+
+// requestLogger is the root logger of a request served by the handler of an exporter
+type requestLogger interface {
+	ctxLogger
+
+	// addStaticAttribute adds an attribute to the child logs and the parent log of the request
+	addStaticAttribute(key string, value any)
+
+	// setDisconnect sets the watcher of the client of the request
+	setDisconnect(w *disconnectWatcher)
+
+	// stopLimit stops dropping the child logs after MaxLogs, and returns the number of logs dropped
+	stopLimit() int
+
+	// requestAttribute returns the value of the attribute of the parent log
+	requestAttribute(key string) any
+}
+
+// route returns the minimum level of the child logs of the route of the request, nil when not overridden,
+// and if the parent logs of the route are written for requests without logs
+func (h *handlerOptions) route(r *http.Request) (*slog.Level, bool) {
+	route, ok := matchRoute(h.routes, r.URL.Path)
+	if ok && route.logAll != nil {
+		return route.minLevel, *route.logAll
+	}
+
+	return route.minLevel, h.logAll
+}
+
+// startRequest adds the attributes of the request to l before it is served, and returns the request with the
+// context of l, the counter of the request body, and the watcher of the client
+func (h *handlerOptions) startRequest(w http.ResponseWriter, r *http.Request, l requestLogger, begin time.Time) (*http.Request, *bodyCounter, *disconnectWatcher) {
+	for k, v := range h.parentAttrs {
+		l.AddRequestAttribute(k, v)
+	}
+	for k, v := range h.staticAttrs {
+		l.addStaticAttribute(k, v)
+	}
+	if id, ok := requestID(r, h.reqIDHeader); ok {
+		l.addStaticAttribute(requestIDKey, id)
+		w.Header().Set(h.reqIDHeader, id)
+		r = r.WithContext(newRequestIDContext(r.Context(), id))
+	}
+	if id, ok := correlationID(r, h.corrHeader); ok {
+		l.addStaticAttribute(correlationIDKey, id)
+		r = r.WithContext(newCorrelationContext(r.Context(), h.corrHeader, id))
+	}
+	r = r.WithContext(newPropagatorContext(r.Context(), h.propagator))
+	if h.traceHeader != "" {
+		w.Header().Set(h.traceHeader, l.TraceID())
+	}
+	reqBody := countBody(r)
+	if body, ok := h.bodyCapture.capture(r); ok {
+		l.AddRequestAttribute(requestBodyKey, body)
+	}
+	for k, v := range headerAttributes(requestHeaderPrefix, r.Header, h.reqHeaders) {
+		l.AddRequestAttribute(k, v)
+	}
+	addIdentity(l, r, h.identity)
+	disconnect := watchDisconnect(r.Context(), begin, h.cancelLevel)
+	l.setDisconnect(disconnect)
+
+	return r.WithContext(newAuditContext(newContext(r.Context(), l), h.audit)), reqBody, disconnect
+}
+
+// serve serves the request with next, and returns the value of a panic of next that is raised again after
+// the parent log is written, or nil
+func (h *handlerOptions) serve(next http.Handler, sw responseRecorder, r *http.Request, l ctxLogger) any {
+	if v := serveRecover(l, next, sw, r, h.recovers); v != nil && (!h.recovers || h.repanic || v == http.ErrAbortHandler) {
+		return v
+	}
+
+	return nil
+}
+
+// droppedLogsMessage is the message of the final log of a request that reached the MaxLogs limit
+const droppedLogsMessage = "%d additional log entries suppressed"
+
+// endRequest adds the attributes of the response to l after the request is served, and records its metrics.
+// It returns the level of the gRPC status of the response, and false when the parent log is excluded.
+func (h *handlerOptions) endRequest(r *http.Request, sw responseRecorder, l requestLogger, disconnect *disconnectWatcher, begin time.Time) (slog.Level, bool) {
+	for k, v := range responseAttributes(sw, h.respHeaders, h.phaseTiming) {
+		l.AddRequestAttribute(k, v)
+	}
+	trailers, grpcLevel := trailerAttributes(sw.Header())
+	for k, v := range trailers {
+		l.AddRequestAttribute(k, v)
+	}
+	if elapsed, ok := disconnect.disconnected(); ok {
+		l.AddRequestAttribute(clientDisconnectedKey, true)
+		l.AddRequestAttribute(clientDisconnectedElapsedKey, elapsed.String())
+	}
+
+	if dropped := l.stopLimit(); dropped > 0 {
+		l.Warnf(r.Context(), droppedLogsMessage, dropped)
+	}
+
+	if h.metrics != nil {
+		route, _ := l.requestAttribute(httpRouteKey).(string)
+		h.metrics.RecordRequest(requestMetrics(r, sw, begin, route))
+	}
+
+	return grpcLevel, !h.excludes.excluded(r.URL.Path)
+}
+
+// parentLog returns the parent log entry of the request passed through the processors, and false when it is
+// not written. The entry is not written for requests without logs unless logAll is set, and for the successful
+// requests that are not sampled by FollowTraceSampling, SampleSuccess, or SamplePath. Slow requests are always
+// written, at Warning or above.
+func (h *handlerOptions) parentLog(r *http.Request, entry Entry, logAll bool, logCount int) (Entry, bool) {
+	sampleCount, pathSampled := h.pathSamples.sample(r.URL.Path)
+
+	sampled := h.followTrace && traceSampled(r, h.propagator)
+	if h.followTrace {
+		logAll = sampled
+	}
+	// slow requests are always logged, so they are checked before the requests without logs are suppressed
+	elapsed := time.Since(entry.Time)
+	slow := slowRequest(h.slowRequest, elapsed)
+	if !logAllRequests(logAll) && logCount == 0 && !slow {
+		return Entry{}, false
+	}
+
+	if slow {
+		entry.Attributes = withAttribute(entry.Attributes, slowRequestKey, true)
+		entry.Level = max(entry.Level, slog.LevelWarn)
+	}
+
+	if !sampled && !sampleRequest(successSampleRate(h.sampleRate), h.slowRequest, elapsed, entry.Status, entry.Level) {
+		return Entry{}, false
+	}
+
+	if !pathSampled && entry.Status < http.StatusBadRequest && entry.Level < slog.LevelWarn {
+		return Entry{}, false
+	}
+	if sampleCount > 0 {
+		entry.Attributes = withAttribute(entry.Attributes, sampleCountKey, sampleCount)
+	}
+
+	return h.processors.process(entry)
+}
+
+// withAttribute returns a copy of attributes with the attribute (key, value), so the attributes of the
+// logger are not changed
+func withAttribute(attributes map[string]any, key string, value any) map[string]any {
+	attributes = maps.Clone(attributes)
+	if attributes == nil {
+		attributes = make(map[string]any)
+	}
+	attributes[key] = value
+
+	return attributes
+}
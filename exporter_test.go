@@ -0,0 +1,145 @@
+package logger
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_exporterOptions(t *testing.T) {
+	t.Parallel()
+
+	gcp := NewGoogleCloudExporter(nil, "project")
+	if got := gcp.Dedupe(time.Second).LogAll(false); got != gcp || got.dedupe != time.Second {
+		t.Errorf("GoogleCloudExporter.Dedupe() = %p, want %p", got, gcp)
+	}
+	aws := NewAWSExporter(true)
+	if got := aws.MaxLogs(10).Writer(nil); got != aws || got.maxLogs != 10 {
+		t.Errorf("AWSExporter.MaxLogs() = %p, want %p", got, aws)
+	}
+	console := NewConsoleExporter()
+	if got := console.LevelCounts(true).NoColor(true); got != console || !got.levelCounts {
+		t.Errorf("ConsoleExporter.LevelCounts() = %p, want %p", got, console)
+	}
+}
+
+func Test_handlerOptions_route(t *testing.T) {
+	t.Parallel()
+
+	debug := slog.LevelDebug
+	h := handlerOptions{
+		logAll: true,
+		routes: cloneRoutes([]*RouteConfig{Route("/quiet").LogAll(false), Route("/debug").MinLevel(debug)}),
+	}
+	tests := []struct {
+		name         string
+		path         string
+		wantMinLevel *slog.Level
+		wantLogAll   bool
+	}{
+		{name: "no route", path: "/", wantLogAll: true},
+		{name: "log all", path: "/quiet", wantLogAll: false},
+		{name: "min level", path: "/debug", wantMinLevel: &debug, wantLogAll: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			minLevel, logAll := h.route(httptest.NewRequest(http.MethodGet, tt.path, http.NoBody))
+			if diff := cmp.Diff(tt.wantMinLevel, minLevel); diff != "" {
+				t.Errorf("handlerOptions.route() minLevel mismatch (-want +got):\n%s", diff)
+			}
+			if logAll != tt.wantLogAll {
+				t.Errorf("handlerOptions.route() logAll = %v, want %v", logAll, tt.wantLogAll)
+			}
+		})
+	}
+}
+
+func Test_handlerOptions_parentLog(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		begin    time.Time
+		status   int
+		level    slog.Level
+		logAll   bool
+		logCount int
+	}
+	tests := []struct {
+		name  string
+		h     handlerOptions
+		args  args
+		want  Entry
+		want1 bool
+	}{
+		{
+			name:  "log all",
+			h:     handlerOptions{sampleRate: 1},
+			args:  args{status: http.StatusOK, level: slog.LevelInfo, logAll: true},
+			want:  Entry{Level: slog.LevelInfo, Request: true, Status: http.StatusOK},
+			want1: true,
+		},
+		{
+			name: "request without logs",
+			h:    handlerOptions{sampleRate: 1},
+			args: args{status: http.StatusOK, level: slog.LevelInfo},
+		},
+		{
+			name:  "request with logs",
+			h:     handlerOptions{sampleRate: 1},
+			args:  args{status: http.StatusOK, level: slog.LevelInfo, logCount: 1},
+			want:  Entry{Level: slog.LevelInfo, Request: true, Status: http.StatusOK},
+			want1: true,
+		},
+		{
+			name:  "slow request without logs",
+			h:     handlerOptions{sampleRate: 1, slowRequest: time.Second},
+			args:  args{begin: time.Now().Add(-time.Minute), status: http.StatusOK, level: slog.LevelInfo},
+			want:  Entry{Level: slog.LevelWarn, Attributes: map[string]any{slowRequestKey: true}, Request: true, Status: http.StatusOK},
+			want1: true,
+		},
+		{
+			name: "successful request not sampled",
+			h:    handlerOptions{sampleRate: 0},
+			args: args{status: http.StatusOK, level: slog.LevelInfo, logAll: true},
+		},
+		{
+			name:  "failed request not sampled",
+			h:     handlerOptions{sampleRate: 0},
+			args:  args{status: http.StatusInternalServerError, level: slog.LevelError, logAll: true},
+			want:  Entry{Level: slog.LevelError, Request: true, Status: http.StatusInternalServerError},
+			want1: true,
+		},
+		{
+			name: "trace not sampled",
+			h:    handlerOptions{sampleRate: 1, followTrace: true, propagator: consolePropagator},
+			args: args{status: http.StatusOK, level: slog.LevelInfo, logAll: true},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			begin := tt.args.begin
+			if begin.IsZero() {
+				begin = time.Now()
+			}
+			if tt.want1 {
+				tt.want.Time = begin
+			}
+			entry := Entry{Time: begin, Level: tt.args.level, Request: true, Status: tt.args.status}
+			got, got1 := tt.h.parentLog(httptest.NewRequest(http.MethodGet, "/", http.NoBody), entry, tt.args.logAll, tt.args.logCount)
+			if got1 != tt.want1 {
+				t.Fatalf("handlerOptions.parentLog() got1 = %v, want %v", got1, tt.want1)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("handlerOptions.parentLog() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+)
+
+// attrFilter drops user attributes by key pattern before they are written. The
+// patterns use the syntax of path.Match. A nil attrFilter keeps all attributes.
+type attrFilter struct {
+	allow []string // attributes must match one of the patterns, empty to allow all
+	deny  []string // attributes matching one of the patterns are dropped
+}
+
+// clone returns a copy of the filter, so options changed after the middleware is created are not applied
+func (f *attrFilter) clone() *attrFilter {
+	if f == nil {
+		return nil
+	}
+
+	return &attrFilter{allow: slices.Clone(f.allow), deny: slices.Clone(f.deny)}
+}
+
+// keep reports whether the attribute with key is written
+func (f *attrFilter) keep(key string) bool {
+	if f == nil {
+		return true
+	}
+	if matchAny(f.deny, key) {
+		return false
+	}
+
+	return len(f.allow) == 0 || matchAny(f.allow, key)
+}
+
+// apply returns the attributes that are kept, or attributes itself when all are kept
+func (f *attrFilter) apply(attributes map[string]any) map[string]any {
+	if f == nil {
+		return attributes
+	}
+
+	kept := make(map[string]any, len(attributes))
+	for k, v := range attributes {
+		if f.keep(k) {
+			kept[k] = v
+		}
+	}
+
+	return kept
+}
+
+// matchAny reports whether key matches one of the patterns, malformed patterns match nothing
+func matchAny(patterns []string, key string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, key); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pathFilter matches the request paths that are excluded from parent logs. A nil pathFilter excludes nothing.
+type pathFilter struct {
+	globs   []string // patterns with the syntax of path.Match
+	regexps []*regexp.Regexp
+}
+
+// clone returns a copy of the filter, so options changed after the middleware is created are not applied
+func (f *pathFilter) clone() *pathFilter {
+	if f == nil {
+		return nil
+	}
+
+	return &pathFilter{globs: slices.Clone(f.globs), regexps: slices.Clone(f.regexps)}
+}
+
+// excluded reports whether the parent log of the request path is excluded
+func (f *pathFilter) excluded(p string) bool {
+	if f == nil {
+		return false
+	}
+	if matchAny(f.globs, p) {
+		return true
+	}
+
+	return slices.ContainsFunc(f.regexps, func(re *regexp.Regexp) bool { return re.MatchString(p) })
+}
+
+// DropRule drops the child logs that match all of its conditions, e.g. the known noisy logs of a library:
+//
+//	exporter.DropLogs(
+//		logger.DropRule{MessagePattern: regexp.MustCompile(`^http: TLS handshake error`)},
+//		logger.DropRule{Attribute: "http.client.url", ValuePattern: regexp.MustCompile(`/healthz$`)},
+//	)
+type DropRule struct {
+	// MessagePattern matches the message of the log, nil matches any message
+	MessagePattern *regexp.Regexp
+	// Attribute is the key of the attribute matched by ValuePattern
+	Attribute string
+	// ValuePattern matches the value of the Attribute formatted with fmt.Sprint, nil matches any log. Logs without
+	// the attribute do not match.
+	ValuePattern *regexp.Regexp
+}
+
+// matches reports whether the entry matches all the conditions of the rule. The request log never matches.
+func (r DropRule) matches(e Entry) bool {
+	if e.Request {
+		return false
+	}
+	if r.MessagePattern != nil && !r.MessagePattern.MatchString(e.Message) {
+		return false
+	}
+	if r.ValuePattern != nil {
+		v, ok := e.Attributes[r.Attribute]
+		if !ok || !r.ValuePattern.MatchString(fmt.Sprint(v)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// dropProcessor returns a Processor that drops the entries matching one of the rules
+func dropProcessor(rules []DropRule) Processor {
+	rules = slices.Clone(rules)
+
+	return func(e Entry) (Entry, bool) {
+		return e, !slices.ContainsFunc(rules, func(r DropRule) bool { return r.matches(e) })
+	}
+}
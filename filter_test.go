@@ -0,0 +1,138 @@
+package logger
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_attrFilter_apply(t *testing.T) {
+	t.Parallel()
+
+	attributes := map[string]any{"user": "bob", "internal.id": 1, "internal": 2, "http.route": "/"}
+	tests := []struct {
+		name   string
+		filter *attrFilter
+		want   map[string]any
+	}{
+		{
+			name: "nil filter",
+			want: attributes,
+		},
+		{
+			name:   "deny",
+			filter: &attrFilter{deny: []string{"internal.*"}},
+			want:   map[string]any{"user": "bob", "internal": 2, "http.route": "/"},
+		},
+		{
+			name:   "allow",
+			filter: &attrFilter{allow: []string{"user", "http.*"}},
+			want:   map[string]any{"user": "bob", "http.route": "/"},
+		},
+		{
+			name:   "deny takes precedence",
+			filter: &attrFilter{allow: []string{"*"}, deny: []string{"internal*"}},
+			want:   map[string]any{"user": "bob", "http.route": "/"},
+		},
+		{
+			name:   "malformed pattern",
+			filter: &attrFilter{deny: []string{"[user"}},
+			want:   attributes,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(tt.want, tt.filter.apply(attributes)); diff != "" {
+				t.Errorf("apply() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_pathFilter_excluded(t *testing.T) {
+	t.Parallel()
+
+	filter := &pathFilter{
+		globs:   []string{"/healthz", "/debug/*"},
+		regexps: []*regexp.Regexp{regexp.MustCompile(`^/metrics(/|$)`)},
+	}
+	tests := []struct {
+		name   string
+		filter *pathFilter
+		path   string
+		want   bool
+	}{
+		{name: "nil filter", path: "/healthz"},
+		{name: "glob", filter: filter, path: "/healthz", want: true},
+		{name: "glob wildcard", filter: filter, path: "/debug/vars", want: true},
+		{name: "glob single segment", filter: filter, path: "/debug/pprof/heap"},
+		{name: "regexp", filter: filter, path: "/metrics", want: true},
+		{name: "regexp prefix", filter: filter, path: "/metricsx"},
+		{name: "not excluded", filter: filter, path: "/api/users"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.filter.excluded(tt.path); got != tt.want {
+				t.Errorf("excluded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_dropProcessor(t *testing.T) {
+	t.Parallel()
+
+	process := dropProcessor([]DropRule{
+		{MessagePattern: regexp.MustCompile(`^http: TLS handshake error`)},
+		{Attribute: "http.client.url", ValuePattern: regexp.MustCompile(`/healthz$`)},
+		{MessagePattern: regexp.MustCompile(`^cache miss`), Attribute: "cache", ValuePattern: regexp.MustCompile(`^sessions$`)},
+	})
+
+	tests := []struct {
+		name     string
+		entry    Entry
+		wantDrop bool
+	}{
+		{
+			name:     "message pattern",
+			entry:    Entry{Level: LevelError, Message: "http: TLS handshake error from 10.0.0.1:5555: EOF"},
+			wantDrop: true,
+		},
+		{
+			name:     "attribute value",
+			entry:    Entry{Level: LevelInfo, Message: "GET https://example.com/healthz 200", Attributes: map[string]any{"http.client.url": "https://example.com/healthz"}},
+			wantDrop: true,
+		},
+		{
+			name:  "attribute value not matching",
+			entry: Entry{Level: LevelInfo, Message: "GET https://example.com/users 200", Attributes: map[string]any{"http.client.url": "https://example.com/users"}},
+		},
+		{
+			name:     "all conditions",
+			entry:    Entry{Level: LevelDebug, Message: "cache miss", Attributes: map[string]any{"cache": "sessions"}},
+			wantDrop: true,
+		},
+		{
+			name:  "missing attribute",
+			entry: Entry{Level: LevelDebug, Message: "cache miss"},
+		},
+		{
+			name:  "request log",
+			entry: Entry{Level: LevelInfo, Request: true, Attributes: map[string]any{"http.client.url": "https://example.com/healthz"}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, ok := process(tt.entry); ok == tt.wantDrop {
+				t.Errorf("dropProcessor() ok = %v, want %v", ok, !tt.wantDrop)
+			}
+		})
+	}
+}
@@ -1,36 +1,63 @@
 package logger
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
 	"slices"
+	"strings"
 	"sync"
 	"time"
 
 	"cloud.google.com/go/logging"
-	"contrib.go.opencensus.io/exporter/stackdriver/propagation"
+	"cloud.google.com/go/logging/apiv2/loggingpb"
+	"github.com/go-playground/errors/v5"
 	"go.opentelemetry.io/otel/trace"
 )
 
-const gcpMessageKey = "message"
+const (
+	gcpMessageKey    = "message"
+	gcpLogTypeKey    = "log_type"
+	gcpErrorKindKey  = "error.kind"
+	gcpErrorStackKey = "error.stack"
+
+	gcpParentLogName = "request_parent_log"
+	gcpChildLogName  = "request_child_log"
+	gcpSingleLogName = "request_log"
+	gcpAuditLogName  = "audit_log"
+)
 
 // GoogleCloudExporter implements exporting to Google Cloud Logging
 type GoogleCloudExporter struct {
-	projectID string
-	client    *logging.Client
-	opts      []logging.LoggerOption
-	logAll    bool
+	exporterOptions[*GoogleCloudExporter]
+	projectID    string
+	client       *logging.Client
+	opts         []logging.LoggerOption
+	parentClient *logging.Client
+	parentOpts   []logging.LoggerOption
+	tracePrefix  string
+	insertID     bool
+	singleLog    bool
+	auditLog     string
 }
 
 // NewGoogleCloudExporter returns a configured GoogleCloudExporter
+//
+// Errors that occur while asynchronously writing logs to Cloud Logging are reported to the OnError of the
+// client, so applications can detect failed log delivery by setting OnError on the client before it is
+// used. The exporter does not change the OnError of the client, which may be shared with other exporters.
 func NewGoogleCloudExporter(client *logging.Client, projectID string, opts ...logging.LoggerOption) *GoogleCloudExporter {
-	return &GoogleCloudExporter{
+	e := &GoogleCloudExporter{
 		projectID: projectID,
 		client:    client,
 		opts:      opts,
-		logAll:    true,
 	}
+	e.exporterOptions = exporterOptions[*GoogleCloudExporter]{self: e, logAll: true, minLevelVar: newLevelVar(slog.LevelDebug)}
+
+	return e
 }
 
 // LogAll controls if this logger will log all requests, or only requests that contain
@@ -41,114 +68,329 @@ func (e *GoogleCloudExporter) LogAll(v bool) *GoogleCloudExporter {
 	return e
 }
 
+// GenerateInsertID controls if this logger will set a unique InsertID on each log entry (default: false)
+//
+// The InsertID is generated once per entry, so retried deliveries of the same entry are
+// de-duplicated by Cloud Logging instead of creating duplicate entries.
+func (e *GoogleCloudExporter) GenerateInsertID(v bool) *GoogleCloudExporter {
+	e.insertID = v
+
+	return e
+}
+
+// ParentClient configures a separate client for the parent request logs, allowing request logs
+// to be written to a different project than the child (application) logs. The projectID passed
+// to NewGoogleCloudExporter is still used to correlate both logs to Cloud Trace.
+//
+// If not set, the parent request logs are written using the client passed to NewGoogleCloudExporter.
+// Write errors of the parent request logs are reported to the OnError of this client.
+func (e *GoogleCloudExporter) ParentClient(client *logging.Client, opts ...logging.LoggerOption) *GoogleCloudExporter {
+	e.parentClient = client
+	e.parentOpts = opts
+
+	return e
+}
+
+// TracePrefix overrides the prefix of the trace resource name used to correlate logs to Cloud Trace
+// (default: "projects/{projectID}/traces/"). The raw trace ID is appended to the prefix.
+func (e *GoogleCloudExporter) TracePrefix(prefix string) *GoogleCloudExporter {
+	e.tracePrefix = prefix
+
+	return e
+}
+
+// SingleLog controls if this logger will write both the parent and child logs to a single
+// log named "request_log" (default: false). When enabled, entries are distinguished by a
+// "log_type" attribute with a value of "parent" or "child".
+func (e *GoogleCloudExporter) SingleLog(v bool) *GoogleCloudExporter {
+	e.singleLog = v
+
+	return e
+}
+
+// MinLevel sets the minimum level of the child logs, e.g. LevelTrace writes Trace logs with the
+// Default severity. The parent request log is always written. (default: slog.LevelDebug)
+func (e *GoogleCloudExporter) MinLevel(level slog.Level) *GoogleCloudExporter {
+	e.SetMinLevel(level)
+
+	return e
+}
+
+// SetMinLevel changes the minimum level of the child logs, and is safe to call at runtime after the
+// Middleware is created, e.g. to temporarily write Debug logs. The level applies to the following requests.
+func (e *GoogleCloudExporter) SetMinLevel(level slog.Level) {
+	e.setMinLevel(level)
+}
+
+// MaxMessageLength sets the maximum length in bytes of the message of child logs. Longer messages
+// are truncated and end with "...[truncated]" (default: 0, unlimited)
+func (e *GoogleCloudExporter) MaxMessageLength(n int) *GoogleCloudExporter {
+	e.maxMsgLen = n
+
+	return e
+}
+
+// MaxAttributeSize sets the maximum size in bytes of the JSON serialization of an attribute value.
+// Larger values are replaced with their serialization, truncated and ending with "...[truncated]",
+// so that a single large attribute does not exceed the 256KB log entry limit of Cloud Logging (default: 0, unlimited)
+func (e *GoogleCloudExporter) MaxAttributeSize(n int) *GoogleCloudExporter {
+	e.maxAttrSize = n
+
+	return e
+}
+
+// FollowTraceSampling makes the parent logs follow the OpenTelemetry sampling decision of the request. The
+// parent logs of sampled traces are always written, overriding LogAll and SampleSuccess, and the parent logs
+// of other requests are written only if they contain logs, so that every sampled trace has its request log (default: false)
+func (e *GoogleCloudExporter) FollowTraceSampling(v bool) *GoogleCloudExporter {
+	e.followTrace = v
+
+	return e
+}
+
+// SampleSuccess writes only a fraction (between 0 and 1) of the parent logs of successful requests, reducing the
+// cost of high traffic services. Requests with an error status, Warning or above logs, or slower than SlowRequest
+// are always written. Child logs are not sampled (default: 1, all requests)
+func (e *GoogleCloudExporter) SampleSuccess(rate float64) *GoogleCloudExporter {
+	e.sampling, e.sampleRate = true, rate
+
+	return e
+}
+
+// AuditLog sets the name of the log that audit events written with Logger.Audit are sent to, separate
+// from the request logs (default: "audit_log")
+func (e *GoogleCloudExporter) AuditLog(logID string) *GoogleCloudExporter {
+	e.auditLog = logID
+
+	return e
+}
+
 // Middleware returns a middleware that exports logs to Google Cloud Logging
 func (e *GoogleCloudExporter) Middleware() func(http.Handler) http.Handler {
+	parentClient, parentOpts := e.client, e.opts
+	if e.parentClient != nil {
+		parentClient, parentOpts = e.parentClient, e.parentOpts
+	}
+
+	parentLogName, childLogName := gcpParentLogName, gcpChildLogName
+	if e.singleLog {
+		parentLogName, childLogName = gcpSingleLogName, gcpSingleLogName
+	}
+
+	labels := gcpServerlessLabels(os.Getenv)
+	client, auditLog, opts := e.client, cmp.Or(e.auditLog, gcpAuditLogName), e.opts
+	auditLogger := sync.OnceValue(func() logger { return client.Logger(auditLog, opts...) }) // created on the first audit event
+	audit := newAuditChain(func(_ context.Context, traceID, _ string, attrs map[string]any) {
+		auditLogger().Log(logging.Entry{Timestamp: time.Now(), Severity: logging.Notice, Trace: traceID, Payload: attrs, Labels: labels})
+	})
+
 	return func(next http.Handler) http.Handler {
 		return &gcpHandler{
-			next:         next,
-			parentLogger: e.client.Logger("request_parent_log", e.opts...),
-			childLogger:  e.client.Logger("request_child_log", e.opts...),
-			projectID:    e.projectID,
-			logAll:       e.logAll,
+			handlerOptions: e.handlerOptions(gcpPropagator, audit),
+			next:           next,
+			parentLogger:   parentClient.Logger(parentLogName, parentOpts...),
+			childLogger:    e.client.Logger(childLogName, e.opts...),
+			projectID:      e.projectID,
+			tracePrefix:    e.tracePrefix,
+			insertID:       e.insertID,
+			singleLog:      e.singleLog,
+			labels:         labels,
 		}
 	}
 }
 
 type gcpHandler struct {
+	handlerOptions
 	next         http.Handler
 	parentLogger logger
 	childLogger  logger
 	projectID    string
-	logAll       bool
+	tracePrefix  string
+	insertID     bool
+	singleLog    bool
+	labels       map[string]string // labels of the Cloud Run service or Cloud Function, nil when not running in one
 }
 
 func (g *gcpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	begin := time.Now()
-	traceID := gcpTraceIDFromRequest(r, g.projectID, generateID)
-	l := newGCPLogger(g.childLogger, traceID)
-	r = r.WithContext(newContext(r.Context(), l))
+	r, span := startServerSpan(r, g.tracing, g.propagator)
+	rawTraceID := traceIDFromRequest(r, g.propagator, generateID)
+	traceID := g.traceName(rawTraceID)
+	minLevel, logAll := g.route(r)
+	l := g.newLogger(traceID, rawTraceID, minLevel)
+	r, task := startExecutionTask(r, g.execTrace, rawTraceID)
+	r, reqBody, disconnect := g.startRequest(w, r, l, begin)
 	sw := newResponseRecorder(w)
 
-	g.next.ServeHTTP(sw, r)
-
-	l.mu.Lock()
-	logCount := l.logCount
-	maxSeverity := l.maxSeverity
-	attributes := make(map[string]any)
-	for k, v := range l.reqAttributes {
-		attributes[k] = v
+	if v := g.serve(g.next, sw, r, l); v != nil {
+		defer panic(v)
 	}
-	l.mu.Unlock()
-
-	if !g.logAll && logCount == 0 {
+	endServerSpan(span, sw.Status())
+	endExecutionTask(task)
+	grpcLevel, ok := g.endRequest(r, sw, l, disconnect, begin)
+	if !ok {
 		return
 	}
 
-	// status code should also set the minimum maxSeverity to Error
-	if sw.Status() > 499 && maxSeverity < logging.Error {
-		maxSeverity = logging.Error
+	logCount, maxSeverity, attributes := l.parentAttributes()
+
+	// status code and gRPC status should also set the minimum maxSeverity, when above Info so the parent logs of
+	// requests without logs keep the Default severity
+	if level := max(statusLevel(g.statusLevel, sw.Status()), grpcLevel); level > slog.LevelInfo && maxSeverity < levelSeverity(level) {
+		maxSeverity = levelSeverity(level)
+	}
+
+	entry, ok := g.parentLog(r, Entry{Time: begin, Level: severityLevel(maxSeverity), Attributes: attributes, TraceID: rawTraceID, Request: true, Status: sw.Status()}, logAll, logCount)
+	if !ok {
+		return
 	}
+	maxSeverity, attributes = levelSeverity(entry.Level), entry.Attributes
 
 	sc := trace.SpanFromContext(r.Context()).SpanContext()
 
 	attributes[gcpMessageKey] = parentLogEntry
+	if g.singleLog {
+		attributes[gcpLogTypeKey] = "parent"
+	}
 
 	g.parentLogger.Log(logging.Entry{
+		InsertID:     l.insertID(0),
 		Timestamp:    begin,
 		Severity:     maxSeverity,
 		Trace:        traceID,
 		SpanID:       sc.SpanID().String(),
 		TraceSampled: sc.IsSampled(),
 		Payload:      attributes,
+		Labels:       g.labels,
 		HTTPRequest: &logging.HTTPRequest{
-			Request:      r,
-			RequestSize:  requestSize(r.Header.Get("Content-Length")),
+			Request:      scrubQuery(r, g.scrubParams),
+			RequestSize:  reqBody.size(r),
 			Latency:      time.Since(begin),
 			Status:       sw.Status(),
 			ResponseSize: sw.Length(),
-			RemoteIP:     r.Header.Get("X-Forwarded-For"),
+			RemoteIP:     remoteIP(r, g.proxies),
+			LocalIP:      localIP(r),
+			CacheHit:     gcpCacheHit(sw.Header()),
 		},
 	})
 }
 
-// gcpTraceIDFromRequest formats a trace_id value for GCP Stackdriver
-func gcpTraceIDFromRequest(r *http.Request, projectID string, idgen func() string) string {
-	var traceID string
-	if sc := trace.SpanFromContext(r.Context()).SpanContext(); sc.IsValid() {
-		traceID = sc.TraceID().String()
-	} else {
-		if sc1, ok := new(propagation.HTTPFormat).SpanContextFromRequest(r); ok {
-			traceID = sc1.TraceID.String()
-		} else {
-			traceID = idgen()
+// newLogger returns the root logger of a request, with the minimum level of the route, nil for MinLevel
+func (g *gcpHandler) newLogger(traceID, rawTraceID string, minLevel *slog.Level) *gcpLogger {
+	l := newGCPLogger(g.childLogger, traceID, rawTraceID)
+	l.minSeverity = minSeverity(g.minLevelVar)
+	if minLevel != nil {
+		l.minSeverity = levelSeverity(*minLevel)
+	}
+	l.errorStack = g.errorStack
+	l.spanEvents = g.spanEvents
+	l.metrics = g.metrics
+	l.caller, l.callerSkip = g.caller, g.callerSkip
+	l.compLevels = g.compLevels
+	l.dedupe = newDeduper(g.dedupe)
+	l.errSummary = newErrorSummary(g.parentErrs)
+	l.levelCounts = newLevelCounter(g.levelCounts)
+	l.maxLogs = g.maxLogs
+	l.maxMsgLen, l.maxAttrSize = g.maxMsgLen, g.maxAttrSize
+	l.attrFilter = g.attrFilter
+	l.processors = g.processors
+	l.labels = g.labels
+	if g.insertID {
+		l.insertIDPrefix = generateID()
+	}
+	if g.singleLog {
+		l.singleLog = true
+		l.rsvdKeys = append(l.rsvdKeys, gcpLogTypeKey)
+	}
+
+	return l
+}
+
+// gcpCacheHit reports if the response was served from a cache, as indicated by an X-Cache response header
+// set by a caching proxy (e.g. "HIT" or "HIT from proxy").
+func gcpCacheHit(h http.Header) bool {
+	return strings.HasPrefix(strings.ToUpper(h.Get("X-Cache")), "HIT")
+}
+
+// gcpServerlessLabels returns the labels identifying the Cloud Run service and revision, or the Cloud Function,
+// from the environment variables set by the runtime, or nil when not running in Cloud Run or Cloud Functions.
+// The labels are written on the entries so that they can be filtered by even with a custom MonitoredResource.
+func gcpServerlessLabels(getenv func(string) string) map[string]string {
+	labels := make(map[string]string)
+	for label, env := range map[string]string{
+		"service_name":       "K_SERVICE",
+		"revision_name":      "K_REVISION",
+		"configuration_name": "K_CONFIGURATION",
+		"function_name":      "FUNCTION_NAME",
+		"function_target":    "FUNCTION_TARGET",
+	} {
+		if v := getenv(env); v != "" {
+			labels[label] = v
 		}
 	}
+	if len(labels) == 0 {
+		return nil
+	}
 
-	return fmt.Sprintf("projects/%s/traces/%s", projectID, traceID)
+	return labels
+}
+
+// traceName formats a trace_id value for GCP Stackdriver
+func (g *gcpHandler) traceName(traceID string) string {
+	if g.tracePrefix != "" {
+		return g.tracePrefix + traceID
+	}
+
+	return fmt.Sprintf("projects/%s/traces/%s", g.projectID, traceID)
 }
 
 // logger interface exists for testability
 type logger interface {
 	Log(e logging.Entry)
+	Flush() error
 }
 
 type gcpLogger struct {
-	root          *gcpLogger
-	logger        logger
-	traceID       string
-	rsvdKeys      []string
-	attributes    map[string]any // attributes for child (trace) logs
-	mu            sync.Mutex
-	maxSeverity   logging.Severity
-	logCount      int
-	reqAttributes map[string]any // attributes for the parent request log
-}
-
-func newGCPLogger(lg logger, traceID string) *gcpLogger {
+	root           *gcpLogger
+	logger         logger
+	traceID        string
+	rawTraceID     string
+	insertIDPrefix string           // prefix for generated InsertIDs, empty when disabled
+	singleLog      bool             // parent and child logs share a log name, so child logs are marked with log_type
+	minSeverity    logging.Severity // child logs below minSeverity are dropped
+	errorStack     bool             // Error and above child logs include the stack trace
+	spanEvents     bool             // child logs are added as events of the span of the context
+	metrics        MetricsRecorder  // child logs are recorded by level, nil when disabled
+	caller         bool             // child logs include the source location of the caller
+	callerSkip     int              // additional frames skipped to find the caller
+	callerPkg      string           // function name prefix of the adapted library, whose first frames are skipped to find the caller
+	component      string           // name of the component, empty when not Named
+	compLevels     map[string]slog.Level
+	dedupe         *deduper      // shared by the logger and its children, nil when disabled
+	errSummary     *errorSummary // shared by the logger and its children, nil when disabled
+	maxMsgLen      int           // messages are truncated to maxMsgLen bytes, 0 for unlimited
+	maxAttrSize    int           // attribute values are truncated to maxAttrSize bytes, 0 for unlimited
+	attrFilter     *attrFilter
+	processors     processors
+	labels         map[string]string
+	rsvdKeys       []string
+	attributes     map[string]any // attributes for child (trace) logs
+	mu             sync.Mutex
+	maxSeverity    logging.Severity
+	logCount       int
+	maxLogs        int                // child logs after maxLogs are dropped, 0 for unlimited
+	dropped        int                // number of child logs dropped by maxLogs
+	levelCounts    *levelCounter      // child logs counted by level for the parent log, nil when disabled
+	disconnect     *disconnectWatcher // watches the client of the request, nil outside of requests
+	reqAttributes  map[string]any     // attributes for the parent request log
+}
+
+func newGCPLogger(lg logger, traceID, rawTraceID string) *gcpLogger {
 	l := &gcpLogger{
 		logger:        lg,
 		traceID:       traceID,
-		rsvdKeys:      []string{gcpMessageKey},
+		rawTraceID:    rawTraceID,
+		rsvdKeys:      []string{gcpMessageKey, gcpErrorKindKey, gcpErrorStackKey},
 		reqAttributes: make(map[string]any),
 		attributes:    make(map[string]any),
 	}
@@ -160,15 +402,44 @@ func newGCPLogger(lg logger, traceID string) *gcpLogger {
 // newChild returns a new child gcpLogger
 func (l *gcpLogger) newChild() *gcpLogger {
 	return &gcpLogger{
-		root:          l.root,
-		logger:        l.logger,
-		traceID:       l.traceID,
-		rsvdKeys:      l.rsvdKeys,
-		attributes:    make(map[string]any),
-		reqAttributes: nil, // reqAttributes is only used in the root logger, never the child.
+		root:           l.root,
+		logger:         l.logger,
+		traceID:        l.traceID,
+		rawTraceID:     l.rawTraceID,
+		insertIDPrefix: l.insertIDPrefix,
+		singleLog:      l.singleLog,
+		minSeverity:    l.minSeverity,
+		errorStack:     l.errorStack,
+		spanEvents:     l.spanEvents,
+		metrics:        l.metrics,
+		caller:         l.caller,
+		callerSkip:     l.callerSkip,
+		callerPkg:      l.callerPkg,
+		component:      l.component,
+		compLevels:     l.compLevels,
+		dedupe:         l.dedupe,
+		errSummary:     l.errSummary,
+		maxMsgLen:      l.maxMsgLen,
+		maxAttrSize:    l.maxAttrSize,
+		attrFilter:     l.attrFilter,
+		processors:     l.processors,
+		labels:         l.labels,
+		rsvdKeys:       l.rsvdKeys,
+		attributes:     make(map[string]any),
+		reqAttributes:  nil, // reqAttributes is only used in the root logger, never the child.
 	}
 }
 
+// Trace logs a trace message.
+func (l *gcpLogger) Trace(ctx context.Context, v any) {
+	l.log(ctx, logging.Default, v)
+}
+
+// Tracef logs a trace message with format.
+func (l *gcpLogger) Tracef(ctx context.Context, format string, v ...any) {
+	l.log(ctx, logging.Default, fmt.Sprintf(format, v...))
+}
+
 // Debug logs a debug message.
 func (l *gcpLogger) Debug(ctx context.Context, v any) {
 	l.log(ctx, logging.Debug, v)
@@ -209,6 +480,26 @@ func (l *gcpLogger) Errorf(ctx context.Context, format string, v ...any) {
 	l.log(ctx, logging.Error, fmt.Sprintf(format, v...))
 }
 
+// Critical logs a critical message.
+func (l *gcpLogger) Critical(ctx context.Context, v any) {
+	l.log(ctx, logging.Critical, v)
+}
+
+// Criticalf logs a critical message with format.
+func (l *gcpLogger) Criticalf(ctx context.Context, format string, v ...any) {
+	l.log(ctx, logging.Critical, fmt.Sprintf(format, v...))
+}
+
+// Enabled reports whether logs at the level are written, which is false below the minimum level
+func (l *gcpLogger) Enabled(_ context.Context, level Level) bool {
+	return l.enabled(levelSeverity(level))
+}
+
+// Flush writes the buffered child logs to Cloud Logging
+func (l *gcpLogger) Flush() {
+	_ = l.logger.Flush() // errors are reported to the client's OnError
+}
+
 // AddRequestAttribute adds an attribute (key, value) for the parent request log
 // If the key matches a reserved key, it will be prefixed with "custom_"
 // If the key already exists, its value is overwritten
@@ -222,6 +513,25 @@ func (l *gcpLogger) AddRequestAttribute(key string, value any) {
 	l.root.reqAttributes[key] = value
 }
 
+// addStaticAttribute adds an attribute (key, value) for the child (trace) logs and the parent request log
+func (l *gcpLogger) addStaticAttribute(key string, value any) {
+	(&gcpAttributer{logger: l, attributes: l.attributes}).AddAttribute(key, value)
+	l.AddRequestAttribute(key, value)
+}
+
+// setDisconnect sets the watcher of the client of the request
+func (l *gcpLogger) setDisconnect(w *disconnectWatcher) {
+	l.disconnect = w
+}
+
+// requestAttribute returns the value of the attribute of the parent request log
+func (l *gcpLogger) requestAttribute(key string) any {
+	l.root.mu.Lock()
+	defer l.root.mu.Unlock()
+
+	return l.root.reqAttributes[key]
+}
+
 // WithAttributes returns an attributer that can be used to add child (trace) log attributes
 func (l *gcpLogger) WithAttributes() attributer {
 	attrs := make(map[string]any)
@@ -232,41 +542,205 @@ func (l *gcpLogger) WithAttributes() attributer {
 	return &gcpAttributer{logger: l, attributes: attrs}
 }
 
+// withCaller returns a child ctxLogger that skips skip more frames, and the first frames with the function
+// name prefix pkg, to find the caller
+func (l *gcpLogger) withCaller(skip int, pkg string) ctxLogger {
+	child := l.newChild()
+	for k, v := range l.attributes {
+		child.attributes[k] = v
+	}
+	child.callerSkip += skip
+	if pkg != "" {
+		child.callerPkg = pkg
+	}
+
+	return child
+}
+
+// Named returns a child ctxLogger for the component, with the minimum level of the component
+func (l *gcpLogger) Named(name string) ctxLogger {
+	child := l.newChild()
+	for k, v := range l.attributes {
+		child.attributes[k] = v
+	}
+	child.component = componentName(l.component, name)
+	child.attributes[componentKey] = child.component
+	child.minSeverity = l.root.minSeverity
+	if level, ok := componentLevel(l.compLevels, child.component); ok {
+		child.minSeverity = levelSeverity(level)
+	}
+
+	return child
+}
+
+// enabled reports whether child logs of the severity are written, which is false below the
+// level of the component set with SetLevel, or the minimum level of the logger
+func (l *gcpLogger) enabled(severity logging.Severity) bool {
+	if level, ok := registeredLevel(l.component); ok {
+		return severity >= levelSeverity(level)
+	}
+
+	return severity >= l.minSeverity
+}
+
 // TraceID returns the trace ID of the request logs
 func (l *gcpLogger) TraceID() string {
 	return l.traceID
 }
 
+// RawTraceID returns the trace ID of the request logs without the trace resource name prefix
+func (l *gcpLogger) RawTraceID() string {
+	return l.rawTraceID
+}
+
 func (l *gcpLogger) log(ctx context.Context, severity logging.Severity, msg any) {
-	l.root.mu.Lock()
-	if l.root.maxSeverity < severity {
-		l.root.maxSeverity = severity
+	severity = l.root.disconnect.downgradeSeverity(severity)
+	if !l.enabled(severity) {
+		return
 	}
-	l.root.logCount++
-	l.root.mu.Unlock()
 
-	if err, ok := msg.(error); ok {
-		msg = err.Error()
+	ok, suppressed := l.dedupe.allow(fmt.Sprint(severity, msg, l.attributes), time.Now())
+	if !ok {
+		return
+	}
+
+	attributes := l.attrFilter.apply(l.attributes)
+	if len(l.processors) > 0 {
+		text := fmt.Sprint(msg)
+		entry, ok := l.processors.process(Entry{Level: severityLevel(severity), Message: text, Attributes: attributes, Component: l.component, TraceID: l.rawTraceID})
+		if !ok {
+			return
+		}
+		severity, attributes = levelSeverity(entry.Level), entry.Attributes
+		if entry.Message != text {
+			msg = entry.Message
+		}
+	}
+
+	seq, ok := l.root.count(severity)
+	if !ok {
+		return
 	}
 
 	span := trace.SpanFromContext(ctx)
 	attrs := make(map[string]any)
-	for k, v := range l.attributes {
-		attrs[k] = v
+	for k, v := range attributes {
+		attrs[k] = truncateValue(resolveValue(v), l.maxAttrSize)
+	}
+
+	if err, ok := msg.(error); ok {
+		msg = err.Error()
+
+		var chain errors.Chain
+		if errors.As(err, &chain) && len(chain) > 0 {
+			msg = chainMessage(chain)
+			attrs[gcpErrorKindKey] = fmt.Sprintf("%T", chain[0].Err)
+			attrs[gcpErrorStackKey] = chainStack(chain)
+		}
+	}
+	attrs[gcpMessageKey] = truncateValue(msg, l.maxMsgLen)
+	if l.metrics != nil {
+		l.metrics.RecordLog(severityLevel(severity))
+	}
+	if severity >= logging.Error {
+		l.errSummary.add(truncate(fmt.Sprint(msg), l.maxMsgLen))
+	}
+	if l.spanEvents {
+		addSpanEvent(ctx, severityLevel(severity), fmt.Sprint(msg), attributes)
+	}
+	if l.singleLog {
+		attrs[gcpLogTypeKey] = "child"
+	}
+	if l.errorStack && severity >= logging.Error {
+		attrs[stackTraceKey] = callerStack()
+	}
+	if suppressed > 0 {
+		attrs[suppressedCountKey] = suppressed
+	}
+
+	var source *loggingpb.LogEntrySourceLocation
+	if l.caller {
+		if frame, ok := caller(l.callerSkip, l.callerPkg); ok {
+			source = &loggingpb.LogEntrySourceLocation{File: frame.File, Line: int64(frame.Line), Function: frame.Function}
+		}
 	}
-	attrs[gcpMessageKey] = msg
 
 	l.logger.Log(
 		logging.Entry{
-			Payload:      attrs,
-			Severity:     severity,
-			Trace:        l.traceID,
-			SpanID:       span.SpanContext().SpanID().String(),
-			TraceSampled: span.SpanContext().IsSampled(),
+			InsertID:       l.insertID(seq),
+			Payload:        attrs,
+			Severity:       severity,
+			Trace:          l.traceID,
+			SpanID:         span.SpanContext().SpanID().String(),
+			TraceSampled:   span.SpanContext().IsSampled(),
+			SourceLocation: source,
+			Labels:         l.labels,
 		},
 	)
 }
 
+// count records a child log of the severity in the root logger, and returns its sequence number in the request,
+// or false when the request reached the MaxLogs limit and the log is dropped
+func (l *gcpLogger) count(severity logging.Severity) (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.maxSeverity < severity {
+		l.maxSeverity = severity
+	}
+	if l.maxLogs > 0 && l.logCount >= l.maxLogs {
+		l.dropped++
+
+		return 0, false
+	}
+	l.logCount++
+	l.levelCounts.add(severityLevel(severity))
+
+	return l.logCount, true
+}
+
+// parentAttributes returns the number of child logs, their maximum severity, and the attributes of the parent
+// request log
+func (l *gcpLogger) parentAttributes() (int, logging.Severity, map[string]any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	attributes := make(map[string]any)
+	for k, v := range l.attrFilter.apply(l.reqAttributes) {
+		attributes[k] = truncateValue(resolveValue(v), l.maxAttrSize)
+	}
+	if n := l.dedupe.suppressed(); n > 0 {
+		attributes[suppressedCountKey] = n
+	}
+	if errs, n := l.errSummary.summary(); n > 0 {
+		attributes[errorsKey], attributes[errorsCountKey] = errs, n
+	}
+	for _, a := range l.levelCounts.attrs() {
+		attributes[a.Key] = a.Value.Any()
+	}
+
+	return l.logCount, l.maxSeverity, attributes
+}
+
+// stopLimit removes the limit of child logs at the end of the request, and returns the number of logs dropped
+func (l *gcpLogger) stopLimit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxLogs = 0
+
+	return l.dropped
+}
+
+// insertID returns the InsertID for the entry with sequence number seq, or an empty
+// string if InsertID generation is disabled. The parent request log uses sequence 0.
+func (l *gcpLogger) insertID(seq int) string {
+	if l.insertIDPrefix == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s-%d", l.insertIDPrefix, seq)
+}
+
 var _ attributer = (*gcpAttributer)(nil)
 
 type gcpAttributer struct {
@@ -12,8 +12,10 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"cloud.google.com/go/logging"
+	goerrors "github.com/go-playground/errors/v5"
 	"github.com/go-test/deep"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -41,12 +43,16 @@ func TestNewGoogleCloudExporter(t *testing.T) {
 				projectID: "My Project ID",
 				opts:      []logging.LoggerOption{logging.ConcurrentWriteLimit(5)},
 			},
-			want: &GoogleCloudExporter{
-				projectID: "My Project ID",
-				client:    &logging.Client{},
-				opts:      []logging.LoggerOption{logging.ConcurrentWriteLimit(5)},
-				logAll:    true,
-			},
+			want: func() *GoogleCloudExporter {
+				e := &GoogleCloudExporter{
+					projectID: "My Project ID",
+					client:    &logging.Client{},
+					opts:      []logging.LoggerOption{logging.ConcurrentWriteLimit(5)},
+				}
+				e.exporterOptions = exporterOptions[*GoogleCloudExporter]{self: e, logAll: true, minLevelVar: newLevelVar(slog.LevelDebug)}
+
+				return e
+			}(),
 		},
 	}
 	for _, tt := range tests {
@@ -54,7 +60,8 @@ func TestNewGoogleCloudExporter(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			got := NewGoogleCloudExporter(tt.args.client, tt.args.projectID, tt.args.opts...)
-			if diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(GoogleCloudExporter{}, logging.Client{}), cmpopts.IgnoreFields(logging.Client{}, "client", "loggers", "mu")); diff != "" {
+			levelVar := cmp.Comparer(func(a, b *slog.LevelVar) bool { return a.Level() == b.Level() })
+			if diff := cmp.Diff(got, tt.want, levelVar, cmp.AllowUnexported(GoogleCloudExporter{}, exporterOptions[*GoogleCloudExporter]{}, logging.Client{}), cmpopts.IgnoreFields(logging.Client{}, "client", "loggers", "mu")); diff != "" {
 				t.Errorf("NewGoogleCloudExporter() mismatch (-want +got):\n%s", diff)
 			}
 		})
@@ -81,7 +88,7 @@ func TestGoogleCloudExporter_LogAll(t *testing.T) {
 				v: true,
 			},
 			want: &GoogleCloudExporter{
-				logAll: true,
+				exporterOptions: exporterOptions[*GoogleCloudExporter]{logAll: true},
 			},
 		},
 		{
@@ -93,7 +100,7 @@ func TestGoogleCloudExporter_LogAll(t *testing.T) {
 				v: false,
 			},
 			want: &GoogleCloudExporter{
-				logAll: false,
+				exporterOptions: exporterOptions[*GoogleCloudExporter]{logAll: false},
 			},
 		},
 	}
@@ -102,24 +109,156 @@ func TestGoogleCloudExporter_LogAll(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			e := &GoogleCloudExporter{
-				logAll: tt.fields.logAll,
+				exporterOptions: exporterOptions[*GoogleCloudExporter]{logAll: tt.fields.logAll},
 			}
 			got := e.LogAll(tt.args.v)
-			if diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(GoogleCloudExporter{})); diff != "" {
+			if diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(GoogleCloudExporter{}, exporterOptions[*GoogleCloudExporter]{})); diff != "" {
 				t.Errorf("GoogleCloudExporter.LogAll() mismatch (-want +got):\n%s", diff)
 			}
 		})
 	}
 }
 
+func TestGoogleCloudExporter_GenerateInsertID(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		insertID bool
+	}
+	type args struct {
+		v bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		want   *GoogleCloudExporter
+	}{
+		{
+			name: "insertID=true",
+			args: args{
+				v: true,
+			},
+			want: &GoogleCloudExporter{
+				insertID: true,
+			},
+		},
+		{
+			name: "insertID=false",
+			fields: fields{
+				insertID: true,
+			},
+			args: args{
+				v: false,
+			},
+			want: &GoogleCloudExporter{
+				insertID: false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := &GoogleCloudExporter{
+				insertID: tt.fields.insertID,
+			}
+			got := e.GenerateInsertID(tt.args.v)
+			if diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(GoogleCloudExporter{}, exporterOptions[*GoogleCloudExporter]{})); diff != "" {
+				t.Errorf("GoogleCloudExporter.GenerateInsertID() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGoogleCloudExporter_TracePrefix(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		prefix string
+		want   *GoogleCloudExporter
+	}{
+		{
+			name:   "set prefix",
+			prefix: "projects/my-proxy-project/traces/",
+			want: &GoogleCloudExporter{
+				tracePrefix: "projects/my-proxy-project/traces/",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := &GoogleCloudExporter{}
+			got := e.TracePrefix(tt.prefix)
+			if diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(GoogleCloudExporter{}, exporterOptions[*GoogleCloudExporter]{})); diff != "" {
+				t.Errorf("GoogleCloudExporter.TracePrefix() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGoogleCloudExporter_SingleLog(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		singleLog bool
+	}
+	type args struct {
+		v bool
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		want   *GoogleCloudExporter
+	}{
+		{
+			name: "singleLog=true",
+			args: args{
+				v: true,
+			},
+			want: &GoogleCloudExporter{
+				singleLog: true,
+			},
+		},
+		{
+			name: "singleLog=false",
+			fields: fields{
+				singleLog: true,
+			},
+			args: args{
+				v: false,
+			},
+			want: &GoogleCloudExporter{
+				singleLog: false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := &GoogleCloudExporter{
+				singleLog: tt.fields.singleLog,
+			}
+			got := e.SingleLog(tt.args.v)
+			if diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(GoogleCloudExporter{}, exporterOptions[*GoogleCloudExporter]{})); diff != "" {
+				t.Errorf("GoogleCloudExporter.SingleLog() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestGoogleCloudExporter_Middleware(t *testing.T) {
 	disableMetaServertest(t)
 
 	type fields struct {
-		projectID string
-		client    *logging.Client
-		opts      []logging.LoggerOption
-		logAll    bool
+		projectID    string
+		client       *logging.Client
+		opts         []logging.LoggerOption
+		parentClient *logging.Client
+		parentOpts   []logging.LoggerOption
+		logAll       bool
 	}
 	tests := []struct {
 		name   string
@@ -139,11 +278,34 @@ func TestGoogleCloudExporter_Middleware(t *testing.T) {
 				opts := []logging.LoggerOption{logging.ConcurrentWriteLimit(5)}
 
 				return &gcpHandler{
-					next:         next,
-					parentLogger: client.Logger("request_parent_log", opts...),
-					childLogger:  client.Logger("request_child_log", opts...),
-					projectID:    "My other project",
-					logAll:       true,
+					handlerOptions: handlerOptions{logAll: true},
+					next:           next,
+					parentLogger:   client.Logger("request_parent_log", opts...),
+					childLogger:    client.Logger("request_child_log", opts...),
+					projectID:      "My other project",
+				}
+			},
+		},
+		{
+			name: "call Middleware with parent client",
+			fields: fields{
+				projectID:    "My service project",
+				client:       &logging.Client{},
+				opts:         []logging.LoggerOption{logging.ConcurrentWriteLimit(5)},
+				parentClient: &logging.Client{},
+				parentOpts:   []logging.LoggerOption{logging.ConcurrentWriteLimit(2)},
+				logAll:       true,
+			},
+			want: func(next http.Handler) http.Handler {
+				client := &logging.Client{}
+				parentClient := &logging.Client{}
+
+				return &gcpHandler{
+					handlerOptions: handlerOptions{logAll: true},
+					next:           next,
+					parentLogger:   parentClient.Logger("request_parent_log", logging.ConcurrentWriteLimit(2)),
+					childLogger:    client.Logger("request_child_log", logging.ConcurrentWriteLimit(5)),
+					projectID:      "My service project",
 				}
 			},
 		},
@@ -152,12 +314,9 @@ func TestGoogleCloudExporter_Middleware(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
-			e := &GoogleCloudExporter{
-				projectID: tt.fields.projectID,
-				client:    tt.fields.client,
-				opts:      tt.fields.opts,
-				logAll:    tt.fields.logAll,
-			}
+			e := NewGoogleCloudExporter(tt.fields.client, tt.fields.projectID, tt.fields.opts...)
+			e.logAll = tt.fields.logAll
+			e.ParentClient(tt.fields.parentClient, tt.fields.parentOpts...)
 			got := e.Middleware()(next)
 			if diff := deep.Equal(got, tt.want(next)); diff != nil {
 				t.Errorf("GoogleCloudExporter.Middleware() = %v", diff)
@@ -177,6 +336,7 @@ func Test_gcpHandler_ServeHTTP(t *testing.T) {
 	type fields struct {
 		projectID string
 		logAll    bool
+		singleLog bool
 	}
 	tests := []struct {
 		name      string
@@ -229,6 +389,20 @@ func Test_gcpHandler_ServeHTTP(t *testing.T) {
 			},
 			wantLevel: logging.Warning,
 		},
+		{
+			name: "singleLog=true",
+			fields: fields{
+				projectID: "my-big-project",
+				logAll:    true,
+				singleLog: true,
+			},
+			args: args{
+				status: http.StatusOK,
+				logs:   1,
+				level:  logging.Info,
+			},
+			wantLevel: logging.Info,
+		},
 		{
 			name: "logging for error status",
 			fields: fields{
@@ -249,11 +423,13 @@ func Test_gcpHandler_ServeHTTP(t *testing.T) {
 			var handlerCalled bool
 			var traceID string
 			l := &captureLogger{}
+			cl := &captureLogger{}
 			handler := &gcpHandler{
-				parentLogger: l,
-				childLogger:  &captureLogger{},
-				projectID:    tt.fields.projectID,
-				logAll:       tt.fields.logAll,
+				handlerOptions: handlerOptions{logAll: tt.fields.logAll, propagator: gcpPropagator, sampleRate: 1},
+				parentLogger:   l,
+				childLogger:    cl,
+				projectID:      tt.fields.projectID,
+				singleLog:      tt.fields.singleLog,
 				next: http.HandlerFunc(
 					func(w http.ResponseWriter, r *http.Request) {
 						for i := 0; i < tt.args.logs; i++ {
@@ -304,6 +480,9 @@ func Test_gcpHandler_ServeHTTP(t *testing.T) {
 				"test_key_1": "test_value_1",
 				"test_key_2": "test_value_2",
 			}
+			if tt.fields.singleLog {
+				wantPayload["log_type"] = "parent"
+			}
 			if pl, ok := l.e.Payload.(map[string]any); ok {
 				if diff := cmp.Diff(pl, wantPayload); diff != "" {
 					t.Errorf("Payload mismatch (-want +got):\n%s", diff)
@@ -313,6 +492,127 @@ func Test_gcpHandler_ServeHTTP(t *testing.T) {
 			if l.e.HTTPRequest.Status != tt.args.status {
 				t.Errorf("Status = %v, want %v", l.e.HTTPRequest.Status, tt.args.status)
 			}
+
+			if tt.fields.singleLog && tt.args.logs > 0 {
+				if pl, ok := cl.e.Payload.(map[string]any); !ok || pl["log_type"] != "child" {
+					t.Errorf("child Payload = %v, want log_type=child", cl.e.Payload)
+				}
+			}
+		})
+	}
+}
+
+func Test_gcpServerlessLabels(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want map[string]string
+	}{
+		{
+			name: "not serverless",
+		},
+		{
+			name: "Cloud Run",
+			env:  map[string]string{"K_SERVICE": "api", "K_REVISION": "api-00042-abc", "K_CONFIGURATION": "api"},
+			want: map[string]string{"service_name": "api", "revision_name": "api-00042-abc", "configuration_name": "api"},
+		},
+		{
+			name: "Cloud Functions",
+			env:  map[string]string{"K_SERVICE": "handler", "K_REVISION": "3", "FUNCTION_TARGET": "Handle"},
+			want: map[string]string{"service_name": "handler", "revision_name": "3", "function_target": "Handle"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := gcpServerlessLabels(func(key string) string { return tt.env[key] })
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("gcpServerlessLabels() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_gcpCacheHit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		xCache string
+		want   bool
+	}{
+		{
+			name:   "hit",
+			xCache: "HIT from proxy",
+			want:   true,
+		},
+		{
+			name:   "miss",
+			xCache: "MISS",
+		},
+		{
+			name: "no header",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := http.Header{}
+			if tt.xCache != "" {
+				h.Set("X-Cache", tt.xCache)
+			}
+			if got := gcpCacheHit(h); got != tt.want {
+				t.Errorf("gcpCacheHit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_gcpHandler_traceName(t *testing.T) {
+	t.Parallel()
+	type fields struct {
+		projectID   string
+		tracePrefix string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		traceID string
+		want    string
+	}{
+		{
+			name: "default prefix",
+			fields: fields{
+				projectID: "my-project",
+			},
+			traceID: "105445aa7843bc8bf206b12000100000",
+			want:    "projects/my-project/traces/105445aa7843bc8bf206b12000100000",
+		},
+		{
+			name: "custom prefix",
+			fields: fields{
+				projectID:   "my-project",
+				tracePrefix: "projects/my-proxy-project/traces/",
+			},
+			traceID: "105445aa7843bc8bf206b12000100000",
+			want:    "projects/my-proxy-project/traces/105445aa7843bc8bf206b12000100000",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			g := &gcpHandler{
+				projectID:   tt.fields.projectID,
+				tracePrefix: tt.fields.tracePrefix,
+			}
+			if got := g.traceName(tt.traceID); got != tt.want {
+				t.Errorf("gcpHandler.traceName() = %v, want %v", got, tt.want)
+			}
 		})
 	}
 }
@@ -320,15 +620,13 @@ func Test_gcpHandler_ServeHTTP(t *testing.T) {
 func Test_gcpTraceIDFromRequest(t *testing.T) {
 	t.Parallel()
 	type args struct {
-		mockReq   func(traceStr string) (*http.Request, string)
-		projectID string
-		traceStr  string
+		mockReq  func(traceStr string) (*http.Request, string)
+		traceStr string
 	}
 	tests := []struct {
-		name            string
-		args            args
-		wantTracePrefix string
-		wantTraceStr    string
+		name         string
+		args         args
+		wantTraceStr string
 	}{
 		// The order these are significant
 		{
@@ -338,11 +636,9 @@ func Test_gcpTraceIDFromRequest(t *testing.T) {
 				mockReq: func(wantTraceStr string) (*http.Request, string) {
 					return &http.Request{URL: &url.URL{}}, wantTraceStr
 				},
-				projectID: "my-project",
-				traceStr:  "105445aa7843bc8bf206b12000100000",
+				traceStr: "105445aa7843bc8bf206b12000100000",
 			},
-			wantTracePrefix: "projects/my-project/traces/",
-			wantTraceStr:    "105445aa7843bc8bf206b12000100000",
+			wantTraceStr: "105445aa7843bc8bf206b12000100000",
 		},
 		{
 			// This test sets the global tracing provider (I don't think this can be un-done)
@@ -357,9 +653,7 @@ func Test_gcpTraceIDFromRequest(t *testing.T) {
 
 					return r, span.SpanContext().TraceID().String()
 				},
-				projectID: "my-project",
 			},
-			wantTracePrefix: "projects/my-project/traces/",
 		},
 		{
 			// With the global tracing provider set, this test shows that
@@ -372,21 +666,32 @@ func Test_gcpTraceIDFromRequest(t *testing.T) {
 
 					return r, wantTraceStr
 				},
-				projectID: "my-project",
 			},
-			wantTracePrefix: "projects/my-project/traces/",
-			wantTraceStr:    "105445aa7843bc8bf206b12000100000",
+			wantTraceStr: "105445aa7843bc8bf206b12000100000",
+		},
+		{
+			// W3C traceparent takes priority over the legacy X-Cloud-Trace-Context header
+			name: "with traceparent in headers",
+			args: args{
+				mockReq: func(wantTraceStr string) (r *http.Request, traceStr string) {
+					r = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+					r.Header.Add("traceparent", "00-"+wantTraceStr+"-00f067aa0ba902b7-01")
+					r.Header.Add("X-Cloud-Trace-Context", "105445aa7843bc8bf206b12000100000/1;o=1")
+
+					return r, wantTraceStr
+				},
+			},
+			wantTraceStr: "4bf92f3577b34da6a3ce929d0e0e4736",
 		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			r, traceStr := tt.args.mockReq(tt.wantTraceStr)
-			want := tt.wantTracePrefix + traceStr
+			r, want := tt.args.mockReq(tt.wantTraceStr)
 
-			if got := gcpTraceIDFromRequest(r, tt.args.projectID, func() string { return tt.args.traceStr }); got != want {
-				t.Errorf("gcpTraceIDFromRequest() = %v, want %v", got, want)
+			if got := traceIDFromRequest(r, gcpPropagator, func() string { return tt.args.traceStr }); got != want {
+				t.Errorf("traceIDFromRequest() = %v, want %v", got, want)
 			}
 		})
 	}
@@ -396,8 +701,9 @@ func Test_newGCPLogger(t *testing.T) {
 	t.Parallel()
 
 	type args struct {
-		lg      *logging.Logger
-		traceID string
+		lg         *logging.Logger
+		traceID    string
+		rawTraceID string
 	}
 	tests := []struct {
 		name string
@@ -407,13 +713,15 @@ func Test_newGCPLogger(t *testing.T) {
 		{
 			name: "new",
 			args: args{
-				lg:      &logging.Logger{},
-				traceID: "hello",
+				lg:         &logging.Logger{},
+				traceID:    "projects/hello/traces/world",
+				rawTraceID: "world",
 			},
 			want: &gcpLogger{
 				logger:        &logging.Logger{},
-				traceID:       "hello",
-				rsvdKeys:      []string{"message"},
+				traceID:       "projects/hello/traces/world",
+				rawTraceID:    "world",
+				rsvdKeys:      []string{"message", "error.kind", "error.stack"},
 				reqAttributes: map[string]any{},
 				attributes:    map[string]any{},
 			},
@@ -423,7 +731,7 @@ func Test_newGCPLogger(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			got := newGCPLogger(tt.args.lg, tt.args.traceID)
+			got := newGCPLogger(tt.args.lg, tt.args.traceID, tt.args.rawTraceID)
 			if diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(gcpLogger{}), cmpopts.IgnoreFields(gcpLogger{}, "logger", "mu", "root")); diff != "" {
 				t.Errorf("newGCPLogger() mismatch (-want +got):\n%s", diff)
 			}
@@ -440,6 +748,7 @@ func Test_gcpLogger(t *testing.T) {
 	type fields struct {
 		attributes map[string]any
 		traceID    string
+		rawTraceID string
 	}
 	type args struct {
 		format string
@@ -463,7 +772,8 @@ func Test_gcpLogger(t *testing.T) {
 			name: "Strings",
 			fields: fields{
 				attributes: map[string]any{"a test key": "a test value"},
-				traceID:    "123987",
+				traceID:    "projects/my-project/traces/123987",
+				rawTraceID: "123987",
 			},
 			args: args{
 				format: "Formatted %s",
@@ -483,7 +793,8 @@ func Test_gcpLogger(t *testing.T) {
 			name: "String & Error",
 			fields: fields{
 				attributes: map[string]any{"test_key_1": "test_value_1", "test_key_2": "test_value_2"},
-				traceID:    "987123",
+				traceID:    "projects/my-project/traces/987123",
+				rawTraceID: "987123",
 			},
 			args: args{
 				format: "Formatted %s",
@@ -514,6 +825,7 @@ func Test_gcpLogger(t *testing.T) {
 				},
 				attributes: tt.fields.attributes,
 				traceID:    tt.fields.traceID,
+				rawTraceID: tt.fields.rawTraceID,
 			}
 			l.root = l
 
@@ -570,6 +882,119 @@ func Test_gcpLogger(t *testing.T) {
 			if l.TraceID() != tt.fields.traceID {
 				t.Errorf("TraceID() = %v, want %v", l.TraceID(), tt.fields.traceID)
 			}
+			if l.RawTraceID() != tt.fields.rawTraceID {
+				t.Errorf("RawTraceID() = %v, want %v", l.RawTraceID(), tt.fields.rawTraceID)
+			}
+		})
+	}
+}
+
+func Test_gcpLogger_errorChain(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		err       error
+		wantMsg   string
+		wantKind  string
+		wantStack int
+	}{
+		{
+			name:    "standard error",
+			err:     errors.New("some error"),
+			wantMsg: "some error",
+		},
+		{
+			name:      "error chain",
+			err:       goerrors.Wrap(goerrors.Wrap(errors.New("some error"), "inner"), "outer"),
+			wantMsg:   "outer: inner: some error",
+			wantKind:  "*errors.errorString",
+			wantStack: 3,
+		},
+		{
+			name:      "wrapped error chain",
+			err:       fmt.Errorf("wrapped: %w", goerrors.New("some error")),
+			wantMsg:   "some error",
+			wantKind:  "*errors.errorString",
+			wantStack: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := &captureLogger{}
+			l := newGCPLogger(c, "projects/my-project/traces/123", "123")
+			l.Error(context.Background(), tt.err)
+
+			pl, ok := c.e.Payload.(map[string]any)
+			if !ok {
+				t.Fatalf("Payload type %T, want %T", c.e.Payload, map[string]any{})
+			}
+			if pl["message"] != tt.wantMsg {
+				t.Errorf("message = %v, want %v", pl["message"], tt.wantMsg)
+			}
+			if tt.wantStack == 0 {
+				if _, ok := pl["error.stack"]; ok {
+					t.Errorf("error.stack = %v, want none", pl["error.stack"])
+				}
+
+				return
+			}
+			if pl["error.kind"] != tt.wantKind {
+				t.Errorf("error.kind = %v, want %v", pl["error.kind"], tt.wantKind)
+			}
+			stack, ok := pl["error.stack"].([]map[string]any)
+			if !ok || len(stack) != tt.wantStack {
+				t.Fatalf("error.stack = %v, want %d frames", pl["error.stack"], tt.wantStack)
+			}
+			if file := stack[0]["file"]; file != "gcp_test.go" {
+				t.Errorf("error.stack[0].file = %v, want gcp_test.go", file)
+			}
+		})
+	}
+}
+
+func Test_gcpLogger_insertID(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		seq int
+	}
+	tests := []struct {
+		name           string
+		insertIDPrefix string
+		args           args
+		want           string
+	}{
+		{
+			name: "disabled",
+			args: args{
+				seq: 3,
+			},
+			want: "",
+		},
+		{
+			name:           "parent entry",
+			insertIDPrefix: "0af7651916cd43dd8448eb211c80319c",
+			want:           "0af7651916cd43dd8448eb211c80319c-0",
+		},
+		{
+			name:           "child entry",
+			insertIDPrefix: "0af7651916cd43dd8448eb211c80319c",
+			args: args{
+				seq: 3,
+			},
+			want: "0af7651916cd43dd8448eb211c80319c-3",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			l := &gcpLogger{insertIDPrefix: tt.insertIDPrefix}
+			if got := l.insertID(tt.args.seq); got != tt.want {
+				t.Errorf("gcpLogger.insertID() = %v, want %v", got, tt.want)
+			}
 		})
 	}
 }
@@ -866,6 +1291,10 @@ func (t *testLogger) Log(e logging.Entry) {
 	_, _ = t.buf.WriteString(logStr)
 }
 
+func (t *testLogger) Flush() error {
+	return nil
+}
+
 type captureLogger struct {
 	e logging.Entry
 }
@@ -873,3 +1302,310 @@ type captureLogger struct {
 func (c *captureLogger) Log(e logging.Entry) {
 	c.e = e
 }
+
+func (c *captureLogger) Flush() error {
+	return nil
+}
+
+func Test_gcpLogger_slogAttributes(t *testing.T) {
+	t.Parallel()
+
+	c := &captureLogger{}
+	l := newGCPLogger(c, "projects/my-project/traces/123", "123")
+	a := l.WithAttributes()
+	a.AddAttribute("user", slog.GroupValue(slog.String("id", "42")))
+	a.Logger().Info(context.Background(), "message")
+
+	pl, ok := c.e.Payload.(map[string]any)
+	if !ok {
+		t.Fatalf("Payload type %T, want %T", c.e.Payload, map[string]any{})
+	}
+	if diff := cmp.Diff(map[string]any{"id": "42"}, pl["user"]); diff != "" {
+		t.Errorf("user mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_gcpLogger_errorStack(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		errorStack bool
+		log        func(l *gcpLogger)
+		wantStack  bool
+	}{
+		{
+			name:       "error with stack",
+			errorStack: true,
+			log:        func(l *gcpLogger) { l.Error(context.Background(), "message") },
+			wantStack:  true,
+		},
+		{
+			name:       "warning with stack",
+			errorStack: true,
+			log:        func(l *gcpLogger) { l.Warn(context.Background(), "message") },
+		},
+		{
+			name: "error without stack",
+			log:  func(l *gcpLogger) { l.Error(context.Background(), "message") },
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := &captureLogger{}
+			l := newGCPLogger(c, "projects/my-project/traces/123", "123")
+			l.errorStack = tt.errorStack
+			tt.log(l)
+
+			pl, ok := c.e.Payload.(map[string]any)
+			if !ok {
+				t.Fatalf("Payload type %T, want %T", c.e.Payload, map[string]any{})
+			}
+			stack, ok := pl["stack_trace"].(string)
+			if ok != tt.wantStack {
+				t.Fatalf("stack_trace = %v, want stack %v", pl["stack_trace"], tt.wantStack)
+			}
+			if tt.wantStack && !strings.Contains(stack, "Test_gcpLogger_errorStack") {
+				t.Errorf("stack_trace = %q, want the test function", stack)
+			}
+		})
+	}
+}
+
+func Test_gcpLogger_caller(t *testing.T) {
+	t.Parallel()
+
+	c := &captureLogger{}
+	l := newGCPLogger(c, "projects/my-project/traces/123", "123")
+	l.Info(context.Background(), "message")
+	if c.e.SourceLocation != nil {
+		t.Errorf("SourceLocation = %v, want nil", c.e.SourceLocation)
+	}
+
+	l.caller = true
+	l.Info(context.Background(), "message")
+	if c.e.SourceLocation == nil {
+		t.Fatal("SourceLocation = nil, want caller")
+	}
+	if got := c.e.SourceLocation.GetFunction(); !strings.HasSuffix(got, ".Test_gcpLogger_caller") {
+		t.Errorf("SourceLocation.Function = %q, want Test_gcpLogger_caller", got)
+	}
+	if got := c.e.SourceLocation.GetFile(); !strings.HasSuffix(got, "gcp_test.go") {
+		t.Errorf("SourceLocation.File = %q, want gcp_test.go", got)
+	}
+}
+
+func Test_gcpLogger_Enabled(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		minSeverity logging.Severity
+		level       Level
+		want        bool
+	}{
+		{name: "trace below debug", minSeverity: logging.Debug, level: LevelTrace, want: false},
+		{name: "debug at debug", minSeverity: logging.Debug, level: LevelDebug, want: true},
+		{name: "trace at default", minSeverity: logging.Default, level: LevelTrace, want: true},
+		{name: "info below warning", minSeverity: logging.Warning, level: LevelInfo, want: false},
+		{name: "critical above warning", minSeverity: logging.Warning, level: LevelCritical, want: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := &gcpLogger{minSeverity: tt.minSeverity}
+			if got := l.Enabled(context.Background(), tt.level); got != tt.want {
+				t.Errorf("gcpLogger.Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_gcpLogger_Named(t *testing.T) {
+	t.Parallel()
+
+	c := &captureLogger{}
+	l := newGCPLogger(c, "projects/my-project/traces/123", "123")
+	l.minSeverity = logging.Info
+	l.compLevels = map[string]slog.Level{"db": slog.LevelDebug}
+
+	db := l.Named("db")
+	db.Debug(context.Background(), "debug")
+	pl, _ := c.e.Payload.(map[string]any)
+	if pl["message"] != "debug" || pl["component"] != "db" {
+		t.Errorf("Named(db).Debug() payload = %v, want debug message of component db", pl)
+	}
+
+	c.e = logging.Entry{}
+	api := l.Named("api")
+	api.Debug(context.Background(), "dropped")
+	if c.e.Payload != nil {
+		t.Errorf("Named(api).Debug() payload = %v, want dropped", c.e.Payload)
+	}
+
+	db.Named("pool").Info(context.Background(), "info")
+	pl, _ = c.e.Payload.(map[string]any)
+	if pl["component"] != "db.pool" {
+		t.Errorf("Named(db).Named(pool) component = %v, want db.pool", pl["component"])
+	}
+}
+
+func Test_gcpLogger_Dedupe(t *testing.T) {
+	t.Parallel()
+
+	c := &captureLogger{}
+	l := newGCPLogger(c, "projects/my-project/traces/123", "123")
+	l.dedupe = newDeduper(time.Minute)
+
+	l.Info(context.Background(), "retrying")
+	c.e = logging.Entry{}
+	l.Info(context.Background(), "retrying")
+	l.Info(context.Background(), "retrying")
+	if c.e.Payload != nil {
+		t.Fatalf("duplicate log written, Payload = %v", c.e.Payload)
+	}
+
+	l.Warn(context.Background(), "retrying")
+	pl, _ := c.e.Payload.(map[string]any)
+	if _, ok := pl["suppressed_count"]; ok {
+		t.Errorf("Warn() suppressed_count = %v, want none", pl["suppressed_count"])
+	}
+
+	for _, e := range l.dedupe.entries {
+		e.written = e.written.Add(-time.Minute)
+	}
+	l.Info(context.Background(), "retrying")
+	pl, _ = c.e.Payload.(map[string]any)
+	if pl["suppressed_count"] != 2 {
+		t.Errorf("Info() suppressed_count = %v, want 2", pl["suppressed_count"])
+	}
+	if got := l.dedupe.suppressed(); got != 2 {
+		t.Errorf("suppressed() = %d, want 2", got)
+	}
+}
+
+func Test_gcpLogger_maxLogs(t *testing.T) {
+	t.Parallel()
+
+	c := &captureLogger{}
+	l := newGCPLogger(c, "projects/my-project/traces/123", "123")
+	l.maxLogs = 2
+
+	for i := range 5 {
+		l.Named("loop").Infof(context.Background(), "message %d", i)
+	}
+	pl, _ := c.e.Payload.(map[string]any)
+	if pl["message"] != "message 1" {
+		t.Errorf("last message = %v, want message 1", pl["message"])
+	}
+	if l.logCount != 2 {
+		t.Errorf("logCount = %d, want 2", l.logCount)
+	}
+
+	if got := l.stopLimit(); got != 3 {
+		t.Fatalf("stopLimit() = %d, want 3", got)
+	}
+	l.Warnf(context.Background(), droppedLogsMessage, 3)
+	pl, _ = c.e.Payload.(map[string]any)
+	if pl["message"] != "3 additional log entries suppressed" {
+		t.Errorf("message = %v, want 3 additional log entries suppressed", pl["message"])
+	}
+}
+
+func Test_gcpLogger_truncate(t *testing.T) {
+	t.Parallel()
+
+	c := &captureLogger{}
+	l := newGCPLogger(c, "projects/my-project/traces/123", "123")
+	l.maxMsgLen, l.maxAttrSize = 20, 24
+	a := l.WithAttributes()
+	a.AddAttribute("small", "value")
+	a.AddAttribute("large", map[string]string{"key": strings.Repeat("a", 30)})
+	a.Logger().Info(context.Background(), strings.Repeat("m", 30))
+
+	want := map[string]any{
+		"message": "mmmmmm...[truncated]",
+		"small":   "value",
+		"large":   `{"key":"aa...[truncated]`,
+	}
+	if diff := cmp.Diff(want, c.e.Payload); diff != "" {
+		t.Errorf("Payload mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_gcpLogger_processors(t *testing.T) {
+	t.Parallel()
+
+	c := &captureLogger{}
+	l := newGCPLogger(c, "projects/my-project/traces/123", "123")
+	l.processors = processors{
+		func(e Entry) (Entry, bool) {
+			return e, e.Message != "dropped"
+		},
+		func(e Entry) (Entry, bool) {
+			delete(e.Attributes, "password")
+			e.Attributes["trace"] = e.TraceID
+			e.Level = LevelWarn
+			e.Message = strings.ToUpper(e.Message)
+
+			return e, true
+		},
+	}
+	a := l.WithAttributes()
+	a.AddAttribute("password", "secret")
+	a.AddAttribute("user", "bob")
+	lg := a.Logger()
+
+	lg.Info(context.Background(), "message")
+	want := map[string]any{"message": "MESSAGE", "user": "bob", "trace": "123"}
+	if diff := cmp.Diff(want, c.e.Payload); diff != "" {
+		t.Errorf("Payload mismatch (-want +got):\n%s", diff)
+	}
+	if c.e.Severity != logging.Warning {
+		t.Errorf("Severity = %v, want %v", c.e.Severity, logging.Warning)
+	}
+
+	c.e = logging.Entry{}
+	lg.Info(context.Background(), "dropped")
+	if c.e.Payload != nil {
+		t.Errorf("dropped log written, Payload = %v", c.e.Payload)
+	}
+	if l.logCount != 1 {
+		t.Errorf("logCount = %d, want 1", l.logCount)
+	}
+}
+
+func Test_gcpHandler_staticAttributes(t *testing.T) {
+	t.Parallel()
+
+	l := &captureLogger{}
+	cl := &captureLogger{}
+	handler := &gcpHandler{
+		handlerOptions: handlerOptions{
+			logAll:      true,
+			staticAttrs: map[string]any{"service": "api", "version": "1.2.3", "message": "static"},
+			propagator:  gcpPropagator,
+			sampleRate:  1,
+		},
+		parentLogger: l,
+		childLogger:  cl,
+		next: http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			Req(r).AddRequestAttribute("version", "override")
+			Req(r).Info("some log")
+		}),
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	wantParent := map[string]any{"message": "Parent Log Entry", "service": "api", "version": "override", "custom_message": "static"}
+	if diff := cmp.Diff(wantParent, l.e.Payload); diff != "" {
+		t.Errorf("parent Payload mismatch (-want +got):\n%s", diff)
+	}
+	wantChild := map[string]any{"message": "some log", "service": "api", "version": "1.2.3", "custom_message": "static"}
+	if diff := cmp.Diff(wantChild, cl.e.Payload); diff != "" {
+		t.Errorf("child Payload mismatch (-want +got):\n%s", diff)
+	}
+}
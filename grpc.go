@@ -0,0 +1,223 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// keys of the message size attributes of the parent log of an RPC
+const (
+	grpcRequestSizeKey  = "grpc.request_size"
+	grpcResponseSizeKey = "grpc.response_size"
+)
+
+// UnaryServerInterceptor returns a gRPC interceptor that logs unary RPCs with the middleware of the exporter, e.g.
+//
+//	grpc.NewServer(grpc.UnaryInterceptor(logger.UnaryServerInterceptor(exporter)))
+//
+// Each RPC is a request with the POST method and the path of the full method name (e.g. "/pkg.Service/Method"),
+// with the incoming metadata as headers, so the options of the exporter apply to RPCs like to HTTP requests.
+// The parent log has the gRPC status (grpc.status), the status mapped to an HTTP status, and the sizes of
+// the messages. The logger of the RPC is returned by Ctx with the context of the handler. A panic of the handler
+// recovered by the middleware (see RecoverPanics) returns the Internal status.
+func UnaryServerInterceptor(e Exporter) grpc.UnaryServerInterceptor {
+	h := e.Middleware()(http.HandlerFunc(serveGRPC))
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		call := &grpcCall{}
+		call.received.Add(messageSize(req))
+		var resp any
+		call.run = func(ctx context.Context) error {
+			var err error
+			if resp, err = handler(ctx, req); err == nil {
+				call.sent.Add(messageSize(resp))
+			}
+
+			return err
+		}
+		h.ServeHTTP(&statusResponseWriter{header: make(http.Header)}, grpcRequest(context.WithValue(ctx, grpcCallKey, call), info.FullMethod))
+
+		// the status error of the handler is returned as is for the gRPC server
+		return resp, call.err
+	}
+}
+
+// StreamServerInterceptor returns a gRPC interceptor that logs streaming RPCs with the middleware of the exporter,
+// like UnaryServerInterceptor. The sizes of the messages are the totals of the messages of the stream.
+func StreamServerInterceptor(e Exporter) grpc.StreamServerInterceptor {
+	h := e.Middleware()(http.HandlerFunc(serveGRPC))
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		call := &grpcCall{}
+		call.run = func(ctx context.Context) error {
+			return handler(srv, &grpcServerStream{ServerStream: ss, ctx: ctx, call: call})
+		}
+		h.ServeHTTP(&statusResponseWriter{header: make(http.Header)}, grpcRequest(context.WithValue(ss.Context(), grpcCallKey, call), info.FullMethod))
+
+		return call.err
+	}
+}
+
+// grpcCall is an RPC served by the middleware of an exporter
+type grpcCall struct {
+	run      func(ctx context.Context) error
+	err      error
+	received atomic.Int64
+	sent     atomic.Int64
+}
+
+// serveGRPC runs the RPC in the context of the request, and writes its status
+func serveGRPC(w http.ResponseWriter, r *http.Request) {
+	call, ok := r.Context().Value(grpcCallKey).(*grpcCall)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	defer func() {
+		// the panic is raised again for the middleware, which logs it, and recovers it with RecoverPanics
+		if v := recover(); v != nil {
+			call.err = status.Error(codes.Internal, "handler panicked")
+			w.Header().Set("Grpc-Status", strconv.Itoa(int(codes.Internal)))
+			panic(v)
+		}
+	}()
+	call.err = call.run(r.Context())
+	Req(r).AddRequestAttribute(grpcRequestSizeKey, call.received.Load()).AddRequestAttribute(grpcResponseSizeKey, call.sent.Load())
+
+	st := status.Convert(call.err)
+	w.Header().Set("Grpc-Status", strconv.Itoa(int(st.Code())))
+	if st.Code() != codes.OK && st.Message() != "" {
+		w.Header().Set("Grpc-Message", st.Message())
+	}
+	w.WriteHeader(grpcHTTPStatus(st.Code()))
+}
+
+// grpcRequest returns the request of the RPC for the middleware of an exporter
+func grpcRequest(ctx context.Context, method string) *http.Request {
+	r := (&http.Request{
+		Method:     http.MethodPost,
+		URL:        &url.URL{Path: method},
+		RequestURI: method,
+		Proto:      "HTTP/2.0",
+		ProtoMajor: 2,
+		Header:     make(http.Header),
+		Body:       http.NoBody,
+	}).WithContext(ctx)
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	for k, v := range md {
+		if strings.HasPrefix(k, ":") {
+			continue
+		}
+		r.Header[http.CanonicalHeaderKey(k)] = v
+	}
+	if authority := md.Get(":authority"); len(authority) > 0 {
+		r.Host = authority[0]
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		r.RemoteAddr = p.Addr.String()
+	}
+
+	return r
+}
+
+// grpcHTTPStatus maps the gRPC status code to the HTTP status of the parent log
+func grpcHTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499 // Client Closed Request
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// messageSize returns the size of the protobuf message, or 0 when m is not a protobuf message
+func messageSize(m any) int64 {
+	if pm, ok := m.(proto.Message); ok {
+		return int64(proto.Size(pm))
+	}
+
+	return 0
+}
+
+// statusResponseWriter is the http.ResponseWriter of the requests of RPCs, messages, job runs, and Lambda
+// invocations, which only records the headers and status
+type statusResponseWriter struct {
+	header http.Header
+	status int
+}
+
+func (w *statusResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *statusResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *statusResponseWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+// grpcServerStream is the stream of an RPC with the context of the request logger, which counts the sizes of
+// the messages. The errors of the stream are returned as is, since gRPC compares them, e.g. with io.EOF.
+type grpcServerStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	call *grpcCall
+}
+
+func (s *grpcServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *grpcServerStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	s.call.received.Add(messageSize(m))
+
+	return nil
+}
+
+func (s *grpcServerStream) SendMsg(m any) error {
+	if err := s.ServerStream.SendMsg(m); err != nil {
+		return err
+	}
+	s.call.sent.Add(messageSize(m))
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// grpcLogs returns the child and parent logs of a request written by a console exporter in JSON format
+func grpcLogs(t *testing.T, buf *bytes.Buffer) (child, parent map[string]any) {
+	t.Helper()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("output = %q, want 2 lines", buf.String())
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &child); err != nil {
+		t.Fatalf("json.Unmarshal() child error = %v", err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &parent); err != nil {
+		t.Fatalf("json.Unmarshal() parent error = %v", err)
+	}
+
+	return child, parent
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		err        error
+		wantErr    error
+		wantParent map[string]any
+	}{
+		{
+			name: "success",
+			wantParent: map[string]any{
+				"level": "INFO", "msg": "POST /pkg.Users/Get", "method": "POST", "path": "/pkg.Users/Get", "status": float64(200),
+				"requestSize": float64(0), "responseSize": float64(0), "logCount": float64(1),
+				grpcStatusKey: float64(0), grpcRequestSizeKey: float64(7), grpcResponseSizeKey: float64(7),
+			},
+		},
+		{
+			name:    "status error",
+			err:     status.Error(codes.NotFound, "user not found"),
+			wantErr: status.Error(codes.NotFound, "user not found"),
+			wantParent: map[string]any{
+				"level": "WARN", "msg": "POST /pkg.Users/Get", "method": "POST", "path": "/pkg.Users/Get", "status": float64(404),
+				"requestSize": float64(0), "responseSize": float64(0), "logCount": float64(1),
+				grpcStatusKey: float64(codes.NotFound), grpcMessageKey: "user not found", grpcRequestSizeKey: float64(7), grpcResponseSizeKey: float64(0),
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			interceptor := UnaryServerInterceptor(NewConsoleExporter().Writer(&buf).Format(JSONFormat))
+
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"))
+			resp, err := interceptor(ctx, wrapperspb.String("alice"), &grpc.UnaryServerInfo{FullMethod: "/pkg.Users/Get"}, func(ctx context.Context, _ any) (any, error) {
+				Ctx(ctx).Info("get user")
+				if tt.err != nil {
+					return nil, tt.err
+				}
+
+				return wrapperspb.String("Alice"), nil
+			})
+			if status.Code(err) != status.Code(tt.wantErr) {
+				t.Fatalf("UnaryServerInterceptor() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.err == nil && !proto.Equal(resp.(proto.Message), wrapperspb.String("Alice")) {
+				t.Errorf("UnaryServerInterceptor() resp = %v, want %v", resp, wrapperspb.String("Alice"))
+			}
+
+			child, parent := grpcLogs(t, &buf)
+			if child["traceID"] != "4bf92f3577b34da6a3ce929d0e0e4736" || parent["traceID"] != child["traceID"] {
+				t.Errorf("UnaryServerInterceptor() child traceID = %v, parent traceID = %v", child["traceID"], parent["traceID"])
+			}
+			ignore := cmpopts.IgnoreMapEntries(func(k string, _ any) bool { return k == "time" || k == "elapsed" || k == "traceID" })
+			if diff := cmp.Diff(tt.wantParent, parent, ignore); diff != "" {
+				t.Errorf("UnaryServerInterceptor() parent mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	recv []proto.Message
+	sent []proto.Message
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testServerStream) RecvMsg(m any) error {
+	if len(s.recv) == 0 {
+		return io.EOF
+	}
+	proto.Merge(m.(proto.Message), s.recv[0])
+	s.recv = s.recv[1:]
+
+	return nil
+}
+
+func (s *testServerStream) SendMsg(m any) error {
+	s.sent = append(s.sent, m.(proto.Message))
+
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	interceptor := StreamServerInterceptor(NewConsoleExporter().Writer(&buf).Format(JSONFormat))
+
+	ss := &testServerStream{ctx: context.Background(), recv: []proto.Message{wrapperspb.String("alice"), wrapperspb.String("bob")}}
+	err := interceptor(nil, ss, &grpc.StreamServerInfo{FullMethod: "/pkg.Users/Stream"}, func(_ any, stream grpc.ServerStream) error {
+		for {
+			m := &wrapperspb.StringValue{}
+			if err := stream.RecvMsg(m); err != nil {
+				if err == io.EOF {
+					break
+				}
+
+				return err
+			}
+			Ctx(stream.Context()).Infof("received %s", m.GetValue())
+		}
+
+		return stream.SendMsg(wrapperspb.String("done"))
+	})
+	if err != nil {
+		t.Fatalf("StreamServerInterceptor() error = %v", err)
+	}
+	if len(ss.sent) != 1 {
+		t.Errorf("StreamServerInterceptor() sent = %v, want 1 message", ss.sent)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("StreamServerInterceptor() output = %q, want 3 lines", buf.String())
+	}
+	var parent map[string]any
+	if err := json.Unmarshal([]byte(lines[2]), &parent); err != nil {
+		t.Fatalf("json.Unmarshal() parent error = %v", err)
+	}
+	want := map[string]any{
+		"level": "INFO", "msg": "POST /pkg.Users/Stream", "method": "POST", "path": "/pkg.Users/Stream", "status": float64(200),
+		"requestSize": float64(0), "responseSize": float64(0), "logCount": float64(2),
+		grpcStatusKey: float64(0), grpcRequestSizeKey: float64(12), grpcResponseSizeKey: float64(6),
+	}
+	ignore := cmpopts.IgnoreMapEntries(func(k string, _ any) bool { return k == "time" || k == "elapsed" || k == "traceID" })
+	if diff := cmp.Diff(want, parent, ignore); diff != "" {
+		t.Errorf("StreamServerInterceptor() parent mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestServerInterceptor_panic(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	exporter := NewConsoleExporter().Writer(&buf).Format(JSONFormat).RecoverPanics(false)
+
+	_, err := UnaryServerInterceptor(exporter)(context.Background(), wrapperspb.String("alice"), &grpc.UnaryServerInfo{FullMethod: "/pkg.Users/Get"}, func(context.Context, any) (any, error) {
+		panic("user is nil")
+	})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("UnaryServerInterceptor() error = %v, want the Internal status", err)
+	}
+
+	err = StreamServerInterceptor(exporter)(nil, &testServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/pkg.Users/Stream"}, func(any, grpc.ServerStream) error {
+		panic("user is nil")
+	})
+	if status.Code(err) != codes.Internal {
+		t.Errorf("StreamServerInterceptor() error = %v, want the Internal status", err)
+	}
+}
@@ -1,11 +1,23 @@
 package logger
 
 import (
+	"bufio"
 	"crypto/rand"
 	"encoding/hex"
+	"io"
+	"log/slog"
+	"maps"
+	"net"
 	"net/http"
+	"net/url"
+	"runtime/debug"
+	"slices"
 	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
+	"cloud.google.com/go/logging"
 	"github.com/go-playground/errors/v5"
 )
 
@@ -23,6 +35,89 @@ type Exporter interface {
 	Middleware() func(http.Handler) http.Handler
 }
 
+// DefaultStatusLevel is the default mapping of the HTTP response status to the minimum level of the
+// parent request log: slog.LevelError for server errors (5xx), otherwise slog.LevelInfo
+func DefaultStatusLevel(status int) slog.Level {
+	if status > 499 {
+		return slog.LevelError
+	}
+
+	return slog.LevelInfo
+}
+
+// ClientErrorStatusLevel maps the HTTP response status to the minimum level of the parent request log:
+// slog.LevelError for server errors (5xx), slog.LevelWarn for client errors (4xx), otherwise slog.LevelInfo
+func ClientErrorStatusLevel(status int) slog.Level {
+	if status > 399 && status < 500 {
+		return slog.LevelWarn
+	}
+
+	return DefaultStatusLevel(status)
+}
+
+// statusLevel returns the minimum level of the parent request log for the status using f,
+// or DefaultStatusLevel when f is nil
+func statusLevel(f func(status int) slog.Level, status int) slog.Level {
+	if f == nil {
+		return DefaultStatusLevel(status)
+	}
+
+	return f(status)
+}
+
+// levelSeverity returns the logging.Severity for the slog.Level
+func levelSeverity(level slog.Level) logging.Severity {
+	switch {
+	case level >= LevelCritical:
+		return logging.Critical
+	case level >= slog.LevelError:
+		return logging.Error
+	case level >= slog.LevelWarn:
+		return logging.Warning
+	case level >= slog.LevelInfo:
+		return logging.Info
+	case level >= slog.LevelDebug:
+		return logging.Debug
+	default:
+		return logging.Default // Trace logs, Cloud Logging has no lower severity
+	}
+}
+
+// severityLevel returns the slog.Level for the logging.Severity
+func severityLevel(severity logging.Severity) slog.Level {
+	switch {
+	case severity >= logging.Critical:
+		return LevelCritical
+	case severity >= logging.Error:
+		return slog.LevelError
+	case severity >= logging.Warning:
+		return slog.LevelWarn
+	case severity >= logging.Info:
+		return slog.LevelInfo
+	case severity >= logging.Debug:
+		return slog.LevelDebug
+	default:
+		return LevelTrace
+	}
+}
+
+// newLevelVar returns a slog.LevelVar set to level
+func newLevelVar(level slog.Level) *slog.LevelVar {
+	v := new(slog.LevelVar)
+	v.Set(level)
+
+	return v
+}
+
+// minSeverity returns the severity of the minimum level v, or of slog.LevelDebug when v is nil
+func minSeverity(v *slog.LevelVar) logging.Severity {
+	if v == nil {
+		return logging.Debug
+	}
+
+	return levelSeverity(v.Level())
+}
+
 func requestSize(length string) int64 {
 	l, err := strconv.Atoi(length)
 	if err != nil {
@@ -32,18 +127,189 @@ func requestSize(length string) int64 {
 	return int64(l)
 }
 
-func newResponseRecorder(w http.ResponseWriter) responseRecorder {
-	if _, ok := w.(http.Flusher); ok {
-		return &recorderFlusher{
-			recorder: recorder{
-				ResponseWriter: w,
-			},
+// bodyCounter counts the bytes of the request body read by the handler
+type bodyCounter struct {
+	io.ReadCloser
+	read atomic.Int64
+}
+
+// countBody replaces the body of the request with a bodyCounter
+func countBody(r *http.Request) *bodyCounter {
+	c := &bodyCounter{ReadCloser: r.Body}
+	if r.Body != nil && r.Body != http.NoBody {
+		r.Body = c
+	}
+
+	return c
+}
+
+// Read implements io.Reader, the error is not wrapped so io.EOF is returned as is
+func (c *bodyCounter) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.read.Add(int64(n))
+
+	return n, err
+}
+
+// size returns the number of bytes of the request body read by the handler, or the Content-Length when
+// it is larger, e.g. when the handler did not read the body. Chunked uploads have no Content-Length.
+func (c *bodyCounter) size(r *http.Request) int64 {
+	return max(c.read.Load(), requestSize(r.Header.Get("Content-Length")))
+}
+
+// scrubQuery returns a shallow copy of r with the values of the query parameters masked, or r
+// when none of the parameters are in the query. The parameter names are case-insensitive.
+func scrubQuery(r *http.Request, params []string) *http.Request {
+	if len(params) == 0 || r.URL.RawQuery == "" {
+		return r
+	}
+
+	pairs := strings.Split(r.URL.RawQuery, "&")
+	var scrubbed bool
+	for i, pair := range pairs {
+		rawKey, _, _ := strings.Cut(pair, "=")
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			key = rawKey
+		}
+		if slices.ContainsFunc(params, func(p string) bool { return strings.EqualFold(p, key) }) {
+			pairs[i] = rawKey + "=" + redactedValue
+			scrubbed = true
 		}
 	}
+	if !scrubbed {
+		return r
+	}
 
-	return &recorder{
+	u := *r.URL
+	u.RawQuery = strings.Join(pairs, "&")
+	r2 := new(http.Request)
+	*r2 = *r
+	r2.URL = &u
+
+	return r2
+}
+
+// newResponseRecorder returns a responseRecorder for w, which implements http.Flusher, http.Hijacker, http.Pusher,
+// and io.ReaderFrom only when w does, so handlers detect the features of the connection like without the middleware
+func newResponseRecorder(w http.ResponseWriter) responseRecorder {
+	r := &recorder{
 		ResponseWriter: w,
+		start:          time.Now(),
 	}
+	_, flush := w.(http.Flusher)
+	_, hijack := w.(http.Hijacker)
+	_, push := w.(http.Pusher)
+	_, readFrom := w.(io.ReaderFrom)
+
+	if !flush {
+		return newUnflushedRecorder(r, hijack, push, readFrom)
+	}
+
+	// the cases with more interfaces come first, so the first case that matches has all the interfaces of w
+	switch {
+	case hijack && push && readFrom:
+		return struct {
+			*recorder
+			recorderFlusher
+			recorderHijacker
+			recorderPusher
+			recorderReaderFrom
+		}{r, recorderFlusher{r}, recorderHijacker{r}, recorderPusher{r}, recorderReaderFrom{r}}
+	case hijack && push:
+		return struct {
+			*recorder
+			recorderFlusher
+			recorderHijacker
+			recorderPusher
+		}{r, recorderFlusher{r}, recorderHijacker{r}, recorderPusher{r}}
+	case hijack && readFrom:
+		return struct {
+			*recorder
+			recorderFlusher
+			recorderHijacker
+			recorderReaderFrom
+		}{r, recorderFlusher{r}, recorderHijacker{r}, recorderReaderFrom{r}}
+	case push && readFrom:
+		return struct {
+			*recorder
+			recorderFlusher
+			recorderPusher
+			recorderReaderFrom
+		}{r, recorderFlusher{r}, recorderPusher{r}, recorderReaderFrom{r}}
+	case hijack:
+		return struct {
+			*recorder
+			recorderFlusher
+			recorderHijacker
+		}{r, recorderFlusher{r}, recorderHijacker{r}}
+	case push:
+		return struct {
+			*recorder
+			recorderFlusher
+			recorderPusher
+		}{r, recorderFlusher{r}, recorderPusher{r}}
+	case readFrom:
+		return struct {
+			*recorder
+			recorderFlusher
+			recorderReaderFrom
+		}{r, recorderFlusher{r}, recorderReaderFrom{r}}
+	}
+
+	return struct {
+		*recorder
+		recorderFlusher
+	}{r, recorderFlusher{r}}
+}
+
+// newUnflushedRecorder returns the responseRecorder of newResponseRecorder for a writer that does not implement http.Flusher
+func newUnflushedRecorder(r *recorder, hijack, push, readFrom bool) responseRecorder {
+	// the cases with more interfaces come first, so the first case that matches has all the interfaces of the writer
+	switch {
+	case hijack && push && readFrom:
+		return struct {
+			*recorder
+			recorderHijacker
+			recorderPusher
+			recorderReaderFrom
+		}{r, recorderHijacker{r}, recorderPusher{r}, recorderReaderFrom{r}}
+	case hijack && push:
+		return struct {
+			*recorder
+			recorderHijacker
+			recorderPusher
+		}{r, recorderHijacker{r}, recorderPusher{r}}
+	case hijack && readFrom:
+		return struct {
+			*recorder
+			recorderHijacker
+			recorderReaderFrom
+		}{r, recorderHijacker{r}, recorderReaderFrom{r}}
+	case push && readFrom:
+		return struct {
+			*recorder
+			recorderPusher
+			recorderReaderFrom
+		}{r, recorderPusher{r}, recorderReaderFrom{r}}
+	case hijack:
+		return struct {
+			*recorder
+			recorderHijacker
+		}{r, recorderHijacker{r}}
+	case push:
+		return struct {
+			*recorder
+			recorderPusher
+		}{r, recorderPusher{r}}
+	case readFrom:
+		return struct {
+			*recorder
+			recorderReaderFrom
+		}{r, recorderReaderFrom{r}}
+	}
+
+	return r
 }
 
 type responseRecorder interface {
@@ -52,12 +318,19 @@ type responseRecorder interface {
 	WriteHeader(status int)
 	Write(b []byte) (int, error)
 	Length() int64
+	Timing() (firstByte, handler, write time.Duration)
+	Hijacked() bool
+	panicked(write bool)
 }
 
 type recorder struct {
 	http.ResponseWriter
-	status int
-	length int64
+	status    int
+	length    int64
+	start     time.Time
+	firstByte time.Duration // time from start to the first write of the response, 0 before
+	writing   time.Duration // time spent writing and flushing the response
+	hijacked  bool
 }
 
 func (r *recorder) Status() int {
@@ -70,11 +343,15 @@ func (r *recorder) Status() int {
 
 func (r *recorder) WriteHeader(status int) {
 	r.status = status
+	r.wrote()
 	r.ResponseWriter.WriteHeader(status)
 }
 
 func (r *recorder) Write(b []byte) (int, error) {
+	r.wrote()
+	begin := time.Now()
 	n, err := r.ResponseWriter.Write(b)
+	r.writing += time.Since(begin)
 	r.length += int64(n)
 	if err != nil {
 		return n, errors.Wrap(err, "http.ResponseWriter.Write()")
@@ -87,14 +364,163 @@ func (r *recorder) Length() int64 {
 	return r.length
 }
 
+// wrote records the time to the first byte of the response
+func (r *recorder) wrote() {
+	if r.firstByte == 0 {
+		r.firstByte = max(time.Since(r.start), 1)
+	}
+}
+
+// Timing returns the latency breakdown of the response: the time to the first write of the response, the time
+// spent in the handler without writing, and the time spent writing the response, which is high for slow clients.
+// The time to the first byte is the total time when the handler did not write.
+func (r *recorder) Timing() (firstByte, handler, write time.Duration) {
+	total := time.Since(r.start)
+	firstByte = r.firstByte
+	if firstByte == 0 {
+		firstByte = total
+	}
+
+	return firstByte, max(total-r.writing, 0), r.writing
+}
+
+// panicked sets the status to 500 after a panic of the handler. When write is set, the header is written
+// if the handler did not start the response, otherwise only the status of the parent log is changed.
+func (r *recorder) panicked(write bool) {
+	if write && r.status == 0 && r.length == 0 {
+		r.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+	r.status = http.StatusInternalServerError
+}
+
+// Hijacked reports whether the handler took over the connection
+func (r *recorder) Hijacked() bool {
+	return r.hijacked
+}
+
+// Unwrap returns the wrapped http.ResponseWriter, so http.ResponseController methods like SetWriteDeadline
+// and EnableFullDuplex reach it
+func (r *recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// recorderFlusher adds http.Flusher to a recorder of an http.ResponseWriter that implements it
 type recorderFlusher struct {
-	recorder
+	*recorder
+}
+
+func (r recorderFlusher) Flush() {
+	r.wrote()
+	begin := time.Now()
+	r.ResponseWriter.(http.Flusher).Flush()
+	r.writing += time.Since(begin)
 }
 
-func (r *recorderFlusher) Flush() {
-	if f, ok := r.ResponseWriter.(http.Flusher); ok {
-		f.Flush()
+// recorderHijacker adds http.Hijacker to a recorder of an http.ResponseWriter that implements it
+type recorderHijacker struct {
+	*recorder
+}
+
+// Hijack implements http.Hijacker, so connections can be upgraded, e.g. to websockets. The status of
+// the parent log is 101 (Switching Protocols) when the handler did not write one.
+func (r recorderHijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := r.ResponseWriter.(http.Hijacker).Hijack()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "http.Hijacker.Hijack()")
 	}
+	r.hijacked = true
+	if r.status == 0 {
+		r.status = http.StatusSwitchingProtocols
+	}
+
+	return conn, rw, nil
+}
+
+// recorderPusher adds http.Pusher to a recorder of an http.ResponseWriter that implements it, for HTTP/2 server push
+type recorderPusher struct {
+	*recorder
+}
+
+func (r recorderPusher) Push(target string, opts *http.PushOptions) error {
+	if err := r.ResponseWriter.(http.Pusher).Push(target, opts); err != nil {
+		return errors.Wrap(err, "http.Pusher.Push()")
+	}
+
+	return nil
+}
+
+// recorderReaderFrom adds io.ReaderFrom to a recorder of an http.ResponseWriter that implements it, so the
+// sendfile optimization of the http.ResponseWriter is used when serving files
+type recorderReaderFrom struct {
+	*recorder
+}
+
+func (r recorderReaderFrom) ReadFrom(src io.Reader) (int64, error) {
+	r.wrote()
+	begin := time.Now()
+	n, err := r.ResponseWriter.(io.ReaderFrom).ReadFrom(src)
+	r.writing += time.Since(begin)
+	r.length += n
+	if err != nil {
+		return n, errors.Wrap(err, "io.ReaderFrom.ReadFrom()")
+	}
+
+	return n, nil
+}
+
+// hijackedKey is the attribute of the parent log of a request whose connection was hijacked by the handler
+const hijackedKey = "hijacked"
+
+// keys of the latency breakdown attributes of the parent log added with the PhaseTiming option
+const (
+	latencyFirstByteKey = "latency.first_byte_ms"
+	latencyHandlerKey   = "latency.handler_ms"
+	latencyWriteKey     = "latency.write_ms"
+)
+
+// responseAttributes returns the attributes of the parent log recorded by the response recorder, the same for
+// all exporters: the response headers of names, the latency breakdown when phaseTiming is set, and whether the
+// connection was hijacked
+func responseAttributes(sw responseRecorder, names []string, phaseTiming bool) map[string]any {
+	attrs := headerAttributes(responseHeaderPrefix, sw.Header(), names)
+	if phaseTiming {
+		maps.Copy(attrs, timingAttributes(sw))
+	}
+	if sw.Hijacked() {
+		attrs[hijackedKey] = true
+	}
+
+	return attrs
+}
+
+// timingAttributes returns the latency breakdown attributes of the response in milliseconds
+func timingAttributes(sw responseRecorder) map[string]any {
+	firstByte, handler, write := sw.Timing()
+
+	return map[string]any{
+		latencyFirstByteKey: float64(firstByte) / float64(time.Millisecond),
+		latencyHandlerKey:   float64(handler) / float64(time.Millisecond),
+		latencyWriteKey:     float64(write) / float64(time.Millisecond),
+	}
+}
+
+// serveRecover serves the request with next, and recovers a panic of next so the parent log is still written.
+// The panic is logged as an Error child log with the stack, and the status of the parent log is set to 500, which
+// is also written to the response when recoverPanics is set. The recovered value is returned, so it can be
+// re-panicked after the parent log is written. http.ErrAbortHandler is recovered but not logged.
+func serveRecover(l ctxLogger, next http.Handler, sw responseRecorder, r *http.Request, recoverPanics bool) (v any) {
+	defer func() {
+		if v = recover(); v == nil || v == http.ErrAbortHandler {
+			return
+		}
+		sw.panicked(recoverPanics)
+		l.Errorf(r.Context(), "panic: %v\n\n%s", v, debug.Stack())
+	}()
+	next.ServeHTTP(sw, r)
+
+	return nil
 }
 
 // generateID provides an id that matches the trace id format
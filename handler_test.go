@@ -1,13 +1,21 @@
 package logger
 
 import (
+	"bufio"
 	"errors"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"cloud.google.com/go/logging"
 	"github.com/go-test/deep"
+	"go.uber.org/mock/gomock"
 )
 
 func TestNewRequestLogger(t *testing.T) {
@@ -30,11 +38,11 @@ func TestNewRequestLogger(t *testing.T) {
 				client := &logging.Client{}
 
 				return &gcpHandler{
-					next:         next,
-					parentLogger: client.Logger("request_parent_log"),
-					childLogger:  client.Logger("request_child_log"),
-					projectID:    "My first project",
-					logAll:       true,
+					handlerOptions: handlerOptions{logAll: true},
+					next:           next,
+					parentLogger:   client.Logger("request_parent_log"),
+					childLogger:    client.Logger("request_child_log"),
+					projectID:      "My first project",
 				}
 			},
 		},
@@ -51,6 +59,77 @@ func TestNewRequestLogger(t *testing.T) {
 	}
 }
 
+func Test_statusLevel(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		f      func(status int) slog.Level
+		status int
+	}
+	tests := []struct {
+		name         string
+		args         args
+		want         slog.Level
+		wantSeverity logging.Severity
+	}{
+		{
+			name:         "default 200",
+			args:         args{status: http.StatusOK},
+			want:         slog.LevelInfo,
+			wantSeverity: logging.Info,
+		},
+		{
+			name:         "default 404",
+			args:         args{status: http.StatusNotFound},
+			want:         slog.LevelInfo,
+			wantSeverity: logging.Info,
+		},
+		{
+			name:         "default 500",
+			args:         args{status: http.StatusInternalServerError},
+			want:         slog.LevelError,
+			wantSeverity: logging.Error,
+		},
+		{
+			name:         "client error 200",
+			args:         args{f: ClientErrorStatusLevel, status: http.StatusOK},
+			want:         slog.LevelInfo,
+			wantSeverity: logging.Info,
+		},
+		{
+			name:         "client error 404",
+			args:         args{f: ClientErrorStatusLevel, status: http.StatusNotFound},
+			want:         slog.LevelWarn,
+			wantSeverity: logging.Warning,
+		},
+		{
+			name:         "client error 503",
+			args:         args{f: ClientErrorStatusLevel, status: http.StatusServiceUnavailable},
+			want:         slog.LevelError,
+			wantSeverity: logging.Error,
+		},
+		{
+			name:         "custom",
+			args:         args{f: func(int) slog.Level { return slog.LevelDebug }, status: http.StatusInternalServerError},
+			want:         slog.LevelDebug,
+			wantSeverity: logging.Debug,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := statusLevel(tt.args.f, tt.args.status)
+			if got != tt.want {
+				t.Errorf("statusLevel() = %v, want %v", got, tt.want)
+			}
+			if severity := levelSeverity(got); severity != tt.wantSeverity {
+				t.Errorf("levelSeverity() = %v, want %v", severity, tt.wantSeverity)
+			}
+		})
+	}
+}
+
 func Test_requestSize(t *testing.T) {
 	t.Parallel()
 
@@ -87,6 +166,125 @@ func Test_requestSize(t *testing.T) {
 	}
 }
 
+func Test_bodyCounter_size(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		body          string
+		contentLength string
+		read          int
+		want          int64
+	}{
+		{
+			name: "chunked body read",
+			body: "hello world",
+			read: -1,
+			want: 11,
+		},
+		{
+			name:          "content length without reading",
+			body:          "hello world",
+			contentLength: "11",
+			want:          11,
+		},
+		{
+			name: "chunked body partially read",
+			body: "hello world",
+			read: 5,
+			want: 5,
+		},
+		{
+			name: "no body",
+			read: -1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodPost, "/", http.NoBody)
+			if tt.body != "" {
+				r.Body = io.NopCloser(strings.NewReader(tt.body))
+			}
+			if tt.contentLength != "" {
+				r.Header.Set("Content-Length", tt.contentLength)
+			}
+
+			c := countBody(r)
+			switch {
+			case tt.read < 0:
+				if _, err := io.ReadAll(r.Body); err != nil {
+					t.Fatalf("io.ReadAll() error = %v", err)
+				}
+			case tt.read > 0:
+				if _, err := io.ReadFull(r.Body, make([]byte, tt.read)); err != nil {
+					t.Fatalf("io.ReadFull() error = %v", err)
+				}
+			}
+
+			if got := c.size(r); got != tt.want {
+				t.Errorf("bodyCounter.size() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_scrubQuery(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		url    string
+		params []string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "no params",
+			args: args{url: "/path?token=abc"},
+			want: "/path?token=abc",
+		},
+		{
+			name: "no query",
+			args: args{url: "/path", params: []string{"token"}},
+			want: "/path",
+		},
+		{
+			name: "scrubbed",
+			args: args{url: "/path?page=2&Token=abc&api_key=xyz&api_key=zzz", params: []string{"token", "api_key"}},
+			want: "/path?page=2&Token=[REDACTED]&api_key=[REDACTED]&api_key=[REDACTED]",
+		},
+		{
+			name: "escaped key",
+			args: args{url: "/path?api%5Fkey=xyz", params: []string{"api_key"}},
+			want: "/path?api%5Fkey=[REDACTED]",
+		},
+		{
+			name: "not present",
+			args: args{url: "/path?page=2", params: []string{"token"}},
+			want: "/path?page=2",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := httptest.NewRequest(http.MethodGet, tt.args.url, http.NoBody)
+			original := r.URL.String()
+			if got := scrubQuery(r, tt.args.params).URL.String(); got != tt.want {
+				t.Errorf("scrubQuery() = %v, want %v", got, tt.want)
+			}
+			if r.URL.String() != original {
+				t.Errorf("scrubQuery() modified the request URL = %v, want %v", r.URL.String(), original)
+			}
+		})
+	}
+}
+
 func Test_recorder_Status(t *testing.T) {
 	t.Parallel()
 
@@ -335,7 +533,7 @@ func Test_recorderFlusher_Flush(t *testing.T) {
 	t.Parallel()
 
 	type fields struct {
-		recorder http.ResponseWriter
+		w http.ResponseWriter
 	}
 	tests := []struct {
 		name        string
@@ -346,7 +544,7 @@ func Test_recorderFlusher_Flush(t *testing.T) {
 		{
 			name: "Flusher",
 			fields: fields{
-				recorder: newResponseRecorder(&testResponseWriterFlusher{}),
+				w: &testResponseWriterFlusher{},
 			},
 			wantFlusher: true,
 			flushCount:  1,
@@ -354,7 +552,7 @@ func Test_recorderFlusher_Flush(t *testing.T) {
 		{
 			name: "No flusher",
 			fields: fields{
-				recorder: newResponseRecorder(&testResponseWriter{}),
+				w: &testResponseWriter{},
 			},
 			wantFlusher: false,
 			flushCount:  0,
@@ -364,7 +562,7 @@ func Test_recorderFlusher_Flush(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			r := tt.fields.recorder
+			r := newResponseRecorder(tt.fields.w)
 			f, gotFlusher := r.(http.Flusher)
 			if gotFlusher {
 				f.Flush()
@@ -374,11 +572,7 @@ func Test_recorderFlusher_Flush(t *testing.T) {
 			}
 
 			if tt.wantFlusher {
-				rf, ok := r.(*recorderFlusher)
-				if !ok {
-					t.Fatalf("recorder not a recorderFlusher")
-				}
-				c, ok := rf.ResponseWriter.(*testResponseWriterFlusher)
+				c, ok := tt.fields.w.(*testResponseWriterFlusher)
 				if !ok {
 					t.Fatalf("ResponseWriter not a testResponseWriterFlusher")
 				}
@@ -411,3 +605,492 @@ type testResponseWriterFlusher struct {
 func (t *testResponseWriterFlusher) Flush() {
 	t.flushed++
 }
+
+func Test_serveRecover(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		recovers   bool
+		prepare    func(l *MockctxLogger)
+		wantValue  any
+		wantStatus int
+		wantCode   int
+	}{
+		{
+			name:       "no panic",
+			handler:    func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) },
+			prepare:    func(*MockctxLogger) {},
+			wantStatus: http.StatusNoContent,
+			wantCode:   http.StatusNoContent,
+		},
+		{
+			name:     "panic before response",
+			handler:  func(http.ResponseWriter, *http.Request) { panic("boom") },
+			recovers: true,
+			prepare: func(l *MockctxLogger) {
+				l.EXPECT().Errorf(gomock.Any(), "panic: %v\n\n%s", "boom", gomock.Any()).Times(1)
+			},
+			wantValue:  "boom",
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   http.StatusInternalServerError,
+		},
+		{
+			name: "panic after response",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte("partial"))
+				panic("boom")
+			},
+			recovers: true,
+			prepare: func(l *MockctxLogger) {
+				l.EXPECT().Errorf(gomock.Any(), "panic: %v\n\n%s", "boom", gomock.Any()).Times(1)
+			},
+			wantValue:  "boom",
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:    "panic without recovery",
+			handler: func(http.ResponseWriter, *http.Request) { panic("boom") },
+			prepare: func(l *MockctxLogger) {
+				l.EXPECT().Errorf(gomock.Any(), "panic: %v\n\n%s", "boom", gomock.Any()).Times(1)
+			},
+			wantValue:  "boom",
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   http.StatusOK,
+		},
+		{
+			name:       "abort handler",
+			handler:    func(http.ResponseWriter, *http.Request) { panic(http.ErrAbortHandler) },
+			prepare:    func(*MockctxLogger) {},
+			wantValue:  http.ErrAbortHandler,
+			wantStatus: http.StatusOK,
+			wantCode:   http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			l := NewMockctxLogger(gomock.NewController(t))
+			tt.prepare(l)
+			w := httptest.NewRecorder()
+			sw := newResponseRecorder(w)
+
+			got := serveRecover(l, tt.handler, sw, httptest.NewRequest(http.MethodGet, "/", http.NoBody), tt.recovers)
+			if got != tt.wantValue {
+				t.Errorf("serveRecover() = %v, want %v", got, tt.wantValue)
+			}
+			if sw.Status() != tt.wantStatus {
+				t.Errorf("Status() = %v, want %v", sw.Status(), tt.wantStatus)
+			}
+			if w.Code != tt.wantCode {
+				t.Errorf("response code = %v, want %v", w.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	conn net.Conn
+}
+
+func (h *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return h.conn, nil, nil
+}
+
+func Test_recorder_Hijack(t *testing.T) {
+	t.Parallel()
+
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	sw := newResponseRecorder(&hijackRecorder{ResponseRecorder: httptest.NewRecorder(), conn: server})
+	if _, ok := sw.(http.Flusher); !ok {
+		t.Error("newResponseRecorder() does not implement http.Flusher")
+	}
+	conn, _, err := http.NewResponseController(sw).Hijack()
+	if err != nil {
+		t.Fatalf("Hijack() error = %v", err)
+	}
+	if conn != server {
+		t.Errorf("Hijack() conn = %v, want %v", conn, server)
+	}
+	if sw.Status() != http.StatusSwitchingProtocols {
+		t.Errorf("Status() = %v, want %v", sw.Status(), http.StatusSwitchingProtocols)
+	}
+	if !sw.Hijacked() {
+		t.Error("Hijacked() = false, want true")
+	}
+
+	notHijacker := newResponseRecorder(httptest.NewRecorder())
+	if _, ok := notHijacker.(http.Hijacker); ok {
+		t.Error("newResponseRecorder() implements http.Hijacker when the http.ResponseWriter does not")
+	}
+	if _, _, err := http.NewResponseController(notHijacker).Hijack(); !errors.Is(err, http.ErrNotSupported) {
+		t.Errorf("Hijack() error = %v, want %v", err, http.ErrNotSupported)
+	}
+	if notHijacker.Hijacked() {
+		t.Error("Hijacked() = true, want false")
+	}
+}
+
+type readerFromRecorder struct {
+	*httptest.ResponseRecorder
+	readFrom bool
+}
+
+func (w *readerFromRecorder) ReadFrom(src io.Reader) (int64, error) {
+	w.readFrom = true
+
+	return io.Copy(w.ResponseRecorder, src)
+}
+
+func Test_recorder_ReadFrom(t *testing.T) {
+	t.Parallel()
+
+	w := &readerFromRecorder{ResponseRecorder: httptest.NewRecorder()}
+	sw := newResponseRecorder(w)
+	n, err := sw.(io.ReaderFrom).ReadFrom(strings.NewReader("response"))
+	if err != nil {
+		t.Fatalf("ReadFrom() error = %v", err)
+	}
+	if n != 8 || sw.Length() != 8 {
+		t.Errorf("ReadFrom() = %v, Length() = %v, want 8", n, sw.Length())
+	}
+	if !w.readFrom {
+		t.Error("io.ReaderFrom.ReadFrom() not called")
+	}
+	if w.Body.String() != "response" {
+		t.Errorf("Body = %v, want response", w.Body.String())
+	}
+}
+
+type pushRecorder struct {
+	*httptest.ResponseRecorder
+	target string
+}
+
+func (w *pushRecorder) Push(target string, _ *http.PushOptions) error {
+	w.target = target
+
+	return nil
+}
+
+func Test_recorder_Push(t *testing.T) {
+	t.Parallel()
+
+	w := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	if err := newResponseRecorder(w).(http.Pusher).Push("/app.js", nil); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	if w.target != "/app.js" {
+		t.Errorf("Push() target = %v, want %v", w.target, "/app.js")
+	}
+}
+
+type deadlineWriter struct {
+	http.ResponseWriter
+	deadline   time.Time
+	fullDuplex bool
+}
+
+func (w *deadlineWriter) SetWriteDeadline(deadline time.Time) error {
+	w.deadline = deadline
+
+	return nil
+}
+
+func (w *deadlineWriter) EnableFullDuplex() error {
+	w.fullDuplex = true
+
+	return nil
+}
+
+type deadlineFlusher struct {
+	*deadlineWriter
+}
+
+func (w *deadlineFlusher) Flush() {}
+
+func Test_recorder_Unwrap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		flusher bool
+	}{
+		{
+			name: "recorder",
+		},
+		{
+			name:    "Flusher",
+			flusher: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := &deadlineWriter{ResponseWriter: httptest.NewRecorder()}
+			var rw http.ResponseWriter = w
+			if tt.flusher {
+				rw = &deadlineFlusher{deadlineWriter: w}
+			}
+			sw := newResponseRecorder(rw)
+			if _, ok := sw.(http.Flusher); ok != tt.flusher {
+				t.Fatalf("newResponseRecorder() implements http.Flusher = %v, want %v", ok, tt.flusher)
+			}
+			if got := sw.(interface{ Unwrap() http.ResponseWriter }).Unwrap(); got != rw {
+				t.Errorf("Unwrap() = %v, want %v", got, rw)
+			}
+
+			deadline := time.Now().Add(time.Minute)
+			rc := http.NewResponseController(sw)
+			if err := rc.SetWriteDeadline(deadline); err != nil {
+				t.Fatalf("SetWriteDeadline() error = %v", err)
+			}
+			if err := rc.EnableFullDuplex(); err != nil {
+				t.Fatalf("EnableFullDuplex() error = %v", err)
+			}
+			if !w.deadline.Equal(deadline) {
+				t.Errorf("SetWriteDeadline() deadline = %v, want %v", w.deadline, deadline)
+			}
+			if !w.fullDuplex {
+				t.Error("EnableFullDuplex() fullDuplex = false, want true")
+			}
+		})
+	}
+}
+
+type hijackWriter struct {
+	http.ResponseWriter
+}
+
+func (*hijackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, nil
+}
+
+type readerFromWriter struct {
+	http.ResponseWriter
+}
+
+func (*readerFromWriter) ReadFrom(io.Reader) (int64, error) {
+	return 0, nil
+}
+
+type flushHijackPushWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (*flushHijackPushWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, nil
+}
+
+func (*flushHijackPushWriter) Push(string, *http.PushOptions) error {
+	return nil
+}
+
+type flushHijackPushReaderFromWriter struct {
+	flushHijackPushWriter
+}
+
+func (*flushHijackPushReaderFromWriter) ReadFrom(io.Reader) (int64, error) {
+	return 0, nil
+}
+
+func Test_newResponseRecorder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		w            http.ResponseWriter
+		wantFlusher  bool
+		wantHijack   bool
+		wantPusher   bool
+		wantReadFrom bool
+	}{
+		{
+			name: "none",
+			w:    &testResponseWriter{},
+		},
+		{
+			name:        "Flusher",
+			w:           httptest.NewRecorder(),
+			wantFlusher: true,
+		},
+		{
+			name:        "Flusher and Hijacker",
+			w:           &hijackRecorder{ResponseRecorder: httptest.NewRecorder()},
+			wantFlusher: true,
+			wantHijack:  true,
+		},
+		{
+			name:        "Flusher and Pusher",
+			w:           &pushRecorder{ResponseRecorder: httptest.NewRecorder()},
+			wantFlusher: true,
+			wantPusher:  true,
+		},
+		{
+			name:         "Flusher and ReaderFrom",
+			w:            &readerFromRecorder{ResponseRecorder: httptest.NewRecorder()},
+			wantFlusher:  true,
+			wantReadFrom: true,
+		},
+		{
+			name:        "Flusher, Hijacker, and Pusher",
+			w:           &flushHijackPushWriter{ResponseRecorder: httptest.NewRecorder()},
+			wantFlusher: true,
+			wantHijack:  true,
+			wantPusher:  true,
+		},
+		{
+			name:         "Flusher, Hijacker, Pusher, and ReaderFrom",
+			w:            &flushHijackPushReaderFromWriter{flushHijackPushWriter{ResponseRecorder: httptest.NewRecorder()}},
+			wantFlusher:  true,
+			wantHijack:   true,
+			wantPusher:   true,
+			wantReadFrom: true,
+		},
+		{
+			name:       "Hijacker",
+			w:          &hijackWriter{ResponseWriter: &testResponseWriter{}},
+			wantHijack: true,
+		},
+		{
+			name:         "ReaderFrom",
+			w:            &readerFromWriter{ResponseWriter: &testResponseWriter{}},
+			wantReadFrom: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sw := newResponseRecorder(tt.w)
+			if _, ok := sw.(http.Flusher); ok != tt.wantFlusher {
+				t.Errorf("newResponseRecorder() implements http.Flusher = %v, want %v", ok, tt.wantFlusher)
+			}
+			if _, ok := sw.(http.Hijacker); ok != tt.wantHijack {
+				t.Errorf("newResponseRecorder() implements http.Hijacker = %v, want %v", ok, tt.wantHijack)
+			}
+			if _, ok := sw.(http.Pusher); ok != tt.wantPusher {
+				t.Errorf("newResponseRecorder() implements http.Pusher = %v, want %v", ok, tt.wantPusher)
+			}
+			if _, ok := sw.(io.ReaderFrom); ok != tt.wantReadFrom {
+				t.Errorf("newResponseRecorder() implements io.ReaderFrom = %v, want %v", ok, tt.wantReadFrom)
+			}
+			if got := sw.(interface{ Unwrap() http.ResponseWriter }).Unwrap(); got != tt.w {
+				t.Errorf("Unwrap() = %v, want %v", got, tt.w)
+			}
+		})
+	}
+}
+
+func Test_responseAttributes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		hijack      bool
+		phaseTiming bool
+		want        []string
+	}{
+		{
+			name: "headers",
+			want: []string{"http.response.header.content-type"},
+		},
+		{
+			name:        "phase timing",
+			phaseTiming: true,
+			want:        []string{"http.response.header.content-type", latencyFirstByteKey, latencyHandlerKey, latencyWriteKey},
+		},
+		{
+			name:   "hijacked",
+			hijack: true,
+			want:   []string{"http.response.header.content-type", hijackedKey},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, server := net.Pipe()
+			defer client.Close()
+			defer server.Close()
+
+			sw := newResponseRecorder(&hijackRecorder{ResponseRecorder: httptest.NewRecorder(), conn: server})
+			sw.Header().Set("Content-Type", "text/plain")
+			if tt.hijack {
+				if _, _, err := sw.(http.Hijacker).Hijack(); err != nil {
+					t.Fatalf("Hijack() error = %v", err)
+				}
+			}
+
+			var got []string
+			for k := range responseAttributes(sw, []string{"Content-Type", "X-Missing"}, tt.phaseTiming) {
+				got = append(got, k)
+			}
+			slices.Sort(got)
+			slices.Sort(tt.want)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("responseAttributes() keys = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type slowResponseWriter struct {
+	*httptest.ResponseRecorder
+	delay time.Duration
+}
+
+func (w *slowResponseWriter) Write(b []byte) (int, error) {
+	time.Sleep(w.delay)
+
+	return w.ResponseRecorder.Write(b)
+}
+
+func Test_recorder_Timing(t *testing.T) {
+	t.Parallel()
+
+	sw := newResponseRecorder(&slowResponseWriter{ResponseRecorder: httptest.NewRecorder(), delay: 20 * time.Millisecond})
+	time.Sleep(10 * time.Millisecond)
+	_, _ = sw.Write([]byte("response"))
+
+	firstByte, handler, write := sw.Timing()
+	if firstByte < 10*time.Millisecond {
+		t.Errorf("Timing() firstByte = %v, want >= 10ms", firstByte)
+	}
+	if write < 20*time.Millisecond {
+		t.Errorf("Timing() write = %v, want >= 20ms", write)
+	}
+	if handler < 10*time.Millisecond {
+		t.Errorf("Timing() handler = %v, want >= 10ms", handler)
+	}
+
+	attrs := timingAttributes(sw)
+	for _, k := range []string{latencyFirstByteKey, latencyHandlerKey, latencyWriteKey} {
+		if _, ok := attrs[k].(float64); !ok {
+			t.Errorf("timingAttributes()[%q] = %v, want float64", k, attrs[k])
+		}
+	}
+}
+
+func Test_recorder_Timing_noWrite(t *testing.T) {
+	t.Parallel()
+
+	sw := newResponseRecorder(httptest.NewRecorder())
+	time.Sleep(time.Millisecond)
+
+	firstByte, handler, write := sw.Timing()
+	if firstByte < time.Millisecond || write != 0 || handler < time.Millisecond {
+		t.Errorf("Timing() = %v, %v, %v, want firstByte and handler of the total time without writes", firstByte, handler, write)
+	}
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// hashKey is the secret key of Hash set with SetHashKey
+var hashKey atomic.Pointer[[]byte]
+
+// processHashKey is the secret key of Hash when SetHashKey is not called, generated randomly for each process
+var processHashKey = func() []byte {
+	key := make([]byte, sha256.BlockSize)
+	_, _ = rand.Read(key)
+
+	return key
+}()
+
+// SetHashKey sets the secret key of Hash. Services that correlate hashed values must use the same key,
+// and it should be kept secret so that the values cannot be found by hashing guesses. An empty key restores
+// the random key of the process.
+func SetHashKey(key []byte) {
+	key = slices.Clone(key)
+	hashKey.Store(&key)
+}
+
+// Hash returns the keyed hash (HMAC-SHA256) of value hex encoded, so that values like emails or user IDs
+// can be correlated across logs without storing them. The key is set with SetHashKey. Without it, a random
+// key is generated for each process, so the hashes cannot be reversed by hashing guesses, but are only
+// correlated within the logs of the process, and change when it restarts.
+func Hash(value string) string {
+	key := processHashKey
+	if k := hashKey.Load(); k != nil && len(*k) > 0 {
+		key = *k
+	}
+
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// hashProcessor returns a Processor that replaces the values of the attributes with the keys
+// (case-insensitive) with their Hash
+func hashProcessor(keys []string) Processor {
+	keys = slices.Clone(keys)
+
+	return func(e Entry) (Entry, bool) {
+		for k, v := range e.Attributes {
+			if slices.ContainsFunc(keys, func(key string) bool { return strings.EqualFold(key, k) }) {
+				e.Attributes[k] = Hash(fmt.Sprint(v))
+			}
+		}
+
+		return e, true
+	}
+}
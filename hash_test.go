@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHash(t *testing.T) {
+	// not parallel, the hash key is global
+	t.Cleanup(func() { SetHashKey(nil) })
+
+	SetHashKey([]byte("key"))
+	got := Hash("bob@example.com")
+	if len(got) != 64 {
+		t.Errorf("Hash() = %q, want 64 hex characters", got)
+	}
+	if Hash("bob@example.com") != got {
+		t.Errorf("Hash() is not deterministic")
+	}
+	if Hash("alice@example.com") == got {
+		t.Errorf("Hash() of different values are equal")
+	}
+
+	SetHashKey([]byte("other key"))
+	if Hash("bob@example.com") == got {
+		t.Errorf("Hash() with a different key is equal")
+	}
+
+	SetHashKey(nil)
+	mac := hmac.New(sha256.New, nil)
+	_, _ = mac.Write([]byte("bob@example.com"))
+	if got := Hash("bob@example.com"); got == hex.EncodeToString(mac.Sum(nil)) {
+		t.Errorf("Hash() without a key = %q, want the hash with the random key of the process", got)
+	}
+}
+
+func Test_hashProcessor(t *testing.T) {
+	t.Parallel()
+
+	got, ok := hashProcessor([]string{"email", "user_id"})(Entry{Attributes: map[string]any{
+		"Email":   "bob@example.com",
+		"user_id": 42,
+		"page":    2,
+	}})
+	if !ok {
+		t.Errorf("hashProcessor() dropped the entry")
+	}
+
+	want := map[string]any{
+		"Email":   Hash("bob@example.com"),
+		"user_id": Hash("42"),
+		"page":    2,
+	}
+	if diff := cmp.Diff(want, got.Attributes); diff != "" {
+		t.Errorf("hashProcessor() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+const (
+	requestHeaderPrefix  = "http.request.header."
+	responseHeaderPrefix = "http.response.header."
+)
+
+// sensitiveHeaders are the headers with values masked when recorded with RequestHeaders or ResponseHeaders
+var sensitiveHeaders = []string{"authorization", "proxy-authorization", "cookie", "set-cookie", "x-api-key", "x-auth-token", "x-csrf-token"}
+
+// headerAttributes returns the attributes of the headers in names that are present in h, with the key of
+// the lowercase header name after prefix, and the values joined with a comma. The values of sensitive headers
+// are masked.
+func headerAttributes(prefix string, h http.Header, names []string) map[string]any {
+	attrs := make(map[string]any)
+	for _, name := range names {
+		values := h.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+
+		name = strings.ToLower(name)
+		if slices.Contains(sensitiveHeaders, name) {
+			attrs[prefix+name] = redactedValue
+
+			continue
+		}
+		attrs[prefix+name] = strings.Join(values, ", ")
+	}
+
+	return attrs
+}
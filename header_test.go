@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_headerAttributes(t *testing.T) {
+	t.Parallel()
+
+	h := http.Header{}
+	h.Set("X-Request-Id", "abc")
+	h.Add("Accept", "text/html")
+	h.Add("Accept", "application/json")
+	h.Set("Authorization", "Bearer token")
+	h.Set("Cookie", "session=1")
+
+	tests := []struct {
+		name  string
+		names []string
+		want  map[string]any
+	}{
+		{
+			name: "none",
+			want: map[string]any{},
+		},
+		{
+			name:  "allowlist",
+			names: []string{"X-Request-ID", "accept", "Missing"},
+			want: map[string]any{
+				"http.request.header.x-request-id": "abc",
+				"http.request.header.accept":       "text/html, application/json",
+			},
+		},
+		{
+			name:  "sensitive",
+			names: []string{"Authorization", "Cookie"},
+			want: map[string]any{
+				"http.request.header.authorization": redactedValue,
+				"http.request.header.cookie":        redactedValue,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := headerAttributes(requestHeaderPrefix, h, tt.names)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("headerAttributes() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package logger
+
+import "net/http"
+
+// IdentityExtractor returns the attributes of the user or principal that made the request, e.g. the user ID,
+// tenant, or organization, which are added to the parent log. It returns nil when the request is anonymous.
+type IdentityExtractor func(r *http.Request) map[string]any
+
+// addIdentity adds the attributes returned by extract to the parent log of the request
+func addIdentity(l ctxLogger, r *http.Request, extract IdentityExtractor) {
+	if extract == nil {
+		return
+	}
+
+	for k, v := range extract(r) {
+		l.AddRequestAttribute(k, v)
+	}
+}
+
+// IdentityMiddleware returns middleware that adds the attributes returned by extract to the parent log.
+// It is placed after the authentication middleware, when the identity is stored in the request context, e.g.
+//
+//	r.Use(auth.Middleware, logger.IdentityMiddleware(func(r *http.Request) map[string]any {
+//		u := auth.User(r.Context())
+//		return map[string]any{"user.id": u.ID, "tenant.id": u.TenantID}
+//	}))
+func IdentityMiddleware(extract IdentityExtractor) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			addIdentity(fromReq(r), r, extract)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestIdentityMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		identity map[string]any
+		prepare  func(l *MockctxLogger)
+	}{
+		{
+			name:     "user",
+			identity: map[string]any{"user.id": "u1", "tenant.id": "t1"},
+			prepare: func(l *MockctxLogger) {
+				l.EXPECT().AddRequestAttribute("user.id", "u1").Times(1)
+				l.EXPECT().AddRequestAttribute("tenant.id", "t1").Times(1)
+			},
+		},
+		{
+			name:    "anonymous",
+			prepare: func(*MockctxLogger) {},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			l := NewMockctxLogger(gomock.NewController(t))
+			tt.prepare(l)
+
+			var called bool
+			h := IdentityMiddleware(func(*http.Request) map[string]any { return tt.identity })(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { called = true }))
+			r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			h.ServeHTTP(httptest.NewRecorder(), r.WithContext(newContext(r.Context(), l)))
+			if !called {
+				t.Error("IdentityMiddleware() did not call the next handler")
+			}
+		})
+	}
+}
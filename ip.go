@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// remoteIP returns the IP address of the client that made the request. Without trusted proxies, the address of
+// the peer is used, since any forwarded address can be set by the client. Otherwise, the right-most address of the
+// forwarded header that is not a trusted proxy is used, since the addresses on its left can be set by the client.
+func remoteIP(r *http.Request, proxies []netip.Prefix) string {
+	peer := hostFromAddr(r.RemoteAddr)
+	if len(proxies) == 0 || !trustedProxy(peer, proxies) {
+		return peer
+	}
+
+	hops := forwardedFor(r.Header)
+	for i := len(hops) - 1; i >= 0; i-- {
+		if !trustedProxy(hops[i], proxies) {
+			return hops[i]
+		}
+	}
+	if len(hops) > 0 {
+		return hops[0]
+	}
+
+	return peer
+}
+
+// forwardedFor returns the addresses of the clients and proxies that forwarded the request, from left to right,
+// read from X-Forwarded-For, which Google Cloud and AWS load balancers append to, or from the "for" parameters
+// of the Forwarded header (RFC 7239) when there is no X-Forwarded-For header, so a Forwarded header sent by the
+// client can not override the addresses appended by the proxies
+func forwardedFor(h http.Header) []string {
+	var hops []string
+	for _, xff := range h.Values("X-Forwarded-For") {
+		for _, ip := range strings.Split(xff, ",") {
+			if ip = strings.TrimSpace(ip); ip != "" {
+				hops = append(hops, ip)
+			}
+		}
+	}
+	if len(hops) > 0 {
+		return hops
+	}
+
+	for _, fwd := range h.Values("Forwarded") {
+		for _, elem := range strings.Split(fwd, ",") {
+			for _, pair := range strings.Split(elem, ";") {
+				if k, v, ok := strings.Cut(strings.TrimSpace(pair), "="); ok && strings.EqualFold(k, "for") {
+					hops = append(hops, forwardedNode(v))
+				}
+			}
+		}
+	}
+
+	return hops
+}
+
+// forwardedNode returns the address of a node of the Forwarded header without quotes, brackets, or port,
+// e.g. "192.0.2.60:4711" or "[2001:db8:cafe::17]:4711"
+func forwardedNode(v string) string {
+	v = strings.Trim(strings.TrimSpace(v), `"`)
+	if strings.HasPrefix(v, "[") {
+		if i := strings.Index(v, "]"); i > 0 {
+			return v[1:i]
+		}
+	}
+
+	return hostFromAddr(v)
+}
+
+// trustedProxy reports whether the address is in one of the trusted proxy prefixes
+func trustedProxy(ip string, proxies []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	return slices.ContainsFunc(proxies, func(p netip.Prefix) bool { return p.Contains(addr) })
+}
+
+// localIP returns the IP address of the server that received the request, if known
+func localIP(r *http.Request) string {
+	addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr)
+	if !ok {
+		return ""
+	}
+
+	return hostFromAddr(addr.String())
+}
+
+// hostFromAddr strips the port from a host:port address
+func hostFromAddr(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	return host
+}
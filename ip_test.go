@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func Test_remoteIP(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		xff        string
+		forwarded  string
+		proxies    []netip.Prefix
+		want       string
+	}{
+		{
+			name:       "remote address",
+			remoteAddr: "192.0.2.1:1234",
+			want:       "192.0.2.1",
+		},
+		{
+			name:       "no trusted proxies",
+			remoteAddr: "10.0.0.1:1234",
+			xff:        "203.0.113.195, 70.41.3.18, 150.172.238.178",
+			want:       "10.0.0.1",
+		},
+		{
+			name:       "single hop",
+			remoteAddr: "10.0.0.1:1234",
+			xff:        "203.0.113.195",
+			proxies:    []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+			want:       "203.0.113.195",
+		},
+		{
+			name:       "trusted proxies",
+			remoteAddr: "10.0.0.1:1234",
+			xff:        "198.51.100.1, 203.0.113.195, 10.0.0.2",
+			proxies:    []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+			want:       "203.0.113.195",
+		},
+		{
+			name:       "untrusted remote address",
+			remoteAddr: "192.0.2.1:1234",
+			xff:        "203.0.113.195",
+			proxies:    []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+			want:       "192.0.2.1",
+		},
+		{
+			name:       "all trusted",
+			remoteAddr: "10.0.0.1:1234",
+			xff:        "10.0.0.3, 10.0.0.2",
+			proxies:    []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+			want:       "10.0.0.3",
+		},
+		{
+			name:       "forwarded does not override x-forwarded-for",
+			remoteAddr: "10.0.0.1:1234",
+			xff:        "198.51.100.1",
+			forwarded:  `for=192.0.2.60;proto=http;by=203.0.113.43, for="[2001:db8:cafe::17]:4711"`,
+			proxies:    []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "forwarded trusted proxies",
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  `for=192.0.2.60, For="[2001:db8:cafe::17]:4711", for=10.0.0.2:8080`,
+			proxies:    []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+			want:       "2001:db8:cafe::17",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.xff != "" {
+				r.Header.Set("X-Forwarded-For", tt.xff)
+			}
+			if tt.forwarded != "" {
+				r.Header.Set("Forwarded", tt.forwarded)
+			}
+			if got := remoteIP(r, tt.proxies); got != tt.want {
+				t.Errorf("remoteIP() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_localIP(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		localAddr net.Addr
+		want      string
+	}{
+		{
+			name:      "local address",
+			localAddr: &net.TCPAddr{IP: net.ParseIP("192.0.2.10"), Port: 8080},
+			want:      "192.0.2.10",
+		},
+		{
+			name: "no local address",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			if tt.localAddr != nil {
+				r = r.WithContext(context.WithValue(r.Context(), http.LocalAddrContextKey, tt.localAddr))
+			}
+			if got := localIP(r); got != tt.want {
+				t.Errorf("localIP() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/errors/v5"
+)
+
+// keys of the attributes of the parent log of a job run
+const (
+	jobNameKey    = "job.name"
+	jobOutcomeKey = "job.outcome"
+	jobErrorKey   = "job.error"
+)
+
+// outcomes of a job run in the job.outcome attribute
+const (
+	jobSuccess = "success"
+	jobFailure = "failure"
+)
+
+// NewJobLogger starts a run of a background job, e.g. a cron job or a batch, logged with the middleware of the
+// exporter like a request. It returns the context of the run, whose logger (see Ctx) writes the child logs, and
+// the function that ends the run with its error and writes the parent log, e.g.
+//
+//	ctx, end := logger.NewJobLogger(ctx, exporter, "nightly-export")
+//	err := export(ctx)
+//	end(err)
+//
+// Each run is a request with the RUN method and the path of the job name, so the options of the exporter apply to
+// runs like to HTTP requests. The parent log has the name of the job, its duration as the latency of the request,
+// and the outcome. Failed runs have the 500 status. Only the first call of end has an effect. When ctx is done
+// before end is called, the run ends with the error of ctx, so the run does not outlive ctx. See RunJob to end
+// the run when the job returns.
+func NewJobLogger(ctx context.Context, e Exporter, jobName string) (context.Context, func(err error)) {
+	run := &jobRun{name: jobName, started: make(chan context.Context), end: make(chan error), done: make(chan struct{})}
+
+	go func() {
+		defer close(run.done)
+		e.Middleware()(http.HandlerFunc(serveJob)).ServeHTTP(&statusResponseWriter{header: make(http.Header)}, jobRequest(context.WithValue(ctx, jobRunKey, run), jobName))
+	}()
+
+	var once sync.Once
+	end := func(err error) {
+		once.Do(func() {
+			if ctx.Err() == nil {
+				select {
+				case run.end <- err:
+				case <-run.done:
+				}
+			}
+			<-run.done
+		})
+	}
+
+	select {
+	case ctx := <-run.started:
+		return ctx, end
+	case <-run.done:
+		// the middleware did not run the job, e.g. it panicked, so the run is not logged
+		return ctx, func(error) {}
+	}
+}
+
+// RunJob runs job as a run of a background job logged with the middleware of the exporter (see NewJobLogger),
+// and returns the error of job. The run ends when job returns, or panics, e.g.
+//
+//	err := logger.RunJob(ctx, exporter, "nightly-export", export)
+func RunJob(ctx context.Context, e Exporter, jobName string, job func(ctx context.Context) error) (err error) {
+	ctx, end := NewJobLogger(ctx, e, jobName)
+	defer func() {
+		if v := recover(); v != nil {
+			end(errors.Newf("panic: %v", v))
+			panic(v)
+		}
+		end(err)
+	}()
+
+	return job(ctx)
+}
+
+// jobRun is a run of a job served by the middleware of an exporter
+type jobRun struct {
+	name    string
+	started chan context.Context
+	end     chan error
+	done    chan struct{}
+}
+
+// serveJob passes the context of the request to the job, and writes the outcome of the run when it ends
+func serveJob(w http.ResponseWriter, r *http.Request) {
+	run, ok := r.Context().Value(jobRunKey).(*jobRun)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	run.started <- r.Context()
+	var err error
+	select {
+	case err = <-run.end:
+	case <-r.Context().Done():
+		err = r.Context().Err()
+	}
+
+	l := Req(r).AddRequestAttribute(jobNameKey, run.name)
+	if err != nil {
+		l.AddRequestAttribute(jobOutcomeKey, jobFailure).AddRequestAttribute(jobErrorKey, err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+	l.AddRequestAttribute(jobOutcomeKey, jobSuccess)
+	w.WriteHeader(http.StatusOK)
+}
+
+// jobRequest returns the request of the job run for the middleware of an exporter
+func jobRequest(ctx context.Context, jobName string) *http.Request {
+	path := "/" + strings.TrimPrefix(jobName, "/")
+
+	return (&http.Request{
+		Method:     "RUN",
+		URL:        &url.URL{Path: path},
+		RequestURI: path,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Body:       http.NoBody,
+	}).WithContext(ctx)
+}
@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestNewJobLogger(t *testing.T) {
+	t.Parallel()
+
+	errJob := errors.New("export failed")
+	tests := []struct {
+		name       string
+		err        error
+		wantParent map[string]any
+	}{
+		{
+			name: "success",
+			wantParent: map[string]any{
+				"level": "INFO", "msg": "RUN /nightly-export", "method": "RUN", "path": "/nightly-export",
+				"status": float64(200), "requestSize": float64(0), "responseSize": float64(0), "logCount": float64(1),
+				jobNameKey: "nightly-export", jobOutcomeKey: jobSuccess,
+			},
+		},
+		{
+			name: "failure",
+			err:  errJob,
+			wantParent: map[string]any{
+				"level": "ERROR", "msg": "RUN /nightly-export", "method": "RUN", "path": "/nightly-export",
+				"status": float64(500), "requestSize": float64(0), "responseSize": float64(0), "logCount": float64(1),
+				jobNameKey: "nightly-export", jobOutcomeKey: jobFailure, jobErrorKey: errJob.Error(),
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			ctx, end := NewJobLogger(context.Background(), NewConsoleExporter().Writer(&buf).Format(JSONFormat), "nightly-export")
+			Ctx(ctx).Info("exporting")
+			end(tt.err)
+			end(nil)
+
+			child, parent := grpcLogs(t, &buf)
+			if child["traceID"] == nil || parent["traceID"] != child["traceID"] {
+				t.Errorf("NewJobLogger() child traceID = %v, parent traceID = %v", child["traceID"], parent["traceID"])
+			}
+			ignore := cmpopts.IgnoreMapEntries(func(k string, _ any) bool {
+				return k == "time" || k == "elapsed" || k == "traceID"
+			})
+			if diff := cmp.Diff(tt.wantParent, parent, ignore); diff != "" {
+				t.Errorf("NewJobLogger() parent mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewJobLogger_canceled(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx, end := NewJobLogger(ctx, NewConsoleExporter().Writer(&buf).Format(JSONFormat), "nightly-export")
+	Ctx(ctx).Info("exporting")
+	cancel()
+	end(nil)
+
+	_, parent := grpcLogs(t, &buf)
+	if parent[jobOutcomeKey] != jobFailure || parent[jobErrorKey] != context.Canceled.Error() {
+		t.Errorf("NewJobLogger() parent = %v, want failure with %v", parent, context.Canceled)
+	}
+}
+
+func TestRunJob(t *testing.T) {
+	t.Parallel()
+
+	errJob := errors.New("export failed")
+	var buf bytes.Buffer
+	err := RunJob(context.Background(), NewConsoleExporter().Writer(&buf).Format(JSONFormat), "nightly-export", func(ctx context.Context) error {
+		Ctx(ctx).Info("exporting")
+
+		return errJob
+	})
+	if !errors.Is(err, errJob) {
+		t.Errorf("RunJob() error = %v, want %v", err, errJob)
+	}
+
+	_, parent := grpcLogs(t, &buf)
+	if parent[jobOutcomeKey] != jobFailure || parent[jobErrorKey] != errJob.Error() {
+		t.Errorf("RunJob() parent = %v, want failure with %v", parent, errJob)
+	}
+}
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"cmp"
+	"strings"
+)
+
+const (
+	k8sPodNameKey   = "k8s.pod.name"
+	k8sNamespaceKey = "k8s.namespace"
+	k8sNodeNameKey  = "k8s.node.name"
+
+	// k8sServiceHostEnv is set in every container running in a Kubernetes cluster
+	k8sServiceHostEnv = "KUBERNETES_SERVICE_HOST"
+
+	// k8sNamespaceFile contains the namespace of the pod when a service account token is mounted
+	k8sNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// k8sAttributes returns the pod name, namespace, and node name of the pod when running in a Kubernetes
+// cluster, read from the environment variables set with the downward API. The pod name defaults to the
+// hostname, and the namespace to the namespace of the service account.
+func k8sAttributes(getenv func(string) string, readFile func(string) ([]byte, error)) map[string]any {
+	if getenv(k8sServiceHostEnv) == "" {
+		return nil
+	}
+
+	namespace := getenv("POD_NAMESPACE")
+	if namespace == "" {
+		if b, err := readFile(k8sNamespaceFile); err == nil {
+			namespace = strings.TrimSpace(string(b))
+		}
+	}
+
+	attrs := make(map[string]any)
+	for k, v := range map[string]string{
+		k8sPodNameKey:   cmp.Or(getenv("POD_NAME"), getenv("HOSTNAME")),
+		k8sNamespaceKey: namespace,
+		k8sNodeNameKey:  getenv("NODE_NAME"),
+	} {
+		if v != "" {
+			attrs[k] = v
+		}
+	}
+
+	return attrs
+}
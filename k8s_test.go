@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_k8sAttributes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		env       map[string]string
+		namespace string
+		want      map[string]any
+	}{
+		{
+			name: "not in cluster",
+			env:  map[string]string{"POD_NAME": "api-7d9f"},
+		},
+		{
+			name: "downward API",
+			env: map[string]string{
+				"KUBERNETES_SERVICE_HOST": "10.0.0.1",
+				"POD_NAME":                "api-7d9f",
+				"POD_NAMESPACE":           "prod",
+				"NODE_NAME":               "node-1",
+				"HOSTNAME":                "host",
+			},
+			namespace: "default",
+			want:      map[string]any{"k8s.pod.name": "api-7d9f", "k8s.namespace": "prod", "k8s.node.name": "node-1"},
+		},
+		{
+			name:      "defaults",
+			env:       map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1", "HOSTNAME": "api-7d9f"},
+			namespace: "default\n",
+			want:      map[string]any{"k8s.pod.name": "api-7d9f", "k8s.namespace": "default"},
+		},
+		{
+			name: "no attributes",
+			env:  map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1"},
+			want: map[string]any{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			getenv := func(key string) string { return tt.env[key] }
+			readFile := func(name string) ([]byte, error) {
+				if name != k8sNamespaceFile || tt.namespace == "" {
+					return nil, os.ErrNotExist
+				}
+
+				return []byte(tt.namespace), nil
+			}
+			if diff := cmp.Diff(tt.want, k8sAttributes(getenv, readFile)); diff != "" {
+				t.Errorf("k8sAttributes() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"log/slog"
+	"maps"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-playground/errors/v5"
+)
+
+// levels is the minimum level of the components set with SetLevel
+var levels levelRegistry
+
+// levelRegistry is a set of component levels that is read without locking, since the
+// map is replaced on every change
+type levelRegistry struct {
+	mu     sync.Mutex
+	levels atomic.Pointer[map[string]slog.Level]
+}
+
+// SetLevel sets the minimum level of the child logs of the component, and its sub components, created with
+// Logger.Named. The empty component sets the level of all loggers. It can be called at any time, and applies
+// to the following logs of all requests, overriding the ComponentLevel and MinLevel options of the exporter.
+//
+// The AWSExporter does not write logs below the level of its Handler.
+func SetLevel(component string, level Level) {
+	levels.set(component, level)
+}
+
+// ResetLevel removes the level of the component set with SetLevel
+func ResetLevel(component string) {
+	levels.reset(component)
+}
+
+// registeredLevel returns the level set with SetLevel for the component, or its closest parent component
+func registeredLevel(component string) (slog.Level, bool) {
+	return levels.level(component)
+}
+
+// parseLevel parses the name of a level, e.g. "DEBUG", "info", "WARN+2", "TRACE" or "CRITICAL"
+func parseLevel(s string) (slog.Level, error) {
+	switch strings.ToUpper(s) {
+	case "TRACE":
+		return LevelTrace, nil
+	case "CRITICAL":
+		return LevelCritical, nil
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, errors.Wrap(err, "slog.Level.UnmarshalText()")
+	}
+
+	return level, nil
+}
+
+func (r *levelRegistry) set(component string, level slog.Level) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m := r.all()
+	m[component] = level
+	r.levels.Store(&m)
+}
+
+func (r *levelRegistry) reset(component string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m := r.all()
+	delete(m, component)
+	r.levels.Store(&m)
+}
+
+// level returns the level of the component, or its closest parent component
+func (r *levelRegistry) level(component string) (slog.Level, bool) {
+	m := r.levels.Load()
+	if m == nil {
+		return 0, false
+	}
+
+	return componentLevel(*m, component)
+}
+
+// all returns a copy of the levels
+func (r *levelRegistry) all() map[string]slog.Level {
+	m := r.levels.Load()
+	if m == nil {
+		return make(map[string]slog.Level)
+	}
+
+	return maps.Clone(*m)
+}
+
+// componentKey is the attribute of the component name of the logs written by a Named Logger
+const componentKey = "component"
+
+// componentName returns the name of the component named name within the parent component
+func componentName(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+
+	return parent + "." + name
+}
+
+// componentLevel returns the minimum level configured for the component, or for its closest
+// parent component (e.g. "db" for "db.pool", and "" for all components), and false if no level is configured
+func componentLevel(levels map[string]slog.Level, component string) (slog.Level, bool) {
+	for {
+		if level, ok := levels[component]; ok {
+			return level, true
+		}
+		if component == "" {
+			return 0, false
+		}
+		i := max(strings.LastIndex(component, "."), 0)
+		component = component[:i]
+	}
+}
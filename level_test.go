@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"cloud.google.com/go/logging"
+)
+
+func TestSetLevel(t *testing.T) {
+	t.Parallel()
+
+	// component names are unique to this test since the levels are global
+	if _, ok := registeredLevel("TestSetLevel"); ok {
+		t.Fatal("registeredLevel() ok = true before SetLevel")
+	}
+
+	SetLevel("TestSetLevel", LevelTrace)
+	SetLevel("TestSetLevel.sub", slog.LevelWarn)
+	t.Cleanup(func() {
+		ResetLevel("TestSetLevel")
+		ResetLevel("TestSetLevel.sub")
+	})
+
+	tests := []struct {
+		component string
+		want      slog.Level
+		wantOK    bool
+	}{
+		{component: "TestSetLevel", want: LevelTrace, wantOK: true},
+		{component: "TestSetLevel.sub", want: slog.LevelWarn, wantOK: true},
+		{component: "TestSetLevel.other", want: LevelTrace, wantOK: true},
+		{component: "", wantOK: false},
+	}
+	for _, tt := range tests {
+		if got, ok := registeredLevel(tt.component); got != tt.want || ok != tt.wantOK {
+			t.Errorf("registeredLevel(%q) = %v, %v, want %v, %v", tt.component, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	ResetLevel("TestSetLevel.sub")
+	if got, _ := registeredLevel("TestSetLevel.sub"); got != LevelTrace {
+		t.Errorf("registeredLevel() after ResetLevel = %v, want parent level %v", got, LevelTrace)
+	}
+}
+
+func TestSetLevel_logger(t *testing.T) {
+	t.Parallel()
+
+	c := &captureLogger{}
+	l := newGCPLogger(c, "projects/my-project/traces/123", "123")
+	l.minSeverity = logging.Info
+	db := l.Named("TestSetLevel_logger")
+
+	if db.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Enabled(Debug) = true before SetLevel, want false")
+	}
+
+	SetLevel("TestSetLevel_logger", slog.LevelDebug)
+	t.Cleanup(func() { ResetLevel("TestSetLevel_logger") })
+
+	if !db.Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("Enabled(Debug) = false after SetLevel, want true")
+	}
+	db.Debug(context.Background(), "debug")
+	if c.e.Severity != logging.Debug {
+		t.Errorf("Debug() severity = %v, want %v", c.e.Severity, logging.Debug)
+	}
+}
+
+func Test_componentLevel(t *testing.T) {
+	t.Parallel()
+
+	levels := map[string]slog.Level{"db": slog.LevelDebug, "db.pool": LevelTrace}
+	tests := []struct {
+		name      string
+		component string
+		want      slog.Level
+		wantOK    bool
+	}{
+		{name: "component", component: "db", want: slog.LevelDebug, wantOK: true},
+		{name: "sub component", component: "db.pool", want: LevelTrace, wantOK: true},
+		{name: "parent component", component: "db.query", want: slog.LevelDebug, wantOK: true},
+		{name: "not configured", component: "http", wantOK: false},
+		{name: "prefix is not a parent", component: "dbx", wantOK: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := componentLevel(levels, tt.component)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("componentLevel() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
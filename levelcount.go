@@ -0,0 +1,61 @@
+package logger
+
+import "log/slog"
+
+// keys of the attributes of the parent log with the number of child logs by level
+const (
+	debugCountKey = "debug_count"
+	infoCountKey  = "info_count"
+	warnCountKey  = "warn_count"
+	errorCountKey = "error_count"
+)
+
+// levelCounter counts the child logs of a request by level for the parent log. It is locked by the mutex of
+// the root logger.
+type levelCounter struct {
+	debug int // Trace and Debug logs
+	info  int
+	warn  int
+	error int // Error and Critical logs
+}
+
+// newLevelCounter returns a levelCounter, or nil when disabled
+func newLevelCounter(enabled bool) *levelCounter {
+	if !enabled {
+		return nil
+	}
+
+	return &levelCounter{}
+}
+
+// add counts a child log at the level. A nil levelCounter is a no-op.
+func (c *levelCounter) add(level slog.Level) {
+	if c == nil {
+		return
+	}
+
+	switch {
+	case level >= slog.LevelError:
+		c.error++
+	case level >= slog.LevelWarn:
+		c.warn++
+	case level >= slog.LevelInfo:
+		c.info++
+	default:
+		c.debug++
+	}
+}
+
+// attrs returns the attributes of the parent log with the number of child logs by level, or nil when disabled
+func (c *levelCounter) attrs() []slog.Attr {
+	if c == nil {
+		return nil
+	}
+
+	return []slog.Attr{
+		slog.Int(debugCountKey, c.debug),
+		slog.Int(infoCountKey, c.info),
+		slog.Int(warnCountKey, c.warn),
+		slog.Int(errorCountKey, c.error),
+	}
+}
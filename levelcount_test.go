@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_levelCounter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		enabled bool
+		levels  []slog.Level
+		want    map[string]int64
+	}{
+		{
+			name:   "disabled",
+			levels: []slog.Level{LevelInfo},
+		},
+		{
+			name:    "no logs",
+			enabled: true,
+			want:    map[string]int64{debugCountKey: 0, infoCountKey: 0, warnCountKey: 0, errorCountKey: 0},
+		},
+		{
+			name:    "levels",
+			enabled: true,
+			levels:  []slog.Level{LevelTrace, LevelDebug, LevelInfo, LevelInfo, LevelWarn, LevelError, LevelCritical},
+			want:    map[string]int64{debugCountKey: 2, infoCountKey: 2, warnCountKey: 1, errorCountKey: 2},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := newLevelCounter(tt.enabled)
+			for _, level := range tt.levels {
+				c.add(level)
+			}
+			var got map[string]int64
+			for _, a := range c.attrs() {
+				if got == nil {
+					got = make(map[string]int64)
+				}
+				got[a.Key] = a.Value.Int64()
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("levelCounter.attrs() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -13,7 +13,14 @@ package logger
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-playground/errors/v5"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -21,6 +28,52 @@ const (
 	customPrefix   = "custom_"
 )
 
+// keys of the attributes added by WithError
+const (
+	errorMessageKey = "error.message"
+	errorTypeKey    = "error.type"
+	errorChainKey   = "error.chain"
+)
+
+// Level is the severity of a log, it is the same type as slog.Level
+type Level = slog.Level
+
+const (
+	// LevelTrace is the level of Trace logs, which is below slog.LevelDebug
+	LevelTrace = slog.LevelDebug - 4
+
+	// LevelDebug is the level of Debug logs
+	LevelDebug = slog.LevelDebug
+
+	// LevelInfo is the level of Info logs
+	LevelInfo = slog.LevelInfo
+
+	// LevelWarn is the level of Warn logs
+	LevelWarn = slog.LevelWarn
+
+	// LevelError is the level of Error logs
+	LevelError = slog.LevelError
+
+	// LevelCritical is the level of Fatal and Panic logs, which is above slog.LevelError
+	LevelCritical = slog.LevelError + 4
+)
+
+// exit is called by Fatal, it exists for testability
+var exit = os.Exit
+
+// levelName returns the name of the level, naming LevelTrace and LevelCritical
+// which slog names relative to the built-in levels (e.g. "DEBUG-4")
+func levelName(level slog.Level) string {
+	switch level {
+	case LevelTrace:
+		return "TRACE"
+	case LevelCritical:
+		return "CRITICAL"
+	default:
+		return level.String()
+	}
+}
+
 // Logger implements logging methods for this package
 type Logger struct {
 	ctx context.Context
@@ -28,7 +81,9 @@ type Logger struct {
 }
 
 // Ctx returns the logger from the context. If
-// no logger is found, it will write to stderr
+// no logger is found, it will write to the fallback (see SetFallback and SetFallbackMode).
+// Each fallback logger has its own request attributes, so attributes added with AddRequestAttribute
+// are only written by that Logger, use NewCtx(ctx, Ctx(ctx)) to share them with the following Ctx calls.
 func Ctx(ctx context.Context) *Logger {
 	return &Logger{
 		ctx: ctx,
@@ -36,13 +91,27 @@ func Ctx(ctx context.Context) *Logger {
 	}
 }
 
+// adapterCtx returns the logger from the context like Ctx, but writes to the fallback without the checks of
+// SetFallbackMode when no logger is found, since the adapters of this package also run outside of requests
+func adapterCtx(ctx context.Context) *Logger {
+	lg, ok := lookupCtx(ctx)
+	if !ok {
+		lg = newStdErrLogger()
+	}
+
+	return &Logger{
+		ctx: ctx,
+		lg:  lg,
+	}
+}
+
 // NewCtx associates the logger with the context and returns the resulting context
 func NewCtx(ctx context.Context, l *Logger) context.Context {
 	return newContext(ctx, l.lg)
 }
 
 // Req returns the logger from the http request. If
-// no logger is found, it will write to stderr
+// no logger is found, it will write to the fallback (see SetFallback, SetFallbackMode, and Ctx)
 func Req(r *http.Request) *Logger {
 	return &Logger{
 		ctx: r.Context(),
@@ -55,6 +124,58 @@ func (l *Logger) TraceID() string {
 	return l.lg.TraceID()
 }
 
+// SpanID returns the ID of the span in the context of the logger, e.g. to propagate it with the trace ID in
+// outbound requests, or an empty string when the context has no span
+func (l *Logger) SpanID() string {
+	if l.ctx == nil {
+		return ""
+	}
+	sc := trace.SpanContextFromContext(l.ctx)
+	if !sc.HasSpanID() {
+		return ""
+	}
+
+	return sc.SpanID().String()
+}
+
+// RequestID returns the ID of the request, propagated from the request header or generated by the
+// middleware when the RequestID option of the exporter is set, or an empty string otherwise
+func (l *Logger) RequestID() string {
+	return requestIDFromCtx(l.ctx)
+}
+
+// CorrelationID returns the correlation ID of the request, propagated from the request header when the
+// CorrelationID option of the exporter is set, or an empty string otherwise
+func (l *Logger) CorrelationID() string {
+	c, _ := correlationFromCtx(l.ctx)
+
+	return c.id
+}
+
+// RawTraceID returns the trace ID of the request logs without any exporter
+// specific formatting (e.g. "projects/{projectID}/traces/" for Google Cloud)
+func (l *Logger) RawTraceID() string {
+	return l.lg.RawTraceID()
+}
+
+// Enabled reports whether logs at the level are written, so that expensive
+// log arguments can be skipped when the level is filtered
+func (l *Logger) Enabled(level Level) bool {
+	return l.lg.Enabled(l.ctx, level)
+}
+
+// Trace logs a trace message. Trace logs are dropped unless the minimum level
+// of the exporter is lowered to LevelTrace.
+func (l *Logger) Trace(v any) {
+	l.lg.Trace(l.ctx, v)
+}
+
+// Tracef logs a trace message with format. Trace logs are dropped unless the
+// minimum level of the exporter is lowered to LevelTrace.
+func (l *Logger) Tracef(format string, v ...any) {
+	l.lg.Tracef(l.ctx, format, v...)
+}
+
 // Debug logs a debug message.
 func (l *Logger) Debug(v any) {
 	l.lg.Debug(l.ctx, v)
@@ -95,6 +216,110 @@ func (l *Logger) Errorf(format string, v ...any) {
 	l.lg.Errorf(l.ctx, format, v...)
 }
 
+// TraceFn logs a trace message returned by fn, which is only called when the level is enabled.
+func (l *Logger) TraceFn(fn func() string) {
+	if l.Enabled(LevelTrace) {
+		l.lg.Trace(l.ctx, fn())
+	}
+}
+
+// DebugFn logs a debug message returned by fn, which is only called when the level is enabled.
+func (l *Logger) DebugFn(fn func() string) {
+	if l.Enabled(LevelDebug) {
+		l.lg.Debug(l.ctx, fn())
+	}
+}
+
+// InfoFn logs a info message returned by fn, which is only called when the level is enabled.
+func (l *Logger) InfoFn(fn func() string) {
+	if l.Enabled(LevelInfo) {
+		l.lg.Info(l.ctx, fn())
+	}
+}
+
+// WarnFn logs a warning message returned by fn, which is only called when the level is enabled.
+func (l *Logger) WarnFn(fn func() string) {
+	if l.Enabled(LevelWarn) {
+		l.lg.Warn(l.ctx, fn())
+	}
+}
+
+// ErrorFn logs an error message returned by fn, which is only called when the level is enabled.
+func (l *Logger) ErrorFn(fn func() string) {
+	if l.Enabled(LevelError) {
+		l.lg.Error(l.ctx, fn())
+	}
+}
+
+// Log logs a message at the level, which is rounded down to the nearest level
+// of the Logger methods (e.g. slog.LevelInfo+2 logs as Info).
+// A level of LevelCritical or above is logged without exiting or panicking.
+func (l *Logger) Log(level Level, v any) {
+	switch {
+	case level >= LevelCritical:
+		l.lg.Critical(l.ctx, v)
+	case level >= LevelError:
+		l.lg.Error(l.ctx, v)
+	case level >= LevelWarn:
+		l.lg.Warn(l.ctx, v)
+	case level >= LevelInfo:
+		l.lg.Info(l.ctx, v)
+	case level >= LevelDebug:
+		l.lg.Debug(l.ctx, v)
+	default:
+		l.lg.Trace(l.ctx, v)
+	}
+}
+
+// Logf logs a message with format at the level, see Log.
+func (l *Logger) Logf(level Level, format string, v ...any) {
+	switch {
+	case level >= LevelCritical:
+		l.lg.Criticalf(l.ctx, format, v...)
+	case level >= LevelError:
+		l.lg.Errorf(l.ctx, format, v...)
+	case level >= LevelWarn:
+		l.lg.Warnf(l.ctx, format, v...)
+	case level >= LevelInfo:
+		l.lg.Infof(l.ctx, format, v...)
+	case level >= LevelDebug:
+		l.lg.Debugf(l.ctx, format, v...)
+	default:
+		l.lg.Tracef(l.ctx, format, v...)
+	}
+}
+
+// Fatal logs a critical message, flushes the logs, and exits the program with status 1.
+// Deferred functions and the parent request log are not run.
+func (l *Logger) Fatal(v any) {
+	l.lg.Critical(l.ctx, v)
+	l.lg.Flush()
+	exit(1)
+}
+
+// Fatalf logs a critical message with format, flushes the logs, and exits the program with status 1.
+// Deferred functions and the parent request log are not run.
+func (l *Logger) Fatalf(format string, v ...any) {
+	l.lg.Criticalf(l.ctx, format, v...)
+	l.lg.Flush()
+	exit(1)
+}
+
+// Panic logs a critical message, flushes the logs, and panics with v.
+func (l *Logger) Panic(v any) {
+	l.lg.Critical(l.ctx, v)
+	l.lg.Flush()
+	panic(v)
+}
+
+// Panicf logs a critical message with format, flushes the logs, and panics with the message.
+func (l *Logger) Panicf(format string, v ...any) {
+	msg := fmt.Sprintf(format, v...)
+	l.lg.Critical(l.ctx, msg)
+	l.lg.Flush()
+	panic(msg)
+}
+
 // AddRequestAttribute adds an attribute (kv) for the parent request log and returns a reference to the original logger for method chaining purposes
 // If the key matches a reserved key, it will be prefixed with "custom_"
 // If the key already exists, its value is overwritten
@@ -104,6 +329,27 @@ func (l *Logger) AddRequestAttribute(key string, value any) *Logger {
 	return l
 }
 
+// SetRoute records the route template that matched the request (e.g. "/users/{id}") in the http.route
+// attribute of the parent request log, so logs aggregate by endpoint instead of the concrete path.
+// See RouteMiddleware and ServeMuxRoute to set it from a router.
+func (l *Logger) SetRoute(route string) *Logger {
+	if route != "" {
+		l.lg.AddRequestAttribute(httpRouteKey, route)
+	}
+
+	return l
+}
+
+// Named returns a child Logger for the component (e.g. "db"), which tags its logs with the "component"
+// attribute. Named Loggers nest, e.g. Named("db").Named("pool") is the "db.pool" component.
+// The minimum level of a component can be set with the ComponentLevel option of the exporter.
+func (l *Logger) Named(name string) *Logger {
+	return &Logger{
+		ctx: l.ctx,
+		lg:  l.lg.Named(name),
+	}
+}
+
 // WithAttributes returns an AttributerLogger that can be used to add child (trace) log attributes
 func (l *Logger) WithAttributes() *AttributerLogger {
 	return &AttributerLogger{
@@ -112,6 +358,104 @@ func (l *Logger) WithAttributes() *AttributerLogger {
 	}
 }
 
+// With returns a child Logger with the attribute (kv) embedded for its child (trace) logs.
+// It is a shortcut for WithAttributes().AddAttribute(key, value).Logger()
+func (l *Logger) With(key string, value any) *Logger {
+	return l.WithAttributes().AddAttribute(key, value).Logger()
+}
+
+// Audit writes the audit event (e.g. "user.deleted") with the attributes to the audit destination of the
+// exporter, separate from the request logs. Audit events are never sampled, filtered by level, or deduplicated,
+// and are numbered with tamper-evident sequence numbers (see SetHashKey). Without an exporter, e.g. outside of
+// a request, the event is written as an Info log.
+func (l *Logger) Audit(event string, attrs ...slog.Attr) {
+	if c := auditFromCtx(l.ctx); c != nil {
+		c.audit(l.ctx, l.lg.TraceID(), event, attrs)
+
+		return
+	}
+
+	a := l.lg.WithAttributes()
+	a.AddAttribute(auditEventKey, event)
+	for _, attr := range attrs {
+		a.AddAttribute(attr.Key, attr.Value.Resolve().Any())
+	}
+	a.Logger().Info(l.ctx, event)
+}
+
+// WithError returns a child Logger with the error embedded as attributes for its child (trace) logs:
+// "error.message" and "error.type", plus "error.chain" with the stack frames when err is a
+// github.com/go-playground/errors chain. If err is nil, l is returned.
+func (l *Logger) WithError(err error) *Logger {
+	if err == nil {
+		return l
+	}
+
+	a := l.WithAttributes()
+	var chain errors.Chain
+	if errors.As(err, &chain) && len(chain) > 0 {
+		a.AddAttribute(errorMessageKey, chainMessage(chain))
+		a.AddAttribute(errorTypeKey, fmt.Sprintf("%T", chain[0].Err))
+		a.AddAttribute(errorChainKey, chainStack(chain))
+	} else {
+		a.AddAttribute(errorMessageKey, err.Error())
+		a.AddAttribute(errorTypeKey, fmt.Sprintf("%T", err))
+	}
+
+	return a.Logger()
+}
+
+// chainMessage returns the error message of the chain without the source information,
+// with the outermost prefix first (e.g. "prefix 2: prefix 1: original error")
+func chainMessage(chain errors.Chain) string {
+	parts := make([]string, 0, len(chain))
+	for i := len(chain) - 1; i >= 0; i-- {
+		if chain[i].Prefix != "" {
+			parts = append(parts, chain[i].Prefix)
+		}
+	}
+	if err := chain[0].Err; err != nil {
+		parts = append(parts, err.Error())
+	}
+
+	return strings.Join(parts, ": ")
+}
+
+// chainStack returns the stack frames of the chain as structured payload, with the
+// innermost (original) error first
+func chainStack(chain errors.Chain) []map[string]any {
+	stack := make([]map[string]any, 0, len(chain))
+	for _, link := range chain {
+		frame := map[string]any{
+			"function": link.Source.Frame.Function,
+			"file":     link.Source.File(),
+			"line":     link.Source.Line(),
+		}
+		if link.Prefix != "" {
+			frame["prefix"] = link.Prefix
+		}
+		if len(link.Types) > 0 {
+			frame["types"] = link.Types
+		}
+		if len(link.Tags) > 0 {
+			tags := make(map[string]any, len(link.Tags))
+			for _, tag := range link.Tags {
+				tags[tag.Key] = tag.Value
+			}
+			frame["tags"] = tags
+		}
+		stack = append(stack, frame)
+	}
+
+	return stack
+}
+
+// ContextWith returns a copy of ctx with a child of the context's logger that has the
+// attribute (kv) embedded, so that downstream calls to Ctx include the attribute
+func ContextWith(ctx context.Context, key string, value any) context.Context {
+	return NewCtx(ctx, Ctx(ctx).With(key, value))
+}
+
 type AttributerLogger struct {
 	logger     *Logger
 	attributer attributer
@@ -120,12 +464,24 @@ type AttributerLogger struct {
 // AddAttribute adds an attribute (kv) for the child (trace) log and returns a reference to the original AttributerLogger for method chaining purposes
 // If the key matches a reserved key, it will be prefixed with "custom_"
 // If the key already exists, its value is overwritten
+// The value may be a slog.Value or slog.LogValuer, which are resolved by the exporter
 func (a *AttributerLogger) AddAttribute(key string, value any) *AttributerLogger {
 	a.attributer.AddAttribute(key, value)
 
 	return a
 }
 
+// AddAttrs adds slog attributes for the child (trace) log and returns a reference to the original AttributerLogger for method chaining purposes
+// Groups and slog.LogValuer values are passed natively to slog based exporters, and are nested JSON objects on Google Cloud
+// The same key rules as AddAttribute apply to the key of each attribute
+func (a *AttributerLogger) AddAttrs(attrs ...slog.Attr) *AttributerLogger {
+	for _, attr := range attrs {
+		a.attributer.AddAttribute(attr.Key, attr.Value)
+	}
+
+	return a
+}
+
 // Logger returns a Logger with the child (trace) attributes embedded
 func (a *AttributerLogger) Logger() *Logger {
 	return &Logger{
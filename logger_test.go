@@ -5,11 +5,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"reflect"
 	"testing"
 
+	goerrors "github.com/go-playground/errors/v5"
 	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/mock/gomock"
 )
 
@@ -23,6 +26,8 @@ func TestLogger(t *testing.T) {
 	tests := []struct {
 		name       string
 		args       args
+		wantTrace  string
+		wantTracef string
 		wantDebug  string
 		wantDebugf string
 		wantInfo   string
@@ -38,6 +43,8 @@ func TestLogger(t *testing.T) {
 				v:  []any{"Message"},
 				v2: "Message",
 			},
+			wantTrace:  "Trace: Message, testCtxValue",
+			wantTracef: "Tracef: Formatted Message, testCtxValue",
 			wantDebug:  "Debug: Message, testCtxValue",
 			wantDebugf: "Debugf: Formatted Message, testCtxValue",
 			wantInfo:   "Info: Message, testCtxValue",
@@ -53,6 +60,8 @@ func TestLogger(t *testing.T) {
 				v:  []any{"Message"},
 				v2: errors.New("Message"),
 			},
+			wantTrace:  "Trace: Message, testCtxValue",
+			wantTracef: "Tracef: Formatted Message, testCtxValue",
 			wantDebug:  "Debug: Message, testCtxValue",
 			wantDebugf: "Debugf: Formatted Message, testCtxValue",
 			wantInfo:   "Info: Message, testCtxValue",
@@ -78,6 +87,18 @@ func TestLogger(t *testing.T) {
 			for _, l := range []*Logger{Ctx(ctx), Req(r)} {
 				format := "Formatted %s"
 
+				l.Trace(tt.args.v2)
+				if s := buf.String(); s != tt.wantTrace {
+					t.Errorf("Logger.Trace() = %q, wantValue %q", s, tt.wantTrace)
+				}
+				buf.Reset()
+
+				l.Tracef(format, tt.args.v...)
+				if s := buf.String(); s != tt.wantTracef {
+					t.Errorf("Logger.Tracef() = %q, wantValue %q", s, tt.wantTracef)
+				}
+				buf.Reset()
+
 				l.Debug(tt.args.v2)
 				if s := buf.String(); s != tt.wantDebug {
 					t.Errorf("Logger.Debug() = %q, wantValue %q", s, tt.wantDebug)
@@ -167,6 +188,44 @@ func TestLogger_AddRequestAttribute(t *testing.T) {
 	}
 }
 
+func TestLogger_SpanID(t *testing.T) {
+	t.Parallel()
+
+	spanID := trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{
+			name: "span",
+			ctx: trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+				SpanID:  spanID,
+			})),
+			want: "00f067aa0ba902b7",
+		},
+		{
+			name: "no span",
+			ctx:  context.Background(),
+		},
+		{
+			name: "nil context",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := &Logger{ctx: tt.ctx, lg: NewMockctxLogger(gomock.NewController(t))}
+			if got := l.SpanID(); got != tt.want {
+				t.Errorf("Logger.SpanID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestLogger_WithAttributes(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -209,6 +268,100 @@ func TestLogger_WithAttributes(t *testing.T) {
 	}
 }
 
+func TestLogger_With(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	child := NewMockctxLogger(ctrl)
+	attr := NewMockattributer(ctrl)
+	lg := NewMockctxLogger(ctrl)
+	lg.EXPECT().WithAttributes().Return(attr).Times(1)
+	attr.EXPECT().AddAttribute("key", "value").Times(1)
+	attr.EXPECT().Logger().Return(child).Times(1)
+
+	l := &Logger{ctx: context.Background(), lg: lg}
+	got := l.With("key", "value")
+	if got.lg != child {
+		t.Errorf("Logger.With().lg = %v, want %v", got.lg, child)
+	}
+	if got.ctx != l.ctx {
+		t.Error("Logger.With().ctx NOT original logger's ctx")
+	}
+}
+
+func TestLogger_WithError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		err     error
+		prepare func(a *Mockattributer)
+	}{
+		{
+			name: "standard error",
+			err:  errors.New("some error"),
+			prepare: func(a *Mockattributer) {
+				a.EXPECT().AddAttribute("error.message", "some error").Times(1)
+				a.EXPECT().AddAttribute("error.type", "*errors.errorString").Times(1)
+			},
+		},
+		{
+			name: "error chain",
+			err:  goerrors.Wrap(errors.New("some error"), "outer"),
+			prepare: func(a *Mockattributer) {
+				a.EXPECT().AddAttribute("error.message", "outer: some error").Times(1)
+				a.EXPECT().AddAttribute("error.type", "*errors.errorString").Times(1)
+				a.EXPECT().AddAttribute("error.chain", gomock.Len(2)).Times(1)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			child := NewMockctxLogger(ctrl)
+			attr := NewMockattributer(ctrl)
+			lg := NewMockctxLogger(ctrl)
+			lg.EXPECT().WithAttributes().Return(attr).Times(1)
+			tt.prepare(attr)
+			attr.EXPECT().Logger().Return(child).Times(1)
+
+			l := &Logger{ctx: context.Background(), lg: lg}
+			if got := l.WithError(tt.err); got.lg != child {
+				t.Errorf("Logger.WithError().lg = %v, want %v", got.lg, child)
+			}
+		})
+	}
+}
+
+func TestLogger_WithError_nil(t *testing.T) {
+	t.Parallel()
+
+	l := &Logger{ctx: context.Background(), lg: NewMockctxLogger(gomock.NewController(t))}
+	if got := l.WithError(nil); got != l {
+		t.Error("Logger.WithError(nil) did not return the original Logger")
+	}
+}
+
+func TestContextWith(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	child := NewMockctxLogger(ctrl)
+	attr := NewMockattributer(ctrl)
+	lg := NewMockctxLogger(ctrl)
+	lg.EXPECT().WithAttributes().Return(attr).Times(1)
+	attr.EXPECT().AddAttribute("key", "value").Times(1)
+	attr.EXPECT().Logger().Return(child).Times(1)
+
+	ctx := ContextWith(newContext(context.Background(), lg), "key", "value")
+	if got := fromCtx(ctx); got != child {
+		t.Errorf("ContextWith() logger = %v, want %v", got, child)
+	}
+}
+
 func TestAttributerLogger_AddAttribute(t *testing.T) {
 	t.Parallel()
 	type args struct {
@@ -245,6 +398,43 @@ func TestAttributerLogger_AddAttribute(t *testing.T) {
 	}
 }
 
+func TestAttributerLogger_AddAttrs(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		attrs   []slog.Attr
+		prepare func(a *Mockattributer)
+	}{
+		{
+			name:  "success adding attrs",
+			attrs: []slog.Attr{slog.String("new_key", "new_value"), slog.Group("group", slog.Int("int", 1))},
+			prepare: func(a *Mockattributer) {
+				a.EXPECT().AddAttribute("new_key", slog.StringValue("new_value")).Times(1)
+				a.EXPECT().AddAttribute("group", slog.GroupValue(slog.Int("int", 1))).Times(1)
+			},
+		},
+		{
+			name:    "no attrs",
+			prepare: func(_ *Mockattributer) {},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mockAttributer := NewMockattributer(gomock.NewController(t))
+			tt.prepare(mockAttributer)
+			a := &AttributerLogger{
+				logger:     &Logger{},
+				attributer: mockAttributer,
+			}
+			if got := a.AddAttrs(tt.attrs...); got != a {
+				t.Error("AttributerLogger.AddAttrs() did not return reference to original AttributerLogger (self)")
+			}
+		})
+	}
+}
+
 func TestAttributerLogger_Logger(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -292,12 +482,194 @@ func TestAttributerLogger_Logger(t *testing.T) {
 	}
 }
 
+func TestLogger_Log(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		level    Level
+		wantLog  string
+		wantLogf string
+	}{
+		{name: "trace", level: LevelTrace, wantLog: "Trace: Message, testCtxValue", wantLogf: "Tracef: Formatted Message, testCtxValue"},
+		{name: "debug", level: LevelDebug, wantLog: "Debug: Message, testCtxValue", wantLogf: "Debugf: Formatted Message, testCtxValue"},
+		{name: "info", level: LevelInfo, wantLog: "Info: Message, testCtxValue", wantLogf: "Infof: Formatted Message, testCtxValue"},
+		{name: "between info and warn", level: LevelInfo + 2, wantLog: "Info: Message, testCtxValue", wantLogf: "Infof: Formatted Message, testCtxValue"},
+		{name: "warn", level: LevelWarn, wantLog: "Warn: Message, testCtxValue", wantLogf: "Warnf: Formatted Message, testCtxValue"},
+		{name: "error", level: LevelError, wantLog: "Error: Message, testCtxValue", wantLogf: "Errorf: Formatted Message, testCtxValue"},
+		{name: "critical", level: LevelCritical, wantLog: "Critical: Message, testCtxValue", wantLogf: "Criticalf: Formatted Message, testCtxValue"},
+		{name: "below trace", level: LevelTrace - 4, wantLog: "Trace: Message, testCtxValue", wantLogf: "Tracef: Formatted Message, testCtxValue"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			ctxLgr := &testCtxLogger{buf: &buf}
+			l := Ctx(newContext(context.WithValue(context.Background(), ctxLgr, " testCtxValue"), ctxLgr))
+
+			l.Log(tt.level, "Message")
+			if s := buf.String(); s != tt.wantLog {
+				t.Errorf("Logger.Log() = %q, want %q", s, tt.wantLog)
+			}
+			buf.Reset()
+
+			l.Logf(tt.level, "Formatted %s", "Message")
+			if s := buf.String(); s != tt.wantLogf {
+				t.Errorf("Logger.Logf() = %q, want %q", s, tt.wantLogf)
+			}
+		})
+	}
+}
+
+func TestLogger_Fn(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		log        func(l *Logger, fn func() string)
+		wantCalled bool
+		want       string
+	}{
+		{name: "TraceFn disabled", log: (*Logger).TraceFn, wantCalled: false, want: ""},
+		{name: "DebugFn", log: (*Logger).DebugFn, wantCalled: true, want: "Debug: Message, testCtxValue"},
+		{name: "InfoFn", log: (*Logger).InfoFn, wantCalled: true, want: "Info: Message, testCtxValue"},
+		{name: "WarnFn", log: (*Logger).WarnFn, wantCalled: true, want: "Warn: Message, testCtxValue"},
+		{name: "ErrorFn", log: (*Logger).ErrorFn, wantCalled: true, want: "Error: Message, testCtxValue"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			ctxLgr := &testCtxLogger{buf: &buf}
+			l := Ctx(newContext(context.WithValue(context.Background(), ctxLgr, " testCtxValue"), ctxLgr))
+
+			var called bool
+			tt.log(l, func() string {
+				called = true
+
+				return "Message"
+			})
+			if called != tt.wantCalled {
+				t.Errorf("fn called = %v, want %v", called, tt.wantCalled)
+			}
+			if s := buf.String(); s != tt.want {
+				t.Errorf("Logger.%s() = %q, want %q", tt.name, s, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogger_Enabled(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	lg := NewMockctxLogger(ctrl)
+	ctx := context.Background()
+	lg.EXPECT().Enabled(ctx, LevelInfo).Return(true).Times(1)
+	lg.EXPECT().Enabled(ctx, LevelTrace).Return(false).Times(1)
+
+	l := &Logger{ctx: ctx, lg: lg}
+	if !l.Enabled(LevelInfo) {
+		t.Error("Logger.Enabled(LevelInfo) = false, want true")
+	}
+	if l.Enabled(LevelTrace) {
+		t.Error("Logger.Enabled(LevelTrace) = true, want false")
+	}
+}
+
+// TestLogger_Fatal is not parallel since it replaces the package level exit func
+func TestLogger_Fatal(t *testing.T) {
+	var code int
+	origExit := exit
+	exit = func(c int) { code = c }
+	t.Cleanup(func() { exit = origExit })
+
+	var buf bytes.Buffer
+	ctxLgr := &testCtxLogger{buf: &buf}
+	ctx := newContext(context.WithValue(context.Background(), ctxLgr, " testCtxValue"), ctxLgr)
+
+	Ctx(ctx).Fatal("Message")
+	if want := "Critical: Message, testCtxValue, Flushed"; buf.String() != want {
+		t.Errorf("Logger.Fatal() = %q, want %q", buf.String(), want)
+	}
+	if code != 1 {
+		t.Errorf("Logger.Fatal() exit code = %d, want 1", code)
+	}
+	buf.Reset()
+	code = 0
+
+	Ctx(ctx).Fatalf("Formatted %s", "Message")
+	if want := "Criticalf: Formatted Message, testCtxValue, Flushed"; buf.String() != want {
+		t.Errorf("Logger.Fatalf() = %q, want %q", buf.String(), want)
+	}
+	if code != 1 {
+		t.Errorf("Logger.Fatalf() exit code = %d, want 1", code)
+	}
+}
+
+func TestLogger_Panic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		call      func(l *Logger)
+		wantLog   string
+		wantPanic any
+	}{
+		{
+			name:      "Panic",
+			call:      func(l *Logger) { l.Panic("Message") },
+			wantLog:   "Critical: Message, testCtxValue, Flushed",
+			wantPanic: "Message",
+		},
+		{
+			name:      "Panicf",
+			call:      func(l *Logger) { l.Panicf("Formatted %s", "Message") },
+			wantLog:   "Critical: Formatted Message, testCtxValue, Flushed",
+			wantPanic: "Formatted Message",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			ctxLgr := &testCtxLogger{buf: &buf}
+			ctx := newContext(context.WithValue(context.Background(), ctxLgr, " testCtxValue"), ctxLgr)
+
+			defer func() {
+				if got := recover(); got != tt.wantPanic {
+					t.Errorf("Logger.%s() panic = %v, want %v", tt.name, got, tt.wantPanic)
+				}
+				if buf.String() != tt.wantLog {
+					t.Errorf("Logger.%s() = %q, want %q", tt.name, buf.String(), tt.wantLog)
+				}
+			}()
+
+			tt.call(Ctx(ctx))
+		})
+	}
+}
+
 var _ ctxLogger = &testCtxLogger{}
 
 type testCtxLogger struct {
 	buf *bytes.Buffer
 }
 
+func (l *testCtxLogger) Trace(ctx context.Context, v any) {
+	l.buf.WriteString("Trace: " + fmt.Sprint(v) + "," + fmt.Sprint(ctx.Value(l)))
+}
+
+func (l *testCtxLogger) Tracef(ctx context.Context, format string, v ...any) {
+	l.buf.WriteString("Tracef: " + fmt.Sprintf(format, v...) + "," + fmt.Sprint(ctx.Value(l)))
+}
+
 func (l *testCtxLogger) Debug(ctx context.Context, v any) {
 	l.buf.WriteString("Debug: " + fmt.Sprint(v) + "," + fmt.Sprint(ctx.Value(l)))
 }
@@ -330,12 +702,36 @@ func (l *testCtxLogger) Errorf(ctx context.Context, format string, v ...any) {
 	l.buf.WriteString("Errorf: " + fmt.Sprintf(format, v...) + "," + fmt.Sprint(ctx.Value(l)))
 }
 
+func (l *testCtxLogger) Critical(ctx context.Context, v any) {
+	l.buf.WriteString("Critical: " + fmt.Sprint(v) + "," + fmt.Sprint(ctx.Value(l)))
+}
+
+func (l *testCtxLogger) Criticalf(ctx context.Context, format string, v ...any) {
+	l.buf.WriteString("Criticalf: " + fmt.Sprintf(format, v...) + "," + fmt.Sprint(ctx.Value(l)))
+}
+
+func (l *testCtxLogger) Enabled(_ context.Context, level Level) bool {
+	return level >= LevelDebug
+}
+
+func (l *testCtxLogger) Flush() {
+	l.buf.WriteString(", Flushed")
+}
+
 func (l *testCtxLogger) AddRequestAttribute(_ string, _ any) {}
 
 func (l *testCtxLogger) WithAttributes() attributer {
 	return &Mockattributer{}
 }
 
+func (l *testCtxLogger) Named(_ string) ctxLogger {
+	return l
+}
+
 func (l *testCtxLogger) TraceID() string {
 	return "testTraceID"
 }
+
+func (l *testCtxLogger) RawTraceID() string {
+	return "testRawTraceID"
+}
@@ -0,0 +1,36 @@
+// Package loggerchi provides the middleware of a logger.Exporter for chi routers, which records the route
+// pattern of the request in the http.route attribute of the parent log.
+//
+// It is a separate module, so the logger module does not depend on chi.
+package loggerchi
+
+import (
+	"net/http"
+
+	"github.com/cccteam/logger"
+	"github.com/go-chi/chi/v5"
+)
+
+// Middleware returns the middleware of the exporter for a chi router, with the route pattern that matched
+// the request (e.g. "/users/{id}") in the http.route attribute of the parent log, e.g.
+//
+//	r := chi.NewRouter()
+//	r.Use(loggerchi.Middleware(exporter))
+func Middleware(e logger.Exporter) func(http.Handler) http.Handler {
+	mw := e.Middleware()
+	route := logger.RouteMiddleware(routePattern)
+
+	return func(next http.Handler) http.Handler {
+		return mw(route(next))
+	}
+}
+
+// routePattern returns the route pattern of the request, or an empty string outside of a chi router
+func routePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return ""
+	}
+
+	return rctx.RoutePattern()
+}
@@ -0,0 +1,74 @@
+package loggerchi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cccteam/logger"
+	"github.com/go-chi/chi/v5"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantRoute  string
+	}{
+		{name: "route", target: "/users/42", wantStatus: http.StatusOK, wantRoute: "/users/{id}"},
+		{name: "subrouter", target: "/admin/settings", wantStatus: http.StatusNoContent, wantRoute: "/admin/settings"},
+		{name: "not found", target: "/missing", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			r := chi.NewRouter()
+			r.Use(Middleware(logger.NewConsoleExporter().Writer(&buf).Format(logger.JSONFormat)))
+			r.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+				logger.Req(r).Info("get user")
+				_, _ = w.Write([]byte(chi.URLParam(r, "id")))
+			})
+			r.Route("/admin", func(r chi.Router) {
+				r.Get("/settings", func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusNoContent)
+				})
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, http.NoBody))
+			if w.Code != tt.wantStatus {
+				t.Errorf("Middleware() response status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			parent := parentLog(t, &buf)
+			if got, _ := parent["status"].(float64); int(got) != tt.wantStatus {
+				t.Errorf("Middleware() parent status = %v, want %d", parent["status"], tt.wantStatus)
+			}
+			if got, _ := parent["http.route"].(string); got != tt.wantRoute {
+				t.Errorf("Middleware() parent http.route = %q, want %q", got, tt.wantRoute)
+			}
+		})
+	}
+}
+
+// parentLog returns the parent log of the request, the last log written by a console exporter in JSON format
+func parentLog(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var parent map[string]any
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &parent); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	return parent
+}
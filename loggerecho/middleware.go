@@ -0,0 +1,41 @@
+// Package loggerecho provides the middleware of a logger.Exporter for Echo, which records the route of the
+// request in the http.route attribute of the parent log.
+//
+// It is a separate module, so the logger module does not depend on Echo.
+package loggerecho
+
+import (
+	"net/http"
+
+	"github.com/cccteam/logger"
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware returns the middleware of the exporter for Echo, with the route that matched the request
+// (e.g. "/users/:id") in the http.route attribute of the parent log, e.g.
+//
+//	e := echo.New()
+//	e.Use(loggerecho.Middleware(exporter))
+//
+// The errors of the handlers are passed to the error handler of Echo in the middleware, so the status of the
+// error response is logged. The logger of the request is returned by logger.Req(c.Request()).
+func Middleware(e logger.Exporter) echo.MiddlewareFunc {
+	mw := e.Middleware()
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			var err error
+			mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				c.SetRequest(r)
+				c.SetResponse(echo.NewResponse(w, c.Echo()))
+				if err = next(c); err != nil {
+					c.Error(err)
+				}
+				logger.Req(r).SetRoute(c.Path())
+			})).ServeHTTP(c.Response(), c.Request())
+
+			// the error response is committed, so the error handler of Echo does not write it again
+			return err
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package loggerecho
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cccteam/logger"
+	"github.com/labstack/echo/v4"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantRoute  string
+	}{
+		{name: "route", target: "/users/42", wantStatus: http.StatusOK, wantRoute: "/users/:id"},
+		{name: "handler error", target: "/orders/7", wantStatus: http.StatusConflict, wantRoute: "/orders/:id"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			e := echo.New()
+			e.Use(Middleware(logger.NewConsoleExporter().Writer(&buf).Format(logger.JSONFormat)))
+			e.GET("/users/:id", func(c echo.Context) error {
+				logger.Req(c.Request()).Info("get user")
+
+				return c.String(http.StatusOK, c.Param("id"))
+			})
+			e.GET("/orders/:id", func(echo.Context) error {
+				return echo.NewHTTPError(http.StatusConflict, "order is locked")
+			})
+
+			w := httptest.NewRecorder()
+			e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, http.NoBody))
+			if w.Code != tt.wantStatus {
+				t.Errorf("Middleware() response status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			parent := parentLog(t, &buf)
+			if got, _ := parent["status"].(float64); int(got) != tt.wantStatus {
+				t.Errorf("Middleware() parent status = %v, want %d", parent["status"], tt.wantStatus)
+			}
+			if got, _ := parent["http.route"].(string); got != tt.wantRoute {
+				t.Errorf("Middleware() parent http.route = %q, want %q", got, tt.wantRoute)
+			}
+		})
+	}
+}
+
+// parentLog returns the parent log of the request, the last log written by a console exporter in JSON format
+func parentLog(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var parent map[string]any
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &parent); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	return parent
+}
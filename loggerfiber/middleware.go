@@ -0,0 +1,98 @@
+// Package loggerfiber provides the middleware of a logger.Exporter for Fiber, which records the route of the
+// request in the http.route attribute of the parent log.
+//
+// It is a separate module, so the logger module does not depend on Fiber.
+package loggerfiber
+
+import (
+	"net/http"
+
+	"github.com/cccteam/logger"
+	"github.com/go-playground/errors/v5"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+// Middleware returns the middleware of the exporter for Fiber, with the route that matched the request
+// (e.g. "/users/:id") in the http.route attribute of the parent log, e.g.
+//
+//	app := fiber.New()
+//	app.Use(loggerfiber.Middleware(exporter))
+//
+// Fiber is not based on net/http, so each request is converted to an http.Request for the middleware, and the
+// response of the handlers is copied to the middleware after they return. The errors of the handlers are passed
+// to the error handler of the app in the middleware, so the status of the error response is logged. The logger
+// of the request is returned by logger.Ctx(c.UserContext()).
+func Middleware(e logger.Exporter) fiber.Handler {
+	mw := e.Middleware()
+
+	return func(c *fiber.Ctx) error {
+		r, err := adaptor.ConvertRequest(c, true)
+		if err != nil {
+			return errors.Wrap(err, "adaptor.ConvertRequest()")
+		}
+
+		fw := &responseWriter{c: c, header: make(http.Header)}
+		mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c.SetUserContext(r.Context())
+			// e.g. the request ID header set by the middleware
+			fw.copyHeader()
+			if err := c.Next(); err != nil {
+				if err := c.App().ErrorHandler(c, err); err != nil {
+					_ = c.SendStatus(fiber.StatusInternalServerError)
+				}
+			}
+			logger.Req(r).SetRoute(c.Route().Path)
+
+			// the response is already written to Fiber, it is written to the middleware to be logged
+			fw.handled = true
+			clear(w.Header())
+			c.Response().Header.VisitAll(func(k, v []byte) {
+				w.Header().Add(string(k), string(v))
+			})
+			w.WriteHeader(c.Response().StatusCode())
+			_, _ = w.Write(c.Response().Body())
+		})).ServeHTTP(fw, r.WithContext(c.UserContext()))
+
+		return nil
+	}
+}
+
+// responseWriter is the http.ResponseWriter of the middleware, which writes the response of the middleware
+// to Fiber, e.g. the response headers or the response of a recovered panic, but not the response of the
+// handlers that Fiber already has
+type responseWriter struct {
+	c       *fiber.Ctx
+	header  http.Header
+	handled bool
+}
+
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.handled {
+		return
+	}
+	w.copyHeader()
+	w.c.Status(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.handled {
+		w.c.Response().AppendBody(b)
+	}
+
+	return len(b), nil
+}
+
+// copyHeader copies the header of the middleware to the response of Fiber
+func (w *responseWriter) copyHeader() {
+	for k, v := range w.header {
+		w.c.Response().Header.Del(k)
+		for _, v := range v {
+			w.c.Response().Header.Add(k, v)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package loggerfiber
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cccteam/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantBody   string
+		wantRoute  string
+	}{
+		{name: "route", target: "/users/42", wantStatus: http.StatusOK, wantBody: "42", wantRoute: "/users/:id"},
+		{name: "handler error", target: "/orders/7", wantStatus: http.StatusConflict, wantBody: "order is locked", wantRoute: "/orders/:id"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			app := fiber.New()
+			app.Use(Middleware(logger.NewConsoleExporter().Writer(&buf).Format(logger.JSONFormat).RequestID("X-Request-Id")))
+			app.Get("/users/:id", func(c *fiber.Ctx) error {
+				logger.Ctx(c.UserContext()).Info("get user")
+
+				return c.SendString(c.Params("id"))
+			})
+			app.Get("/orders/:id", func(*fiber.Ctx) error {
+				return fiber.NewError(http.StatusConflict, "order is locked")
+			})
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.target, http.NoBody))
+			if err != nil {
+				t.Fatalf("fiber.App.Test() error = %v", err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.wantStatus || string(body) != tt.wantBody {
+				t.Errorf("Middleware() response = %d %q, want %d %q", resp.StatusCode, body, tt.wantStatus, tt.wantBody)
+			}
+			if resp.Header.Get("X-Request-Id") == "" {
+				t.Errorf("Middleware() response X-Request-Id is empty, want the request ID of the middleware")
+			}
+
+			parent := parentLog(t, &buf)
+			if got, _ := parent["status"].(float64); int(got) != tt.wantStatus {
+				t.Errorf("Middleware() parent status = %v, want %d", parent["status"], tt.wantStatus)
+			}
+			if got, _ := parent["responseSize"].(float64); int(got) != len(tt.wantBody) {
+				t.Errorf("Middleware() parent responseSize = %v, want %d", parent["responseSize"], len(tt.wantBody))
+			}
+			if got, _ := parent["http.route"].(string); got != tt.wantRoute {
+				t.Errorf("Middleware() parent http.route = %q, want %q", got, tt.wantRoute)
+			}
+		})
+	}
+}
+
+// parentLog returns the parent log of the request, the last log written by a console exporter in JSON format
+func parentLog(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var parent map[string]any
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &parent); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	return parent
+}
@@ -0,0 +1,91 @@
+// Package loggergin provides the middleware of a logger.Exporter for Gin, which records the route of the
+// request in the http.route attribute of the parent log.
+//
+// It is a separate module, so the logger module does not depend on Gin.
+package loggergin
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/cccteam/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/errors/v5"
+)
+
+// Middleware returns the middleware of the exporter for Gin, with the route that matched the request
+// (e.g. "/users/:id") in the http.route attribute of the parent log, e.g.
+//
+//	r := gin.New()
+//	r.Use(loggergin.Middleware(exporter))
+//
+// The logger of the request is returned by logger.Req(c.Request).
+func Middleware(e logger.Exporter) gin.HandlerFunc {
+	mw := e.Middleware()
+
+	return func(c *gin.Context) {
+		gw := c.Writer
+		var done bool
+		mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c.Request = r
+			c.Writer = &responseWriter{ResponseWriter: gw, w: w}
+			c.Next()
+			// Gin writes the status after the handlers, so it is written in the middleware to be logged
+			c.Writer.WriteHeaderNow()
+			logger.Req(r).SetRoute(c.FullPath())
+			done = true
+		})).ServeHTTP(gw, c.Request)
+		c.Writer = gw
+
+		// the middleware recovered a panic of a handler, the remaining handlers must not run
+		if !done {
+			c.Abort()
+		}
+	}
+}
+
+// responseWriter is the gin.ResponseWriter of the handlers, which writes the response through the middleware
+// of the exporter. Gin defers writing the status until the body is written, so it does too.
+type responseWriter struct {
+	gin.ResponseWriter
+	w http.ResponseWriter
+}
+
+func (rw *responseWriter) WriteHeaderNow() {
+	if !rw.ResponseWriter.Written() {
+		rw.w.WriteHeader(rw.ResponseWriter.Status())
+		rw.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	rw.WriteHeaderNow()
+
+	return rw.w.Write(b)
+}
+
+func (rw *responseWriter) WriteString(s string) (int, error) {
+	rw.WriteHeaderNow()
+
+	return io.WriteString(rw.w, s)
+}
+
+func (rw *responseWriter) Flush() {
+	rw.WriteHeaderNow()
+	_ = http.NewResponseController(rw.w).Flush()
+}
+
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := http.NewResponseController(rw.w).Hijack()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "http.ResponseController.Hijack()")
+	}
+
+	return conn, brw, nil
+}
+
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.w
+}
@@ -0,0 +1,96 @@
+// Package loggerprom provides a logger.MetricsRecorder that exports the metrics of the requests and child logs of
+// the middleware of a logger.Exporter to Prometheus, so handlers are not wrapped in a separate metrics middleware.
+//
+// It is a separate module, so the logger module does not depend on the Prometheus client.
+package loggerprom
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/cccteam/logger"
+	"github.com/go-playground/errors/v5"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics is a logger.MetricsRecorder of Prometheus metrics:
+//
+//   - http_server_requests_total, the count of requests by method, route, and code
+//   - http_server_request_duration_seconds, the histogram of the latency of requests by method, route, and code
+//   - http_server_response_size_bytes, the histogram of the size of responses by method, route, and code
+//   - logger_logs_total, the count of child logs by level
+//
+// The route is the http.route attribute of the request (e.g. set by logger.RouteMiddleware or Logger.SetRoute),
+// empty when not set, since the values of the path are unbounded.
+type Metrics struct {
+	requests *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+	size     *prometheus.HistogramVec
+	logs     *prometheus.CounterVec
+}
+
+var _ logger.MetricsRecorder = (*Metrics)(nil)
+
+// NewMetrics returns the Metrics registered with reg, e.g. prometheus.DefaultRegisterer, or
+// prometheus.WrapRegistererWithPrefix to add a prefix to the names of the metrics, e.g.
+//
+//	metrics, err := loggerprom.NewMetrics(prometheus.DefaultRegisterer)
+//	exporter := logger.NewGoogleCloudExporter(client, projectID).Metrics(metrics)
+func NewMetrics(reg prometheus.Registerer) (*Metrics, error) {
+	labels := []string{"method", "route", "code"}
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_server_requests_total",
+			Help: "Count of the HTTP requests served.",
+		}, labels),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_server_request_duration_seconds",
+			Help:    "Latency of the HTTP requests served.",
+			Buckets: prometheus.DefBuckets,
+		}, labels),
+		size: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_server_response_size_bytes",
+			Help:    "Size of the bodies of the HTTP responses.",
+			Buckets: prometheus.ExponentialBuckets(100, 10, 7),
+		}, labels),
+		logs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "logger_logs_total",
+			Help: "Count of the child logs of the HTTP requests written.",
+		}, []string{"level"}),
+	}
+
+	for _, c := range []prometheus.Collector{m.requests, m.latency, m.size, m.logs} {
+		if err := reg.Register(c); err != nil {
+			return nil, errors.Wrap(err, "prometheus.Registerer.Register()")
+		}
+	}
+
+	return m, nil
+}
+
+// RecordRequest implements logger.MetricsRecorder
+func (m *Metrics) RecordRequest(rm logger.RequestMetrics) {
+	labels := prometheus.Labels{"method": rm.Method, "route": rm.Route, "code": strconv.Itoa(rm.Status)}
+	m.requests.With(labels).Inc()
+	m.latency.With(labels).Observe(rm.Latency.Seconds())
+	m.size.With(labels).Observe(float64(rm.ResponseSize))
+}
+
+// RecordLog implements logger.MetricsRecorder
+func (m *Metrics) RecordLog(level logger.Level) {
+	m.logs.WithLabelValues(levelLabel(level)).Inc()
+}
+
+// levelLabel returns the lowercase name of the level, naming logger.LevelTrace and logger.LevelCritical
+// which slog names relative to the built-in levels
+func levelLabel(level slog.Level) string {
+	switch level {
+	case logger.LevelTrace:
+		return "trace"
+	case logger.LevelCritical:
+		return "critical"
+	default:
+		return strings.ToLower(level.String())
+	}
+}
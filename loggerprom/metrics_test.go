@@ -0,0 +1,85 @@
+package loggerprom
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cccteam/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	m, err := NewMetrics(prometheus.NewRegistry())
+	if err != nil {
+		t.Fatalf("NewMetrics() error = %v", err)
+	}
+
+	handler := logger.NewConsoleExporter().Writer(io.Discard).Metrics(m).Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l := logger.Req(r).SetRoute("/users/{id}")
+		l.Info("get user")
+		l.Info("got user")
+		l.Error("user not found")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("not found"))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", http.NoBody))
+
+	if got := testutil.ToFloat64(m.requests.WithLabelValues(http.MethodGet, "/users/{id}", "404")); got != 1 {
+		t.Errorf("http_server_requests_total = %v, want 1", got)
+	}
+	if got := testutil.CollectAndCount(m.latency); got != 1 {
+		t.Errorf("http_server_request_duration_seconds series = %d, want 1", got)
+	}
+	if got := testutil.CollectAndCount(m.size); got != 1 {
+		t.Errorf("http_server_response_size_bytes series = %d, want 1", got)
+	}
+	for level, want := range map[string]float64{"info": 2, "error": 1, "debug": 0} {
+		if got := testutil.ToFloat64(m.logs.WithLabelValues(level)); got != want {
+			t.Errorf("logger_logs_total{level=%q} = %v, want %v", level, got, want)
+		}
+	}
+}
+
+func TestNewMetrics_registered(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+	if _, err := NewMetrics(reg); err != nil {
+		t.Fatalf("NewMetrics() error = %v", err)
+	}
+	if _, err := NewMetrics(reg); err == nil {
+		t.Errorf("NewMetrics() error = nil, want an error for metrics already registered")
+	}
+}
+
+func Test_levelLabel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		level slog.Level
+		want  string
+	}{
+		{level: logger.LevelTrace, want: "trace"},
+		{level: logger.LevelDebug, want: "debug"},
+		{level: logger.LevelInfo, want: "info"},
+		{level: logger.LevelWarn, want: "warn"},
+		{level: logger.LevelError, want: "error"},
+		{level: logger.LevelCritical, want: "critical"},
+		{level: logger.LevelWarn + 2, want: "warn+2"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.want, func(t *testing.T) {
+			t.Parallel()
+			if got := levelLabel(tt.level); got != tt.want {
+				t.Errorf("levelLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+)
+
+// logrBadKey is the key of a value without a key in the key/value pairs of a logr log, like in log/slog
+const logrBadKey = "!BADKEY"
+
+// NewLogSink returns a logr.LogSink that writes the logs of logr-based libraries (e.g. controller-runtime) as child
+// logs with the logger of the context (see Ctx), so they are correlated to the request log, e.g.
+//
+//	log := logr.New(logger.NewLogSink(ctx))
+//
+// The verbosity of logr is mapped to the levels: V(0) is Info, V(1) is Debug, and V(2) and above are Trace.
+// The key/value pairs are child log attributes, and the names are components (see Logger.Named). The caller
+// written with IncludeCaller is the caller of logr, using the call depth of logr.
+func NewLogSink(ctx context.Context) logr.LogSink {
+	return &logrSink{l: adapterCtx(ctx)}
+}
+
+// logrSink is the logr.LogSink returned by NewLogSink
+type logrSink struct {
+	l *Logger
+}
+
+// Init implements logr.LogSink, the call depth of logr is skipped to find the caller
+func (s *logrSink) Init(info logr.RuntimeInfo) {
+	s.l = s.l.withCaller(info.CallDepth, "")
+}
+
+// WithCallDepth implements logr.CallDepthLogSink, for the helpers of logr users that skip their frames
+func (s *logrSink) WithCallDepth(depth int) logr.LogSink {
+	return &logrSink{l: s.l.withCaller(depth, "")}
+}
+
+// Enabled implements logr.LogSink
+func (s *logrSink) Enabled(level int) bool {
+	return s.l.Enabled(logrLevel(level))
+}
+
+// Info implements logr.LogSink
+func (s *logrSink) Info(level int, msg string, keysAndValues ...any) {
+	s.with(keysAndValues).Log(logrLevel(level), msg)
+}
+
+// Error implements logr.LogSink, the error is added with Logger.WithError
+func (s *logrSink) Error(err error, msg string, keysAndValues ...any) {
+	s.with(keysAndValues).WithError(err).Error(msg)
+}
+
+// WithValues implements logr.LogSink
+func (s *logrSink) WithValues(keysAndValues ...any) logr.LogSink {
+	return &logrSink{l: s.with(keysAndValues)}
+}
+
+// WithName implements logr.LogSink
+func (s *logrSink) WithName(name string) logr.LogSink {
+	return &logrSink{l: s.l.Named(name)}
+}
+
+// with returns the logger with the key/value pairs as child log attributes
+func (s *logrSink) with(keysAndValues []any) *Logger {
+	if len(keysAndValues) == 0 {
+		return s.l
+	}
+
+	a := s.l.WithAttributes()
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i+1 == len(keysAndValues) {
+			a.AddAttribute(logrBadKey, logrValue(keysAndValues[i]))
+
+			break
+		}
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		a.AddAttribute(key, logrValue(keysAndValues[i+1]))
+	}
+
+	return a.Logger()
+}
+
+// logrLevel returns the level of the verbosity of logr
+func logrLevel(level int) Level {
+	switch {
+	case level <= 0:
+		return LevelInfo
+	case level == 1:
+		return LevelDebug
+	default:
+		return LevelTrace
+	}
+}
+
+// logrValue returns the value of a logr.Marshaler to log, or v
+func logrValue(v any) any {
+	if m, ok := v.(logr.Marshaler); ok {
+		return m.MarshalLog()
+	}
+
+	return v
+}
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+type logrMarshaler struct{}
+
+func (logrMarshaler) MarshalLog() any {
+	return "marshaled"
+}
+
+func TestNewLogSink(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		log       func(l logr.Logger)
+		wantChild map[string]any
+	}{
+		{
+			name:      "info",
+			log:       func(l logr.Logger) { l.Info("reconciled", "name", "web", "replicas", 3) },
+			wantChild: map[string]any{"level": "INFO", "msg": "reconciled", "name": "web", "replicas": float64(3)},
+		},
+		{
+			name:      "debug",
+			log:       func(l logr.Logger) { l.V(1).Info("requeue") },
+			wantChild: map[string]any{"level": "DEBUG", "msg": "requeue"},
+		},
+		{
+			name:      "error",
+			log:       func(l logr.Logger) { l.Error(errors.New("conflict"), "update failed", "name", "web") },
+			wantChild: map[string]any{"level": "ERROR", "msg": "update failed", "name": "web", errorMessageKey: "conflict"},
+		},
+		{
+			name:      "values and name",
+			log:       func(l logr.Logger) { l.WithName("manager").WithValues("controller", "deployment").Info("started") },
+			wantChild: map[string]any{"level": "INFO", "msg": "started", "controller": "deployment", componentKey: "manager"},
+		},
+		{
+			name:      "bad key and marshaler",
+			log:       func(l logr.Logger) { l.Info("odd", "value", logrMarshaler{}, "dangling") },
+			wantChild: map[string]any{"level": "INFO", "msg": "odd", "value": "marshaled", logrBadKey: "dangling"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			h := NewConsoleExporter().Writer(&buf).Format(JSONFormat).Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				l := logr.New(NewLogSink(r.Context()))
+				tt.log(l)
+				l.V(2).Info("trace logs are dropped")
+			}))
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+			child, parent := grpcLogs(t, &buf)
+			for k, want := range tt.wantChild {
+				if got := child[k]; got != want {
+					t.Errorf("NewLogSink() child %s = %v, want %v", k, got, want)
+				}
+			}
+			if child["traceID"] == nil || child["traceID"] != parent["traceID"] {
+				t.Errorf("NewLogSink() child traceID = %v, parent traceID = %v", child["traceID"], parent["traceID"])
+			}
+		})
+	}
+}
+
+func TestNewLogSink_caller(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	h := NewConsoleExporter().Writer(&buf).Format(JSONFormat).IncludeCaller(true).Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		l := logr.New(NewLogSink(r.Context()))
+		l.Info("reconciled")
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	child, _ := grpcLogs(t, &buf)
+	if source, _ := child[slog.SourceKey].(string); !strings.Contains(source, "logr_test.go:") {
+		t.Errorf("NewLogSink() child source = %q, want logr_test.go", source)
+	}
+}
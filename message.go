@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-playground/errors/v5"
+)
+
+// keys of the attributes of the parent log of a message
+const (
+	messagingSourceKey  = "messaging.source"
+	messagingIDKey      = "messaging.message_id"
+	messagingLatencyKey = "messaging.publish_latency_ms"
+	messagingOutcomeKey = "messaging.outcome"
+	messagingErrorKey   = "messaging.error"
+)
+
+// outcomes of a message in the messaging.outcome attribute
+const (
+	messageAck  = "ack"
+	messageNack = "nack"
+)
+
+// Message is a message consumed from a subscription, queue, or topic, e.g. of Cloud Pub/Sub, SQS, or Kafka
+type Message struct {
+	// Source is the subscription, queue, or topic of the message, e.g. "projects/my-project/subscriptions/orders"
+	Source string
+	// ID is the ID of the message
+	ID string
+	// PublishTime is the time the message was published, zero when unknown
+	PublishTime time.Time
+	// Attributes are the attributes or headers of the message, which correlate the logs to the trace of the
+	// publisher with the trace headers of the exporter, e.g. "traceparent". The "googclient_" prefix of the
+	// attributes of Cloud Pub/Sub is removed, and the "AWSTraceHeader" of SQS is the X-Amzn-Trace-Id header.
+	Attributes map[string]string
+}
+
+// MessageHandler handles a consumed message. An error nacks the message, so it is delivered again.
+type MessageHandler func(ctx context.Context, msg *Message) error
+
+// WrapMessageHandler returns a MessageHandler that logs each message with the middleware of the exporter, with a
+// parent log for the message and correlated child logs from the logger of the context of handler, e.g.
+//
+//	handle := logger.WrapMessageHandler(exporter, processOrder)
+//	err := sub.Receive(ctx, func(ctx context.Context, m *pubsub.Message) {
+//		msg := &logger.Message{Source: sub.String(), ID: m.ID, PublishTime: m.PublishTime, Attributes: m.Attributes}
+//		if err := handle(ctx, msg); err != nil {
+//			m.Nack()
+//			return
+//		}
+//		m.Ack()
+//	})
+//
+// Each message is a request with the CONSUME method and the path of the source, so the options of the exporter
+// apply to messages like to HTTP requests. The parent log has the ID of the message, the latency from publish to
+// ack, and the outcome. Nacked messages have the 500 status. A panic of handler recovered by the middleware
+// (see RecoverPanics) is returned as an error, so the message is nacked.
+func WrapMessageHandler(e Exporter, handler MessageHandler) MessageHandler {
+	h := e.Middleware()(http.HandlerFunc(serveMessage))
+
+	return func(ctx context.Context, msg *Message) error {
+		call := &messageCall{msg: msg, handler: handler}
+		h.ServeHTTP(&statusResponseWriter{header: make(http.Header)}, messageRequest(context.WithValue(ctx, messageCallKey, call), msg))
+
+		return call.err
+	}
+}
+
+// messageCall is a message handled by the middleware of an exporter
+type messageCall struct {
+	msg     *Message
+	handler MessageHandler
+	err     error
+}
+
+// serveMessage handles the message in the context of the request, and writes its outcome
+func serveMessage(w http.ResponseWriter, r *http.Request) {
+	call, ok := r.Context().Value(messageCallKey).(*messageCall)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	defer func() {
+		// the panic is raised again for the middleware, which logs it, and recovers it with RecoverPanics
+		if v := recover(); v != nil {
+			call.err = errors.Newf("panic: %v", v)
+			panic(v)
+		}
+	}()
+	call.err = call.handler(r.Context(), call.msg)
+
+	l := Req(r).AddRequestAttribute(messagingSourceKey, call.msg.Source).AddRequestAttribute(messagingIDKey, call.msg.ID)
+	if !call.msg.PublishTime.IsZero() {
+		l.AddRequestAttribute(messagingLatencyKey, float64(time.Since(call.msg.PublishTime))/float64(time.Millisecond))
+	}
+	if call.err != nil {
+		l.AddRequestAttribute(messagingOutcomeKey, messageNack).AddRequestAttribute(messagingErrorKey, call.err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+	l.AddRequestAttribute(messagingOutcomeKey, messageAck)
+	w.WriteHeader(http.StatusOK)
+}
+
+// messageRequest returns the request of the message for the middleware of an exporter
+func messageRequest(ctx context.Context, msg *Message) *http.Request {
+	path := "/" + strings.TrimPrefix(msg.Source, "/")
+	r := (&http.Request{
+		Method:     "CONSUME",
+		URL:        &url.URL{Path: path},
+		RequestURI: path,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Body:       http.NoBody,
+	}).WithContext(ctx)
+
+	for k, v := range msg.Attributes {
+		switch {
+		case k == "AWSTraceHeader":
+			k = awsXRayTraceHeader
+		case strings.HasPrefix(k, "googclient_"):
+			k = strings.TrimPrefix(k, "googclient_")
+		}
+		r.Header.Set(k, v)
+	}
+
+	return r
+}
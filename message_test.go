@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestWrapMessageHandler(t *testing.T) {
+	t.Parallel()
+
+	errNack := errors.New("order not found")
+	tests := []struct {
+		name       string
+		err        error
+		wantParent map[string]any
+	}{
+		{
+			name: "ack",
+			wantParent: map[string]any{
+				"level": "INFO", "msg": "CONSUME /projects/p/subscriptions/orders", "method": "CONSUME", "path": "/projects/p/subscriptions/orders",
+				"status": float64(200), "requestSize": float64(0), "responseSize": float64(0), "logCount": float64(1),
+				messagingSourceKey: "projects/p/subscriptions/orders", messagingIDKey: "42", messagingOutcomeKey: messageAck,
+			},
+		},
+		{
+			name: "nack",
+			err:  errNack,
+			wantParent: map[string]any{
+				"level": "ERROR", "msg": "CONSUME /projects/p/subscriptions/orders", "method": "CONSUME", "path": "/projects/p/subscriptions/orders",
+				"status": float64(500), "requestSize": float64(0), "responseSize": float64(0), "logCount": float64(1),
+				messagingSourceKey: "projects/p/subscriptions/orders", messagingIDKey: "42", messagingOutcomeKey: messageNack,
+				messagingErrorKey: errNack.Error(),
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			handle := WrapMessageHandler(NewConsoleExporter().Writer(&buf).Format(JSONFormat), func(ctx context.Context, msg *Message) error {
+				Ctx(ctx).Infof("processing %s", msg.ID)
+
+				return tt.err
+			})
+
+			err := handle(context.Background(), &Message{
+				Source:      "projects/p/subscriptions/orders",
+				ID:          "42",
+				PublishTime: time.Now().Add(-time.Second),
+				Attributes:  map[string]string{"googclient_traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("WrapMessageHandler() error = %v, want %v", err, tt.err)
+			}
+
+			child, parent := grpcLogs(t, &buf)
+			if child["traceID"] != "4bf92f3577b34da6a3ce929d0e0e4736" || parent["traceID"] != child["traceID"] {
+				t.Errorf("WrapMessageHandler() child traceID = %v, parent traceID = %v", child["traceID"], parent["traceID"])
+			}
+			if latency, ok := parent[messagingLatencyKey].(float64); !ok || latency < 1000 {
+				t.Errorf("WrapMessageHandler() %s = %v, want >= 1000", messagingLatencyKey, parent[messagingLatencyKey])
+			}
+			ignore := cmpopts.IgnoreMapEntries(func(k string, _ any) bool {
+				return k == "time" || k == "elapsed" || k == "traceID" || k == messagingLatencyKey
+			})
+			if diff := cmp.Diff(tt.wantParent, parent, ignore); diff != "" {
+				t.Errorf("WrapMessageHandler() parent mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWrapMessageHandler_panic(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	handle := WrapMessageHandler(NewConsoleExporter().Writer(&buf).Format(JSONFormat).RecoverPanics(false), func(context.Context, *Message) error {
+		panic("order is nil")
+	})
+
+	if err := handle(context.Background(), &Message{Source: "orders", ID: "42"}); err == nil {
+		t.Error("WrapMessageHandler() error = nil, want the error of the panic so the message is nacked")
+	}
+	if !strings.Contains(buf.String(), "panic: order is nil") {
+		t.Errorf("WrapMessageHandler() output = %q, missing the panic", buf.String())
+	}
+}
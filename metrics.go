@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"net/http"
+	"time"
+)
+
+// RequestMetrics are the metrics of a request served by the middleware of an exporter
+type RequestMetrics struct {
+	Method string
+	// Route is the route template of the request in the http.route attribute, e.g. "/users/{id}", empty when the
+	// route is not set. The path of the request is not a metric label, since its values are unbounded.
+	Route        string
+	Status       int
+	Latency      time.Duration
+	ResponseSize int64
+}
+
+// MetricsRecorder records the metrics of the requests and child logs of the middleware of an exporter, in the same
+// pass as the logs, so handlers are not wrapped in a separate metrics middleware, e.g. loggerprom.Metrics
+type MetricsRecorder interface {
+	// RecordRequest records a request after its handler returned, including requests of excluded paths
+	RecordRequest(m RequestMetrics)
+	// RecordLog records a child log written at the level
+	RecordLog(level Level)
+}
+
+// requestMetrics returns the metrics of the request served in the response recorder since begin
+func requestMetrics(r *http.Request, sw responseRecorder, begin time.Time, route string) RequestMetrics {
+	return RequestMetrics{
+		Method:       r.Method,
+		Route:        route,
+		Status:       sw.Status(),
+		Latency:      time.Since(begin),
+		ResponseSize: sw.Length(),
+	}
+}
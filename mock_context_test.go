@@ -51,6 +51,35 @@ func (mr *MockctxLoggerMockRecorder) AddRequestAttribute(key, value any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRequestAttribute", reflect.TypeOf((*MockctxLogger)(nil).AddRequestAttribute), key, value)
 }
 
+// Critical mocks base method.
+func (m *MockctxLogger) Critical(ctx context.Context, v any) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Critical", ctx, v)
+}
+
+// Critical indicates an expected call of Critical.
+func (mr *MockctxLoggerMockRecorder) Critical(ctx, v any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Critical", reflect.TypeOf((*MockctxLogger)(nil).Critical), ctx, v)
+}
+
+// Criticalf mocks base method.
+func (m *MockctxLogger) Criticalf(ctx context.Context, format string, v ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, format}
+	for _, a := range v {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Criticalf", varargs...)
+}
+
+// Criticalf indicates an expected call of Criticalf.
+func (mr *MockctxLoggerMockRecorder) Criticalf(ctx, format any, v ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, format}, v...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Criticalf", reflect.TypeOf((*MockctxLogger)(nil).Criticalf), varargs...)
+}
+
 // Debug mocks base method.
 func (m *MockctxLogger) Debug(ctx context.Context, v any) {
 	m.ctrl.T.Helper()
@@ -80,6 +109,20 @@ func (mr *MockctxLoggerMockRecorder) Debugf(ctx, format any, v ...any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Debugf", reflect.TypeOf((*MockctxLogger)(nil).Debugf), varargs...)
 }
 
+// Enabled mocks base method.
+func (m *MockctxLogger) Enabled(ctx context.Context, level Level) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enabled", ctx, level)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Enabled indicates an expected call of Enabled.
+func (mr *MockctxLoggerMockRecorder) Enabled(ctx, level any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enabled", reflect.TypeOf((*MockctxLogger)(nil).Enabled), ctx, level)
+}
+
 // Error mocks base method.
 func (m *MockctxLogger) Error(ctx context.Context, v any) {
 	m.ctrl.T.Helper()
@@ -109,6 +152,18 @@ func (mr *MockctxLoggerMockRecorder) Errorf(ctx, format any, v ...any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Errorf", reflect.TypeOf((*MockctxLogger)(nil).Errorf), varargs...)
 }
 
+// Flush mocks base method.
+func (m *MockctxLogger) Flush() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Flush")
+}
+
+// Flush indicates an expected call of Flush.
+func (mr *MockctxLoggerMockRecorder) Flush() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flush", reflect.TypeOf((*MockctxLogger)(nil).Flush))
+}
+
 // Info mocks base method.
 func (m *MockctxLogger) Info(ctx context.Context, v any) {
 	m.ctrl.T.Helper()
@@ -138,6 +193,46 @@ func (mr *MockctxLoggerMockRecorder) Infof(ctx, format any, v ...any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Infof", reflect.TypeOf((*MockctxLogger)(nil).Infof), varargs...)
 }
 
+// Named mocks base method.
+func (m *MockctxLogger) Named(name string) ctxLogger {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Named", name)
+	ret0, _ := ret[0].(ctxLogger)
+	return ret0
+}
+
+// Named indicates an expected call of Named.
+func (mr *MockctxLoggerMockRecorder) Named(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Named", reflect.TypeOf((*MockctxLogger)(nil).Named), name)
+}
+
+// RawTraceID mocks base method.
+func (m *MockctxLogger) RawTraceID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RawTraceID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// RawTraceID indicates an expected call of RawTraceID.
+func (mr *MockctxLoggerMockRecorder) RawTraceID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RawTraceID", reflect.TypeOf((*MockctxLogger)(nil).RawTraceID))
+}
+
+// Trace mocks base method.
+func (m *MockctxLogger) Trace(ctx context.Context, v any) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Trace", ctx, v)
+}
+
+// Trace indicates an expected call of Trace.
+func (mr *MockctxLoggerMockRecorder) Trace(ctx, v any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trace", reflect.TypeOf((*MockctxLogger)(nil).Trace), ctx, v)
+}
+
 // TraceID mocks base method.
 func (m *MockctxLogger) TraceID() string {
 	m.ctrl.T.Helper()
@@ -152,6 +247,23 @@ func (mr *MockctxLoggerMockRecorder) TraceID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TraceID", reflect.TypeOf((*MockctxLogger)(nil).TraceID))
 }
 
+// Tracef mocks base method.
+func (m *MockctxLogger) Tracef(ctx context.Context, format string, v ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, format}
+	for _, a := range v {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Tracef", varargs...)
+}
+
+// Tracef indicates an expected call of Tracef.
+func (mr *MockctxLoggerMockRecorder) Tracef(ctx, format any, v ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, format}, v...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tracef", reflect.TypeOf((*MockctxLogger)(nil).Tracef), varargs...)
+}
+
 // Warn mocks base method.
 func (m *MockctxLogger) Warn(ctx context.Context, v any) {
 	m.ctrl.T.Helper()
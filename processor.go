@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"maps"
+	"time"
+)
+
+// Entry is a log entry passed to the processors of an exporter before it is written
+type Entry struct {
+	Time       time.Time
+	Level      Level
+	Message    string         // empty for the request log
+	Attributes map[string]any // user attributes, owned by the processors
+	Component  string         // name of the component of the Logger, empty when not Named
+	TraceID    string
+	Request    bool // the parent request log, which only Level and Attributes are used from
+	Status     int  // status of the response of the request log, 0 for child logs
+}
+
+// Processor processes a log entry before it is written, and returns the entry to write, and whether
+// it is written. It can enrich or scrub the entry, or sample or route it by returning false.
+type Processor func(Entry) (Entry, bool)
+
+// processors is a chain of Processors run in order
+type processors []Processor
+
+// run runs the processors on the entry, stopping at the first processor that drops it
+func (p processors) run(e Entry) (Entry, bool) {
+	for _, process := range p {
+		var ok bool
+		if e, ok = process(e); !ok {
+			return e, false
+		}
+	}
+
+	return e, true
+}
+
+// process runs the processors on a log entry with a copy of the attributes, and returns the entry to
+// write, and whether it is written. Without processors, the entry has the attributes unchanged.
+func (p processors) process(e Entry) (Entry, bool) {
+	if len(p) == 0 {
+		return e, true
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	e.Attributes = maps.Clone(e.Attributes)
+	if e.Attributes == nil {
+		e.Attributes = make(map[string]any)
+	}
+
+	e, ok := p.run(e)
+	if e.Attributes == nil {
+		e.Attributes = make(map[string]any)
+	}
+
+	return e, ok
+}
@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func Test_processors_process(t *testing.T) {
+	t.Parallel()
+
+	scrub := func(e Entry) (Entry, bool) {
+		delete(e.Attributes, "password")
+
+		return e, true
+	}
+	enrich := func(e Entry) (Entry, bool) {
+		e.Attributes["region"] = "us-east1"
+
+		return e, true
+	}
+	drop := func(e Entry) (Entry, bool) {
+		return e, e.Level >= LevelWarn
+	}
+
+	tests := []struct {
+		name       string
+		processors processors
+		entry      Entry
+		want       Entry
+		wantOK     bool
+	}{
+		{
+			name:   "no processors",
+			entry:  Entry{Level: LevelInfo, Message: "message", Attributes: map[string]any{"password": "secret"}},
+			want:   Entry{Level: LevelInfo, Message: "message", Attributes: map[string]any{"password": "secret"}},
+			wantOK: true,
+		},
+		{
+			name:       "chain",
+			processors: processors{scrub, enrich},
+			entry:      Entry{Level: LevelInfo, Message: "message", Attributes: map[string]any{"password": "secret", "user": "bob"}},
+			want:       Entry{Level: LevelInfo, Message: "message", Attributes: map[string]any{"user": "bob", "region": "us-east1"}},
+			wantOK:     true,
+		},
+		{
+			name:       "nil attributes",
+			processors: processors{enrich},
+			entry:      Entry{Level: LevelInfo, Message: "message"},
+			want:       Entry{Level: LevelInfo, Message: "message", Attributes: map[string]any{"region": "us-east1"}},
+			wantOK:     true,
+		},
+		{
+			name:       "dropped",
+			processors: processors{drop, enrich},
+			entry:      Entry{Level: LevelInfo, Message: "message"},
+			want:       Entry{Level: LevelInfo, Message: "message", Attributes: map[string]any{}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			attributes := map[string]any{}
+			for k, v := range tt.entry.Attributes {
+				attributes[k] = v
+			}
+
+			got, ok := tt.processors.process(tt.entry)
+			if ok != tt.wantOK {
+				t.Errorf("process() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(Entry{}, "Time")); diff != "" {
+				t.Errorf("process() mismatch (-want +got):\n%s", diff)
+			}
+			if tt.entry.Attributes != nil {
+				if diff := cmp.Diff(attributes, tt.entry.Attributes); diff != "" {
+					t.Errorf("process() modified the attributes (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package logger
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// cloudTraceContextHeader is the legacy trace header of Google Cloud, e.g. "105445aa7843bc8bf206b120001000/1;o=1"
+	cloudTraceContextHeader = "X-Cloud-Trace-Context"
+
+	// maxCloudTraceContextLength is the maximum length of the X-Cloud-Trace-Context header that is parsed
+	maxCloudTraceContextLength = 200
+)
+
+// default propagators of the exporters. In a composite propagator, the last propagator with a trace in the
+// headers wins, so traceparent takes priority over X-Cloud-Trace-Context, and X-Amzn-Trace-Id over traceparent.
+var (
+	gcpPropagator     propagation.TextMapPropagator = propagation.NewCompositeTextMapPropagator(CloudTraceContext{}, propagation.TraceContext{})
+	awsPropagator     propagation.TextMapPropagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, XRayTraceContext{})
+	consolePropagator propagation.TextMapPropagator = propagation.TraceContext{}
+)
+
+// remoteSpanContext returns the span context propagated in the headers of the request. The span context may
+// only have a trace ID, e.g. of an X-Amzn-Trace-Id header without a parent.
+func remoteSpanContext(r *http.Request, p propagation.TextMapPropagator) trace.SpanContext {
+	return trace.SpanContextFromContext(p.Extract(context.Background(), propagation.HeaderCarrier(r.Header)))
+}
+
+// traceIDFromRequest returns the trace ID of the span of the context of the request, or of the trace propagated
+// in the headers of the request, or a new trace ID returned by idgen
+func traceIDFromRequest(r *http.Request, p propagation.TextMapPropagator, idgen func() string) string {
+	if sc := trace.SpanFromContext(r.Context()).SpanContext(); sc.IsValid() {
+		return sc.TraceID().String()
+	}
+	if sc := remoteSpanContext(r, p); sc.TraceID().IsValid() {
+		return sc.TraceID().String()
+	}
+
+	return idgen()
+}
+
+// traceSampled reports whether the trace of the request is sampled, from the span in the context,
+// or the trace propagated in the headers when the context does not contain a span
+func traceSampled(r *http.Request, p propagation.TextMapPropagator) bool {
+	if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+		return sc.IsSampled()
+	}
+
+	return remoteSpanContext(r, p).IsSampled()
+}
+
+// newPropagatorContext returns a copy of ctx with the propagator of the exporter, which propagates the trace of
+// the request to outbound requests
+func newPropagatorContext(ctx context.Context, p propagation.TextMapPropagator) context.Context {
+	if p == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, propagatorCtxKey, p)
+}
+
+// propagatorFromCtx returns the propagator of the exporter of the request, or the global propagator of
+// OpenTelemetry outside of requests
+func propagatorFromCtx(ctx context.Context) propagation.TextMapPropagator {
+	if ctx != nil {
+		if p, ok := ctx.Value(propagatorCtxKey).(propagation.TextMapPropagator); ok {
+			return p
+		}
+	}
+
+	return otel.GetTextMapPropagator()
+}
+
+// CloudTraceContext is a propagation.TextMapPropagator of the legacy X-Cloud-Trace-Context header of Google Cloud,
+// e.g. to add to the traceparent header for clients that do not support W3C Trace Context
+type CloudTraceContext struct{}
+
+var _ propagation.TextMapPropagator = CloudTraceContext{}
+
+// Inject sets the X-Cloud-Trace-Context header of the span of the context
+func (CloudTraceContext) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+
+	var sampled int
+	if sc.IsSampled() {
+		sampled = 1
+	}
+	spanID := sc.SpanID()
+	carrier.Set(cloudTraceContextHeader, fmt.Sprintf("%s/%d;o=%d", sc.TraceID(), binary.BigEndian.Uint64(spanID[:]), sampled))
+}
+
+// Extract returns the context with the remote span of the X-Cloud-Trace-Context header, or ctx when the header
+// is missing or invalid
+func (CloudTraceContext) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	h := carrier.Get(cloudTraceContextHeader)
+	if h == "" || len(h) > maxCloudTraceContextLength {
+		return ctx
+	}
+
+	rawTraceID, rest, ok := strings.Cut(h, "/")
+	if !ok {
+		return ctx
+	}
+	traceID, err := trace.TraceIDFromHex(rawTraceID)
+	if err != nil {
+		return ctx
+	}
+	rawSpanID, options, _ := strings.Cut(rest, ";")
+	id, err := strconv.ParseUint(rawSpanID, 10, 64)
+	if err != nil {
+		return ctx
+	}
+	var spanID trace.SpanID
+	binary.BigEndian.PutUint64(spanID[:], id)
+
+	var flags trace.TraceFlags
+	if strings.TrimPrefix(options, "o=") == "1" {
+		flags = trace.FlagsSampled
+	}
+
+	return trace.ContextWithRemoteSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: flags, Remote: true}))
+}
+
+// Fields returns the X-Cloud-Trace-Context header
+func (CloudTraceContext) Fields() []string {
+	return []string{cloudTraceContextHeader}
+}
+
+// XRayTraceContext is a propagation.TextMapPropagator of the X-Amzn-Trace-Id header of AWS X-Ray
+type XRayTraceContext struct{}
+
+var _ propagation.TextMapPropagator = XRayTraceContext{}
+
+// Inject sets the X-Amzn-Trace-Id header of the span of the context
+func (XRayTraceContext) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+
+	var sampled int
+	if sc.IsSampled() {
+		sampled = 1
+	}
+	carrier.Set(awsXRayTraceHeader, fmt.Sprintf("Root=%s;Parent=%s;Sampled=%d", xrayTraceID(sc.TraceID().String()), sc.SpanID(), sampled))
+}
+
+// Extract returns the context with the remote span of the X-Amzn-Trace-Id header, or ctx when the header is
+// missing or invalid. The load balancers of AWS set the header without a parent, so the span context of the
+// header may only have a trace ID, which does not replace a valid span of ctx, e.g. of a traceparent header.
+func (XRayTraceContext) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	h, ok := parseXRayTraceHeader(carrier.Get(awsXRayTraceHeader))
+	if !ok {
+		return ctx
+	}
+	traceID, err := trace.TraceIDFromHex(h.traceID)
+	if err != nil {
+		return ctx
+	}
+	spanID, err := trace.SpanIDFromHex(h.parentID)
+	if err != nil && trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+
+	var flags trace.TraceFlags
+	if h.sampled {
+		flags = trace.FlagsSampled
+	}
+
+	return trace.ContextWithRemoteSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: flags, Remote: true}))
+}
+
+// Fields returns the X-Amzn-Trace-Id header
+func (XRayTraceContext) Fields() []string {
+	return []string{awsXRayTraceHeader}
+}
@@ -0,0 +1,306 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Test_remoteSpanContext(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		propagator  propagation.TextMapPropagator
+		headers     map[string]string
+		wantValid   bool
+		wantTraceID string
+		wantSpanID  string
+		wantSampled bool
+	}{
+		{
+			name:        "valid traceparent",
+			propagator:  consolePropagator,
+			headers:     map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			wantValid:   true,
+			wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			wantSpanID:  "00f067aa0ba902b7",
+			wantSampled: true,
+		},
+		{
+			name:       "invalid traceparent",
+			propagator: consolePropagator,
+			headers:    map[string]string{"traceparent": "00-not-a-trace-01"},
+		},
+		{
+			name:       "no traceparent",
+			propagator: consolePropagator,
+		},
+		{
+			name:        "X-Cloud-Trace-Context",
+			propagator:  gcpPropagator,
+			headers:     map[string]string{"X-Cloud-Trace-Context": "105445aa7843bc8bf206b12000100000/1;o=1"},
+			wantValid:   true,
+			wantTraceID: "105445aa7843bc8bf206b12000100000",
+			wantSpanID:  "0000000000000001",
+			wantSampled: true,
+		},
+		{
+			name:       "traceparent takes priority over X-Cloud-Trace-Context",
+			propagator: gcpPropagator,
+			headers: map[string]string{
+				"traceparent":           "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+				"X-Cloud-Trace-Context": "105445aa7843bc8bf206b12000100000/1;o=1",
+			},
+			wantValid:   true,
+			wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			wantSpanID:  "00f067aa0ba902b7",
+		},
+		{
+			name:        "X-Amzn-Trace-Id",
+			propagator:  awsPropagator,
+			headers:     map[string]string{"X-Amzn-Trace-Id": "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1"},
+			wantValid:   true,
+			wantTraceID: "5759e988bd862e3fe1be46a994272793",
+			wantSpanID:  "53995c3f42cd8ad8",
+			wantSampled: true,
+		},
+		{
+			name:       "X-Amzn-Trace-Id takes priority over traceparent",
+			propagator: awsPropagator,
+			headers: map[string]string{
+				"traceparent":     "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+				"X-Amzn-Trace-Id": "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=0",
+			},
+			wantValid:   true,
+			wantTraceID: "5759e988bd862e3fe1be46a994272793",
+			wantSpanID:  "53995c3f42cd8ad8",
+		},
+		{
+			name:        "X-Amzn-Trace-Id without parent",
+			propagator:  awsPropagator,
+			headers:     map[string]string{"X-Amzn-Trace-Id": "Root=1-5759e988-bd862e3fe1be46a994272793"},
+			wantTraceID: "5759e988bd862e3fe1be46a994272793",
+			wantSpanID:  "0000000000000000",
+		},
+		{
+			name:       "X-Amzn-Trace-Id without parent does not replace traceparent",
+			propagator: awsPropagator,
+			headers: map[string]string{
+				"traceparent":     "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+				"X-Amzn-Trace-Id": "Root=1-5759e988-bd862e3fe1be46a994272793",
+			},
+			wantValid:   true,
+			wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			wantSpanID:  "00f067aa0ba902b7",
+			wantSampled: true,
+		},
+		{
+			name:        "B3",
+			propagator:  propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, b3.New()),
+			headers:     map[string]string{"b3": "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1"},
+			wantValid:   true,
+			wantTraceID: "80f198ee56343ba864fe8b2a57d3eff7",
+			wantSpanID:  "e457b5a2e4d86bd1",
+			wantSampled: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			sc := remoteSpanContext(r, tt.propagator)
+			if sc.IsValid() != tt.wantValid {
+				t.Fatalf("remoteSpanContext().IsValid() = %v, want %v", sc.IsValid(), tt.wantValid)
+			}
+			if tt.wantTraceID == "" {
+				return
+			}
+			if got := sc.TraceID().String(); got != tt.wantTraceID {
+				t.Errorf("remoteSpanContext().TraceID() = %v, want %v", got, tt.wantTraceID)
+			}
+			if got := sc.SpanID().String(); got != tt.wantSpanID {
+				t.Errorf("remoteSpanContext().SpanID() = %v, want %v", got, tt.wantSpanID)
+			}
+			if sc.IsSampled() != tt.wantSampled {
+				t.Errorf("remoteSpanContext().IsSampled() = %v, want %v", sc.IsSampled(), tt.wantSampled)
+			}
+			if !sc.IsRemote() {
+				t.Errorf("remoteSpanContext().IsRemote() = false, want true")
+			}
+		})
+	}
+}
+
+func Test_traceIDFromRequest(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	r.Header.Set("X-Amzn-Trace-Id", "Root=1-5759e988-bd862e3fe1be46a994272793")
+
+	// the trace ID of a header without a parent is used, e.g. of the load balancers of AWS
+	if got, want := traceIDFromRequest(r, awsPropagator, generateID), "5759e988bd862e3fe1be46a994272793"; got != want {
+		t.Errorf("traceIDFromRequest() = %v, want %v", got, want)
+	}
+}
+
+func Test_traceSampled(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		traceparent string
+		span        trace.SpanContextConfig
+		want        bool
+	}{
+		{
+			name:        "sampled traceparent",
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			want:        true,
+		},
+		{
+			name:        "unsampled traceparent",
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+		},
+		{
+			name:        "span takes precedence",
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			span: trace.SpanContextConfig{
+				TraceID: trace.TraceID{1},
+				SpanID:  trace.SpanID{1},
+			},
+		},
+		{
+			name: "no trace",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			if tt.traceparent != "" {
+				r.Header.Set("traceparent", tt.traceparent)
+			}
+			if sc := trace.NewSpanContext(tt.span); sc.IsValid() {
+				r = r.WithContext(trace.ContextWithSpanContext(r.Context(), sc))
+			}
+			if got := traceSampled(r, consolePropagator); got != tt.want {
+				t.Errorf("traceSampled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloudTraceContext(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{
+			name:   "sampled",
+			header: "105445aa7843bc8bf206b12000100000/1;o=1",
+			want:   "105445aa7843bc8bf206b12000100000/1;o=1",
+		},
+		{
+			name:   "unsampled",
+			header: "105445aa7843bc8bf206b12000100000/18446744073709551615;o=0",
+			want:   "105445aa7843bc8bf206b12000100000/18446744073709551615;o=0",
+		},
+		{
+			name:   "without options",
+			header: "105445aa7843bc8bf206b12000100000/1",
+			want:   "105445aa7843bc8bf206b12000100000/1;o=0",
+		},
+		{
+			name:   "invalid trace ID",
+			header: "not-a-trace/1;o=1",
+		},
+		{
+			name:   "invalid span ID",
+			header: "105445aa7843bc8bf206b12000100000/span;o=1",
+		},
+		{
+			name:   "without span ID",
+			header: "105445aa7843bc8bf206b12000100000",
+		},
+		{
+			name: "no header",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			in := propagation.MapCarrier{}
+			if tt.header != "" {
+				in.Set(cloudTraceContextHeader, tt.header)
+			}
+			out := propagation.MapCarrier{}
+			CloudTraceContext{}.Inject(CloudTraceContext{}.Extract(context.Background(), in), out)
+			if got := out.Get(cloudTraceContextHeader); got != tt.want {
+				t.Errorf("CloudTraceContext round trip = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestXRayTraceContext(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{
+			name:   "sampled",
+			header: "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1",
+			want:   "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1",
+		},
+		{
+			name:   "unsampled",
+			header: "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=0",
+			want:   "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=0",
+		},
+		{
+			// the span context of a header without a parent is not valid, so it is not injected
+			name:   "without parent",
+			header: "Root=1-5759e988-bd862e3fe1be46a994272793",
+		},
+		{
+			name:   "invalid root",
+			header: "Root=2-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1",
+		},
+		{
+			name: "no header",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			in := propagation.MapCarrier{}
+			if tt.header != "" {
+				in.Set(awsXRayTraceHeader, tt.header)
+			}
+			out := propagation.MapCarrier{}
+			XRayTraceContext{}.Inject(XRayTraceContext{}.Extract(context.Background(), in), out)
+			if got := out.Get(awsXRayTraceHeader); got != tt.want {
+				t.Errorf("XRayTraceContext round trip = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
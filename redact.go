@@ -0,0 +1,157 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// redactedValue replaces the values masked by a Redactor
+const redactedValue = "[REDACTED]"
+
+// defaultRedactKeys are the attribute keys and headers masked by a new Redactor, including the sensitiveHeaders
+var defaultRedactKeys = slices.Concat([]string{"password", "passwd", "secret", "token", "api_key", "apikey", "ssn"}, sensitiveHeaders)
+
+// Redactor masks sensitive data in log entries. It is added to an exporter with Process, e.g.
+//
+//	exporter.Process(logger.NewRedactor().Keys("pin").Processor())
+type Redactor struct {
+	keys     []string
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor returns a Redactor that masks the values of the attributes with a sensitive key,
+// e.g. password, token, or ssn, and the sensitive headers (e.g. Authorization and Cookie) of http.Header values
+func NewRedactor() *Redactor {
+	return &Redactor{keys: slices.Clone(defaultRedactKeys)}
+}
+
+// Keys adds attribute keys that are masked. Keys are case-insensitive and match the full key,
+// or the last segment of a dotted key, e.g. "token" matches "auth.token". The keys also mask
+// the headers of http.Header values.
+func (r *Redactor) Keys(keys ...string) *Redactor {
+	for _, k := range keys {
+		r.keys = append(r.keys, strings.ToLower(k))
+	}
+
+	return r
+}
+
+// Mask adds a pattern that is masked in the message and the string attribute values,
+// e.g. a regexp matching credit card numbers
+func (r *Redactor) Mask(pattern *regexp.Regexp) *Redactor {
+	r.patterns = append(r.patterns, pattern)
+
+	return r
+}
+
+// Processor returns the Processor that masks the entries. It uses the keys and patterns added before it
+// is called, so that Keys and Mask do not race with the entries being masked.
+func (r *Redactor) Processor() Processor {
+	snapshot := &Redactor{keys: slices.Clone(r.keys), patterns: slices.Clone(r.patterns)}
+
+	return func(e Entry) (Entry, bool) {
+		e.Message = snapshot.mask(e.Message)
+		for k, v := range e.Attributes {
+			e.Attributes[k] = snapshot.redact(k, v)
+		}
+
+		return e, true
+	}
+}
+
+// sensitive reports whether the key is masked
+func (r *Redactor) sensitive(key string) bool {
+	key = strings.ToLower(key)
+	if i := strings.LastIndex(key, "."); i >= 0 && slices.Contains(r.keys, key[i+1:]) {
+		return true
+	}
+
+	return slices.Contains(r.keys, key)
+}
+
+// redact returns the value of the attribute with the sensitive values masked
+func (r *Redactor) redact(key string, v any) any {
+	if r.sensitive(key) {
+		return redactedValue
+	}
+
+	return r.redactValue(v)
+}
+
+// redactValue returns v with the sensitive values masked. slog values are resolved, and structs, slices, and
+// maps of other types are masked as their JSON, returning v unchanged when nothing is masked.
+func (r *Redactor) redactValue(v any) any {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return r.mask(t)
+	case http.Header:
+		h := t.Clone()
+		for k := range h {
+			if r.sensitive(k) {
+				h[k] = []string{redactedValue}
+			}
+		}
+
+		return h
+	case map[string]any:
+		m := make(map[string]any, len(t))
+		for k, v := range t {
+			m[k] = r.redact(k, v)
+		}
+
+		return m
+	case []any:
+		s := make([]any, len(t))
+		for i, v := range t {
+			s[i] = r.redactValue(v)
+		}
+
+		return s
+	case slog.Value, slog.LogValuer:
+		return r.redactValue(resolveValue(t))
+	}
+
+	switch reflect.Indirect(reflect.ValueOf(v)).Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return r.redactJSON(v)
+	default:
+		return v
+	}
+}
+
+// redactJSON returns v masked as its decoded JSON, or v when nothing is masked
+func (r *Redactor) redactJSON(v any) any {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var decoded any
+	if err := dec.Decode(&decoded); err != nil {
+		return v
+	}
+
+	if redacted := r.redactValue(decoded); !reflect.DeepEqual(decoded, redacted) {
+		return redacted
+	}
+
+	return v
+}
+
+// mask replaces the matches of the patterns in s
+func (r *Redactor) mask(s string) string {
+	for _, p := range r.patterns {
+		s = p.ReplaceAllString(s, redactedValue)
+	}
+
+	return s
+}
@@ -0,0 +1,129 @@
+package logger
+
+import (
+	"log/slog"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type redactLogValuer struct{}
+
+func (redactLogValuer) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("user", "bob"), slog.String("token", "abc"))
+}
+
+func TestRedactor_Processor(t *testing.T) {
+	t.Parallel()
+
+	type credentials struct {
+		User     string `json:"user"`
+		Password string `json:"password"`
+	}
+
+	tests := []struct {
+		name     string
+		redactor *Redactor
+		entry    Entry
+		want     Entry
+	}{
+		{
+			name:     "default keys",
+			redactor: NewRedactor(),
+			entry: Entry{Message: "login", Attributes: map[string]any{
+				"user":       "bob",
+				"Password":   "secret",
+				"auth.token": "abc",
+				"request": map[string]any{
+					"ssn":  "123-45-6789",
+					"page": 2,
+				},
+				"headers": http.Header{
+					"Authorization": {"Bearer abc"},
+					"Cookie":        {"session=abc"},
+					"Accept":        {"*/*"},
+				},
+			}},
+			want: Entry{Message: "login", Attributes: map[string]any{
+				"user":       "bob",
+				"Password":   "[REDACTED]",
+				"auth.token": "[REDACTED]",
+				"request": map[string]any{
+					"ssn":  "[REDACTED]",
+					"page": 2,
+				},
+				"headers": http.Header{
+					"Authorization": {"[REDACTED]"},
+					"Cookie":        {"[REDACTED]"},
+					"Accept":        {"*/*"},
+				},
+			}},
+		},
+		{
+			name:     "keys",
+			redactor: NewRedactor().Keys("PIN"),
+			entry:    Entry{Message: "login", Attributes: map[string]any{"pin": "1234", "user": "bob"}},
+			want:     Entry{Message: "login", Attributes: map[string]any{"pin": "[REDACTED]", "user": "bob"}},
+		},
+		{
+			name:     "mask",
+			redactor: NewRedactor().Mask(regexp.MustCompile(`\d{4}-\d{4}-\d{4}-\d{4}`)),
+			entry:    Entry{Message: "charged 4111-1111-1111-1111", Attributes: map[string]any{"card": "4111-1111-1111-1111", "amount": 10}},
+			want:     Entry{Message: "charged [REDACTED]", Attributes: map[string]any{"card": "[REDACTED]", "amount": 10}},
+		},
+		{
+			name:     "slog values",
+			redactor: NewRedactor(),
+			entry: Entry{Message: "login", Attributes: map[string]any{
+				"group":  slog.GroupValue(slog.String("user", "bob"), slog.String("secret", "abc")),
+				"valuer": redactLogValuer{},
+			}},
+			want: Entry{Message: "login", Attributes: map[string]any{
+				"group":  map[string]any{"user": "bob", "secret": "[REDACTED]"},
+				"valuer": map[string]any{"user": "bob", "token": "[REDACTED]"},
+			}},
+		},
+		{
+			name:     "structs and slices",
+			redactor: NewRedactor(),
+			entry: Entry{Message: "login", Attributes: map[string]any{
+				"credentials": credentials{User: "bob", Password: "abc"},
+				"users":       []*credentials{{User: "alice", Password: "def"}},
+				"ids":         []int{1, 2},
+			}},
+			want: Entry{Message: "login", Attributes: map[string]any{
+				"credentials": map[string]any{"user": "bob", "password": "[REDACTED]"},
+				"users":       []any{map[string]any{"user": "alice", "password": "[REDACTED]"}},
+				"ids":         []int{1, 2},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := tt.redactor.Processor()(tt.entry)
+			if !ok {
+				t.Errorf("Processor() dropped the entry")
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Processor() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRedactor_Processor_snapshot(t *testing.T) {
+	t.Parallel()
+
+	r := NewRedactor()
+	process := r.Processor()
+	r.Keys("pin")
+
+	got, _ := process(Entry{Attributes: map[string]any{"pin": "1234"}})
+	if want := "1234"; got.Attributes["pin"] != want {
+		t.Errorf("Processor() pin = %v, want %v", got.Attributes["pin"], want)
+	}
+}
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+)
+
+// requestIDKey is the attribute of the request ID in the parent and child logs
+const requestIDKey = "request_id"
+
+// maxRequestIDLength is the maximum length of a request ID propagated from the request header,
+// longer IDs are replaced with a generated one
+const maxRequestIDLength = 128
+
+// requestID returns the request ID from the header of the request, or a generated one when the request
+// has none or it is not valid. It returns false when header is empty, i.e. request IDs are disabled.
+func requestID(r *http.Request, header string) (string, bool) {
+	if header == "" {
+		return "", false
+	}
+	if id := r.Header.Get(header); validRequestID(id) {
+		return id, true
+	}
+
+	return generateID(), true
+}
+
+// validRequestID reports whether id is a non-empty request ID of printable ASCII characters,
+// so a client can not inject control characters in the logs or the response header
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := range len(id) {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newRequestIDContext returns a copy of the context with the request ID, or ctx when id is empty
+func newRequestIDContext(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+
+	return context.WithValue(ctx, requestIDCtxKey, id)
+}
+
+// requestIDFromCtx returns the request ID of the request, or an empty string if there is none
+func requestIDFromCtx(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDCtxKey).(string)
+
+	return id
+}
@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_requestID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		header        string
+		value         string
+		want          string
+		wantGenerated bool
+		wantOK        bool
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name:   "propagated",
+			header: "X-Request-ID",
+			value:  "req-123",
+			want:   "req-123",
+			wantOK: true,
+		},
+		{
+			name:          "generated",
+			header:        "X-Request-ID",
+			wantGenerated: true,
+			wantOK:        true,
+		},
+		{
+			name:          "invalid characters",
+			header:        "X-Request-ID",
+			value:         "req 123\n",
+			wantGenerated: true,
+			wantOK:        true,
+		},
+		{
+			name:          "too long",
+			header:        "X-Request-ID",
+			value:         strings.Repeat("a", maxRequestIDLength+1),
+			wantGenerated: true,
+			wantOK:        true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			if tt.value != "" {
+				r.Header.Set(tt.header, tt.value)
+			}
+
+			got, ok := requestID(r, tt.header)
+			if ok != tt.wantOK {
+				t.Fatalf("requestID() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if tt.wantGenerated {
+				if len(got) != 32 || got == tt.value {
+					t.Errorf("requestID() = %q, want a generated ID", got)
+				}
+
+				return
+			}
+			if got != tt.want {
+				t.Errorf("requestID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogger_RequestID(t *testing.T) {
+	t.Parallel()
+
+	ctx := newRequestIDContext(context.Background(), "req-123")
+	if got := Ctx(ctx).RequestID(); got != "req-123" {
+		t.Errorf("Logger.RequestID() = %q, want %q", got, "req-123")
+	}
+	if got := Ctx(context.Background()).RequestID(); got != "" {
+		t.Errorf("Logger.RequestID() = %q, want empty", got)
+	}
+}
@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"log/slog"
+	"net/http"
+	"path"
+)
+
+// httpRouteKey is the attribute of the parent log with the route template that matched the request
+const httpRouteKey = "http.route"
+
+// RouteConfig overrides the logging options of an exporter for the requests with a matching path.
+// It is created with Route and added to an exporter with Routes.
+type RouteConfig struct {
+	pattern  string
+	logAll   *bool
+	minLevel *slog.Level
+}
+
+// Route returns a RouteConfig for the requests with a path matching the pattern, which uses the
+// syntax of path.Match, e.g. "/api/payments/*"
+func Route(pattern string) *RouteConfig {
+	return &RouteConfig{pattern: pattern}
+}
+
+// LogAll overrides the LogAll option of the exporter for the route, e.g. false to mute a noisy route,
+// or true to log every request of a critical route
+func (c *RouteConfig) LogAll(v bool) *RouteConfig {
+	c.logAll = &v
+
+	return c
+}
+
+// MinLevel overrides the minimum level of the child logs of the route. Component levels set with
+// ComponentLevel or SetLevel take precedence.
+func (c *RouteConfig) MinLevel(level Level) *RouteConfig {
+	c.minLevel = &level
+
+	return c
+}
+
+// matchRoute returns the first route matching the request path, and false when none match
+func matchRoute(routes []RouteConfig, p string) (RouteConfig, bool) {
+	for _, route := range routes {
+		if ok, _ := path.Match(route.pattern, p); ok {
+			return route, true
+		}
+	}
+
+	return RouteConfig{}, false
+}
+
+// cloneRoutes copies the routes, so options changed after the middleware is created are not applied
+func cloneRoutes(routes []*RouteConfig) []RouteConfig {
+	if len(routes) == 0 {
+		return nil
+	}
+
+	c := make([]RouteConfig, 0, len(routes))
+	for _, route := range routes {
+		c = append(c, *route)
+	}
+
+	return c
+}
+
+// RouteMiddleware returns router middleware that records the route template returned by route in the
+// http.route attribute of the parent log. route is called after the handler, when routers like chi have
+// resolved the full pattern, e.g.
+//
+//	r.Use(logger.RouteMiddleware(func(r *http.Request) string {
+//		return chi.RouteContext(r.Context()).RoutePattern()
+//	}))
+//
+// or for gorilla/mux
+//
+//	r.Use(logger.RouteMiddleware(func(r *http.Request) string {
+//		tmpl, _ := mux.CurrentRoute(r).GetPathTemplate()
+//		return tmpl
+//	}))
+func RouteMiddleware(route func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+			Req(r).SetRoute(route(r))
+		})
+	}
+}
+
+// ServeMuxRoute returns a handler serving the requests with mux, that records the pattern matching the
+// request (e.g. "GET /users/{id}") in the http.route attribute of the parent log
+func ServeMuxRoute(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := mux.Handler(r); pattern != "" {
+			Req(r).SetRoute(pattern)
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
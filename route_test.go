@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func Test_matchRoute(t *testing.T) {
+	t.Parallel()
+
+	routes := cloneRoutes([]*RouteConfig{
+		Route("/api/payments/*").LogAll(true).MinLevel(slog.LevelDebug),
+		Route("/api/*").LogAll(false),
+		Route("/api/*").MinLevel(slog.LevelError),
+	})
+	tests := []struct {
+		name         string
+		path         string
+		wantOK       bool
+		wantLogAll   *bool
+		wantMinLevel *slog.Level
+	}{
+		{
+			name:         "payments",
+			path:         "/api/payments/charge",
+			wantOK:       true,
+			wantLogAll:   ptr(true),
+			wantMinLevel: ptr(slog.LevelDebug),
+		},
+		{
+			name:       "first match",
+			path:       "/api/users",
+			wantOK:     true,
+			wantLogAll: ptr(false),
+		},
+		{
+			name: "no match",
+			path: "/healthz",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := matchRoute(routes, tt.path)
+			if ok != tt.wantOK {
+				t.Fatalf("matchRoute() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if (got.logAll == nil) != (tt.wantLogAll == nil) || (got.logAll != nil && *got.logAll != *tt.wantLogAll) {
+				t.Errorf("matchRoute() logAll = %v, want %v", got.logAll, tt.wantLogAll)
+			}
+			if (got.minLevel == nil) != (tt.wantMinLevel == nil) || (got.minLevel != nil && *got.minLevel != *tt.wantMinLevel) {
+				t.Errorf("matchRoute() minLevel = %v, want %v", got.minLevel, tt.wantMinLevel)
+			}
+		})
+	}
+}
+
+func TestServeMuxRoute(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(http.ResponseWriter, *http.Request) {})
+
+	tests := []struct {
+		name    string
+		path    string
+		prepare func(l *MockctxLogger)
+	}{
+		{
+			name: "matched pattern",
+			path: "/users/42",
+			prepare: func(l *MockctxLogger) {
+				l.EXPECT().AddRequestAttribute(httpRouteKey, "GET /users/{id}").Times(1)
+			},
+		},
+		{
+			name:    "not found",
+			path:    "/orders",
+			prepare: func(*MockctxLogger) {},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			l := NewMockctxLogger(gomock.NewController(t))
+			tt.prepare(l)
+			r := httptest.NewRequest(http.MethodGet, tt.path, http.NoBody)
+			ServeMuxRoute(mux).ServeHTTP(httptest.NewRecorder(), r.WithContext(newContext(r.Context(), l)))
+		})
+	}
+}
+
+func TestRouteMiddleware(t *testing.T) {
+	t.Parallel()
+
+	l := NewMockctxLogger(gomock.NewController(t))
+	l.EXPECT().AddRequestAttribute(httpRouteKey, "/users/{id}").Times(1)
+
+	h := RouteMiddleware(func(*http.Request) string { return "/users/{id}" })(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	r := httptest.NewRequest(http.MethodGet, "/users/42", http.NoBody)
+	h.ServeHTTP(httptest.NewRecorder(), r.WithContext(newContext(r.Context(), l)))
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"path"
+	"sync/atomic"
+	"time"
+)
+
+// slowRequestKey is the attribute of parent logs of requests slower than the SlowRequest threshold
+const slowRequestKey = "slow_request"
+
+// slowRequest reports whether the elapsed time of a request reaches the threshold, when positive
+func slowRequest(threshold, elapsed time.Duration) bool {
+	return threshold > 0 && elapsed >= threshold
+}
+
+// sampleRequest reports whether the parent log of the request is written when successful requests are
+// sampled at rate. Requests with an error status, Warning or above logs, or a latency of at least slow
+// (when positive) are always written.
+func sampleRequest(rate float64, slow, elapsed time.Duration, status int, level slog.Level) bool {
+	if rate >= 1 || status >= http.StatusBadRequest || level >= slog.LevelWarn || slowRequest(slow, elapsed) {
+		return true
+	}
+
+	return rand.Float64() < rate
+}
+
+// sampleCountKey is the attribute of the number of requests matching a SamplePath pattern, including the
+// requests whose parent log was not written
+const sampleCountKey = "sample_count"
+
+// pathSample writes the parent log of 1 in n requests with a path matching pattern
+type pathSample struct {
+	pattern string
+	n       uint64
+	count   *atomic.Uint64 // nil until the middleware is created
+}
+
+type pathSamples []pathSample
+
+// clone copies the samples with new counters, so each middleware counts its own requests
+func (s pathSamples) clone() pathSamples {
+	if len(s) == 0 {
+		return nil
+	}
+
+	c := make(pathSamples, 0, len(s))
+	for _, ps := range s {
+		ps.count = &atomic.Uint64{}
+		c = append(c, ps)
+	}
+
+	return c
+}
+
+// sample counts the request with the first sample matching the path, and reports the count, and whether the
+// request is one of the 1 in n written. Requests with a path matching no sample return a zero count and true.
+func (s pathSamples) sample(p string) (count uint64, ok bool) {
+	for _, ps := range s {
+		if matched, _ := path.Match(ps.pattern, p); matched {
+			count = ps.count.Add(1)
+
+			return count, ps.n <= 1 || (count-1)%ps.n == 0
+		}
+	}
+
+	return 0, true
+}
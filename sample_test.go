@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"log/slog"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_sampleRequest(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		rate    float64
+		slow    time.Duration
+		elapsed time.Duration
+		status  int
+		level   slog.Level
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "not sampled",
+			args: args{rate: 1, status: http.StatusOK, level: slog.LevelInfo},
+			want: true,
+		},
+		{
+			name: "successful request dropped",
+			args: args{rate: 0, status: http.StatusOK, level: slog.LevelInfo},
+		},
+		{
+			name: "redirect dropped",
+			args: args{rate: 0, status: http.StatusFound, level: slog.LevelDebug},
+		},
+		{
+			name: "error status",
+			args: args{rate: 0, status: http.StatusNotFound, level: slog.LevelInfo},
+			want: true,
+		},
+		{
+			name: "warning log",
+			args: args{rate: 0, status: http.StatusOK, level: slog.LevelWarn},
+			want: true,
+		},
+		{
+			name: "slow request",
+			args: args{rate: 0, slow: time.Second, elapsed: 2 * time.Second, status: http.StatusOK, level: slog.LevelInfo},
+			want: true,
+		},
+		{
+			name: "fast request",
+			args: args{rate: 0, slow: time.Second, elapsed: time.Millisecond, status: http.StatusOK, level: slog.LevelInfo},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := sampleRequest(tt.args.rate, tt.args.slow, tt.args.elapsed, tt.args.status, tt.args.level); got != tt.want {
+				t.Errorf("sampleRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_slowRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		elapsed   time.Duration
+		want      bool
+	}{
+		{name: "disabled", threshold: 0, elapsed: time.Hour},
+		{name: "fast", threshold: time.Second, elapsed: time.Millisecond},
+		{name: "at threshold", threshold: time.Second, elapsed: time.Second, want: true},
+		{name: "slow", threshold: time.Second, elapsed: 2 * time.Second, want: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := slowRequest(tt.threshold, tt.elapsed); got != tt.want {
+				t.Errorf("slowRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_pathSamples_sample(t *testing.T) {
+	t.Parallel()
+
+	s := pathSamples{
+		{pattern: "/healthz", n: 3},
+		{pattern: "/ready", n: 1},
+	}.clone()
+
+	var got []bool
+	for range 7 {
+		_, ok := s.sample("/healthz")
+		got = append(got, ok)
+	}
+	want := []bool{true, false, false, true, false, false, true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sample(/healthz) = %v, want %v", got, want)
+	}
+	if count, ok := s.sample("/healthz"); count != 8 || ok {
+		t.Errorf("sample(/healthz) = %d, %v, want 8, false", count, ok)
+	}
+	if count, ok := s.sample("/ready"); count != 1 || !ok {
+		t.Errorf("sample(/ready) = %d, %v, want 1, true", count, ok)
+	}
+	if count, ok := s.sample("/api"); count != 0 || !ok {
+		t.Errorf("sample(/api) = %d, %v, want 0, true", count, ok)
+	}
+}
@@ -0,0 +1,32 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// sanitize escapes the control characters in s, e.g. ANSI escape sequences, so that user input written to
+// the console cannot forge log lines or corrupt the terminal. Newlines are replaced with newline, which
+// is either `\n` to keep the log on a single line, or a newline followed by an indentation.
+func sanitize(s, newline string) string {
+	if strings.IndexFunc(s, func(r rune) bool { return r != '\t' && unicode.IsControl(r) }) < 0 {
+		return s
+	}
+
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\n':
+			b.WriteString(newline)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t' || !unicode.IsControl(r):
+			b.WriteRune(r)
+		default:
+			fmt.Fprintf(&b, `\x%02x`, r)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,47 @@
+package logger
+
+import "testing"
+
+func Test_sanitize(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		s       string
+		newline string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "plain",
+			args: args{s: "user logged in\twith tab", newline: `\n`},
+			want: "user logged in\twith tab",
+		},
+		{
+			name: "forged line",
+			args: args{s: "bob\r\n2024/01/01 00:00:00 INFO: admin logged in", newline: `\n`},
+			want: `bob\r\n2024/01/01 00:00:00 INFO: admin logged in`,
+		},
+		{
+			name: "indented lines",
+			args: args{s: "line 1\nline 2", newline: "\n    "},
+			want: "line 1\n    line 2",
+		},
+		{
+			name: "ANSI escape",
+			args: args{s: "\x1b[31mred\x1b[0m\u0085", newline: `\n`},
+			want: `\x1b[31mred\x1b[0m\x85`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := sanitize(tt.args.s, tt.args.newline); got != tt.want {
+				t.Errorf("sanitize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"regexp"
+	"slices"
+)
+
+// SeverityRule sets the level of the log entries that match all of its conditions, e.g. to downgrade the
+// errors of canceled requests:
+//
+//	exporter.SeverityRules(
+//		logger.SeverityRule{MessagePattern: regexp.MustCompile(`context canceled`), Level: logger.LevelInfo},
+//		logger.SeverityRule{Status: http.StatusNotFound, Level: logger.LevelInfo},
+//	)
+type SeverityRule struct {
+	// MessagePattern matches the message of child logs, nil matches any message
+	MessagePattern *regexp.Regexp
+	// ErrorType matches the error.type attribute of child logs of Logger.WithError (e.g. "*net.OpError"), empty
+	// matches any log
+	ErrorType string
+	// Status matches the status of the request log, 0 matches any log
+	Status int
+	// Level is the level of the matching log entries
+	Level Level
+}
+
+// matches reports whether the entry matches all the conditions of the rule
+func (r SeverityRule) matches(e Entry) bool {
+	if r.MessagePattern != nil && (e.Request || !r.MessagePattern.MatchString(e.Message)) {
+		return false
+	}
+	if r.ErrorType != "" {
+		if t, _ := e.Attributes[errorTypeKey].(string); e.Request || t != r.ErrorType {
+			return false
+		}
+	}
+	if r.Status != 0 && (!e.Request || e.Status != r.Status) {
+		return false
+	}
+
+	return true
+}
+
+// severityProcessor returns a Processor that sets the level of the entries to the level of the first rule
+// they match
+func severityProcessor(rules []SeverityRule) Processor {
+	rules = slices.Clone(rules)
+
+	return func(e Entry) (Entry, bool) {
+		for _, r := range rules {
+			if r.matches(e) {
+				e.Level = r.Level
+
+				break
+			}
+		}
+
+		return e, true
+	}
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+)
+
+func Test_severityProcessor(t *testing.T) {
+	t.Parallel()
+
+	process := severityProcessor([]SeverityRule{
+		{MessagePattern: regexp.MustCompile(`context canceled`), Level: LevelInfo},
+		{ErrorType: "*net.OpError", Level: LevelWarn},
+		{Status: http.StatusNotFound, Level: LevelDebug},
+		{MessagePattern: regexp.MustCompile(`^retry`), ErrorType: "*errors.errorString", Level: LevelDebug},
+	})
+
+	tests := []struct {
+		name  string
+		entry Entry
+		want  Level
+	}{
+		{
+			name:  "message pattern",
+			entry: Entry{Level: LevelError, Message: "query: context canceled"},
+			want:  LevelInfo,
+		},
+		{
+			name:  "error type",
+			entry: Entry{Level: LevelError, Message: "dial failed", Attributes: map[string]any{errorTypeKey: "*net.OpError"}},
+			want:  LevelWarn,
+		},
+		{
+			name:  "status",
+			entry: Entry{Level: LevelInfo, Request: true, Status: http.StatusNotFound},
+			want:  LevelDebug,
+		},
+		{
+			name:  "status of a child log",
+			entry: Entry{Level: LevelInfo, Message: "not found", Status: http.StatusNotFound},
+			want:  LevelInfo,
+		},
+		{
+			name:  "message pattern of the request log",
+			entry: Entry{Level: LevelError, Request: true, Status: http.StatusInternalServerError, Attributes: map[string]any{"error": "context canceled"}},
+			want:  LevelError,
+		},
+		{
+			name:  "all conditions",
+			entry: Entry{Level: LevelError, Message: "retry failed", Attributes: map[string]any{errorTypeKey: "*errors.errorString"}},
+			want:  LevelDebug,
+		},
+		{
+			name:  "some conditions",
+			entry: Entry{Level: LevelError, Message: "retry failed"},
+			want:  LevelError,
+		},
+		{
+			name:  "first rule",
+			entry: Entry{Level: LevelError, Message: "context canceled", Attributes: map[string]any{errorTypeKey: "*net.OpError"}},
+			want:  LevelInfo,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := process(tt.entry)
+			if !ok {
+				t.Fatalf("severityProcessor() ok = false, want true")
+			}
+			if got.Level != tt.want {
+				t.Errorf("severityProcessor() level = %v, want %v", got.Level, tt.want)
+			}
+		})
+	}
+}
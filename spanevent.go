@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanEventLevelKey is the attribute of a span event with the level of the child log it mirrors
+const spanEventLevelKey = "log.severity"
+
+// addSpanEvent adds the child log as an event of the span of the context, with the attributes of the log,
+// and sets the status of the span to Error for Error and above logs. It is a no-op when the span is not recording.
+func addSpanEvent(ctx context.Context, level slog.Level, msg string, attributes map[string]any) {
+	if ctx == nil {
+		return
+	}
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(attributes)+1)
+	attrs = append(attrs, attribute.String(spanEventLevelKey, levelName(level)))
+	for k, v := range attributes {
+		attrs = append(attrs, spanAttribute(k, v))
+	}
+	span.AddEvent(msg, trace.WithAttributes(attrs...))
+	if level >= slog.LevelError {
+		span.SetStatus(codes.Error, msg)
+	}
+}
+
+// spanAttribute returns the span attribute of a log attribute, values that are not scalars are strings
+func spanAttribute(key string, value any) attribute.KeyValue {
+	v := slog.AnyValue(value).Resolve()
+	switch v.Kind() {
+	case slog.KindBool:
+		return attribute.Bool(key, v.Bool())
+	case slog.KindInt64:
+		return attribute.Int64(key, v.Int64())
+	case slog.KindFloat64:
+		return attribute.Float64(key, v.Float64())
+	default:
+		return attribute.String(key, v.String())
+	}
+}
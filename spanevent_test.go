@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func Test_addSpanEvent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		level      slog.Level
+		attributes map[string]any
+		wantAttrs  []attribute.KeyValue
+		wantStatus codes.Code
+	}{
+		{
+			name:       "info",
+			level:      slog.LevelInfo,
+			attributes: map[string]any{"user": "42"},
+			wantAttrs:  []attribute.KeyValue{attribute.String(spanEventLevelKey, "INFO"), attribute.String("user", "42")},
+			wantStatus: codes.Unset,
+		},
+		{
+			name:       "error",
+			level:      slog.LevelError,
+			attributes: map[string]any{"retries": 3, "ok": false, "ratio": 0.5},
+			wantAttrs: []attribute.KeyValue{
+				attribute.String(spanEventLevelKey, "ERROR"), attribute.Bool("ok", false), attribute.Float64("ratio", 0.5), attribute.Int64("retries", 3),
+			},
+			wantStatus: codes.Error,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := tracetest.NewSpanRecorder()
+			ctx, span := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test").Start(context.Background(), "request")
+			addSpanEvent(ctx, tt.level, "message", tt.attributes)
+			span.End()
+
+			spans := recorder.Ended()
+			if len(spans) != 1 || len(spans[0].Events()) != 1 {
+				t.Fatalf("addSpanEvent() spans = %v, want 1 span with 1 event", spans)
+			}
+			event := spans[0].Events()[0]
+			if event.Name != "message" {
+				t.Errorf("addSpanEvent() event name = %q, want %q", event.Name, "message")
+			}
+			if diff := cmp.Diff(attributeMap(tt.wantAttrs), attributeMap(event.Attributes)); diff != "" {
+				t.Errorf("addSpanEvent() event attributes mismatch (-want +got):\n%s", diff)
+			}
+			if got := spans[0].Status().Code; got != tt.wantStatus {
+				t.Errorf("addSpanEvent() span status = %v, want %v", got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// attributeMap returns the values of the span attributes by key
+func attributeMap(attrs []attribute.KeyValue) map[string]any {
+	m := make(map[string]any, len(attrs))
+	for _, a := range attrs {
+		m[string(a.Key)] = a.Value.AsInterface()
+	}
+
+	return m
+}
+
+func Test_addSpanEvent_notRecording(t *testing.T) {
+	t.Parallel()
+
+	// the context without a span, or with a nil context, is a no-op
+	addSpanEvent(context.Background(), slog.LevelError, "message", nil)
+	addSpanEvent(nil, slog.LevelError, "message", nil)
+}
@@ -0,0 +1,437 @@
+package logger
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/go-playground/errors/v5"
+)
+
+// keys of the attributes of the child logs of database statements written by SQLDriver
+const (
+	sqlStatementKey = "db.statement"
+	sqlArgsKey      = "db.args"
+	sqlRowsKey      = "db.rows"
+	sqlDurationKey  = "db.duration_ms"
+)
+
+// defaultSQLStatementLength is the number of bytes of the statements logged by a new SQLDriver
+const defaultSQLStatementLength = 1024
+
+// SQLDriver wraps a database/sql driver to write a child log for each statement, with its duration and the
+// number of rows returned or affected, to the logger of the context of the statement. It works with packages
+// built on database/sql, e.g. sqlx. The driver is registered under a new name, e.g.
+//
+//	sql.Register("postgres-logged", logger.NewSQLDriver(&pq.Driver{}))
+//	db, err := sql.Open("postgres-logged", dsn)
+//
+// or wraps a driver.Connector, e.g.
+//
+//	db := sql.OpenDB(logger.NewSQLDriver(drv).Connector(connector))
+//
+// Only statements executed with a context, e.g. QueryContext, are correlated to the request logs.
+type SQLDriver struct {
+	drv        driver.Driver
+	level      Level
+	maxStmtLen int
+	logArgs    bool
+}
+
+// NewSQLDriver returns an SQLDriver that logs the statements of drv
+func NewSQLDriver(drv driver.Driver) *SQLDriver {
+	return &SQLDriver{drv: drv, level: LevelDebug, maxStmtLen: defaultSQLStatementLength}
+}
+
+// Level sets the level of the logs of successful statements. Failed statements are logged as errors
+// (default: LevelDebug)
+func (d *SQLDriver) Level(level Level) *SQLDriver {
+	d.level = level
+
+	return d
+}
+
+// MaxStatementLength sets the number of bytes of the statements and arguments that are logged. Longer
+// statements are truncated, 0 disables truncation (default: 1024)
+func (d *SQLDriver) MaxStatementLength(n int) *SQLDriver {
+	d.maxStmtLen = n
+
+	return d
+}
+
+// LogArgs logs the values of the arguments of the statements, which may contain sensitive data. Otherwise
+// the values are masked (default: false)
+func (d *SQLDriver) LogArgs(v bool) *SQLDriver {
+	d.logArgs = v
+
+	return d
+}
+
+// Open implements driver.Driver
+func (d *SQLDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.drv.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlConn{Conn: c, d: d}, nil
+}
+
+// OpenConnector implements driver.DriverContext
+func (d *SQLDriver) OpenConnector(name string) (driver.Connector, error) {
+	if dc, ok := d.drv.(driver.DriverContext); ok {
+		c, err := dc.OpenConnector(name)
+		if err != nil {
+			return nil, err
+		}
+
+		return d.Connector(c), nil
+	}
+
+	return d.Connector(dsnConnector{name: name, drv: d.drv}), nil
+}
+
+// Connector returns a driver.Connector that logs the statements of the connections of c, for sql.OpenDB
+func (d *SQLDriver) Connector(c driver.Connector) driver.Connector {
+	return &sqlConnector{Connector: c, d: d}
+}
+
+// log writes the child log of the statement. rows is the number of rows returned or affected, or -1 when unknown.
+// driver.ErrSkip is not logged, since database/sql retries the statement with a prepared statement.
+func (d *SQLDriver) log(ctx context.Context, query string, args []driver.NamedValue, begin time.Time, rows int64, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+
+	a := adapterCtx(ctx).WithAttributes().
+		AddAttribute(sqlStatementKey, truncate(query, d.maxStmtLen)).
+		AddAttribute(sqlDurationKey, float64(time.Since(begin))/float64(time.Millisecond))
+	if len(args) > 0 {
+		a.AddAttribute(sqlArgsKey, d.args(args))
+	}
+	if rows >= 0 {
+		a.AddAttribute(sqlRowsKey, rows)
+	}
+	if err != nil {
+		a.Logger().WithError(err).Error("sql statement failed")
+
+		return
+	}
+	a.Logger().Log(d.level, "sql statement")
+}
+
+// args returns the values of the arguments to log, masked unless LogArgs is set
+func (d *SQLDriver) args(args []driver.NamedValue) []any {
+	values := make([]any, len(args))
+	for i, arg := range args {
+		if !d.logArgs {
+			values[i] = redactedValue
+
+			continue
+		}
+		values[i] = truncateValue(arg.Value, d.maxStmtLen)
+	}
+
+	return values
+}
+
+// dsnConnector is the driver.Connector of a driver that does not implement driver.DriverContext
+type dsnConnector struct {
+	name string
+	drv  driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.drv.Open(c.name)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.drv
+}
+
+type sqlConnector struct {
+	driver.Connector
+	d *SQLDriver
+}
+
+func (c *sqlConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlConn{Conn: conn, d: c.d}, nil
+}
+
+func (c *sqlConnector) Driver() driver.Driver {
+	return c.d
+}
+
+// sqlConn logs the statements of a connection. The optional interfaces of the connection that are not implemented
+// by the wrapped connection return driver.ErrSkip, or the default of database/sql, so its behavior is unchanged.
+// The errors of the wrapped connection are returned as is, since database/sql and applications compare them.
+type sqlConn struct {
+	driver.Conn
+	d *SQLDriver
+}
+
+func (c *sqlConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *sqlConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var s driver.Stmt
+	var err error
+	if cp, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		s, err = cp.PrepareContext(ctx, query)
+	} else {
+		s, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqlStmt{Stmt: s, query: query, d: c.d}, nil
+}
+
+func (c *sqlConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ec, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	begin := time.Now()
+	res, err := ec.ExecContext(ctx, query, args)
+	c.d.log(ctx, query, args, begin, rowsAffected(res, err), err)
+
+	return res, err
+}
+
+func (c *sqlConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	qc, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	begin := time.Now()
+	rows, err := qc.QueryContext(ctx, query, args)
+	if err != nil {
+		c.d.log(ctx, query, args, begin, -1, err)
+
+		return nil, err
+	}
+
+	return &sqlRows{Rows: rows, ctx: ctx, query: query, args: args, begin: begin, d: c.d}, nil
+}
+
+func (c *sqlConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if cb, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return cb.BeginTx(ctx, opts)
+	}
+	if opts.Isolation != 0 || opts.ReadOnly {
+		return nil, errors.New("sql: driver does not support non-default isolation level or read-only transactions")
+	}
+
+	return c.Conn.Begin()
+}
+
+func (c *sqlConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+
+	return nil
+}
+
+func (c *sqlConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+
+	return nil
+}
+
+func (c *sqlConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+
+	return true
+}
+
+func (c *sqlConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if nc, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(nv)
+	}
+
+	return driver.ErrSkip
+}
+
+// sqlStmt logs the executions of a prepared statement
+type sqlStmt struct {
+	driver.Stmt
+	query string
+	d     *SQLDriver
+}
+
+func (s *sqlStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	begin := time.Now()
+	var res driver.Result
+	var err error
+	if se, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = se.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = driverValues(args); err == nil {
+			res, err = s.Stmt.Exec(values)
+		}
+	}
+	s.d.log(ctx, s.query, args, begin, rowsAffected(res, err), err)
+
+	return res, err
+}
+
+func (s *sqlStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	begin := time.Now()
+	var rows driver.Rows
+	var err error
+	if sq, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = sq.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = driverValues(args); err == nil {
+			rows, err = s.Stmt.Query(values)
+		}
+	}
+	if err != nil {
+		s.d.log(ctx, s.query, args, begin, -1, err)
+
+		return nil, err
+	}
+
+	return &sqlRows{Rows: rows, ctx: ctx, query: s.query, args: args, begin: begin, d: s.d}, nil
+}
+
+func (s *sqlStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if nc, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return nc.CheckNamedValue(nv)
+	}
+
+	return driver.ErrSkip
+}
+
+// sqlRows counts the rows returned by a query, and logs the query when the rows are closed, so the duration
+// includes reading the rows
+type sqlRows struct {
+	driver.Rows
+	ctx   context.Context
+	query string
+	args  []driver.NamedValue
+	begin time.Time
+	d     *SQLDriver
+	count int64
+	err   error
+}
+
+func (r *sqlRows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	switch {
+	case err == nil:
+		r.count++
+	case !errors.Is(err, io.EOF):
+		r.err = err
+	}
+
+	return err
+}
+
+func (r *sqlRows) Close() error {
+	err := r.Rows.Close()
+	r.d.log(r.ctx, r.query, r.args, r.begin, r.count, r.err)
+
+	return err
+}
+
+func (r *sqlRows) HasNextResultSet() bool {
+	if rs, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return rs.HasNextResultSet()
+	}
+
+	return false
+}
+
+func (r *sqlRows) NextResultSet() error {
+	if rs, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return rs.NextResultSet()
+	}
+
+	return io.EOF
+}
+
+func (r *sqlRows) ColumnTypeScanType(index int) reflect.Type {
+	if ct, ok := r.Rows.(driver.RowsColumnTypeScanType); ok {
+		return ct.ColumnTypeScanType(index)
+	}
+
+	return reflect.TypeFor[any]()
+}
+
+func (r *sqlRows) ColumnTypeDatabaseTypeName(index int) string {
+	if ct, ok := r.Rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return ct.ColumnTypeDatabaseTypeName(index)
+	}
+
+	return ""
+}
+
+func (r *sqlRows) ColumnTypeLength(index int) (int64, bool) {
+	if ct, ok := r.Rows.(driver.RowsColumnTypeLength); ok {
+		return ct.ColumnTypeLength(index)
+	}
+
+	return 0, false
+}
+
+func (r *sqlRows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	if ct, ok := r.Rows.(driver.RowsColumnTypeNullable); ok {
+		return ct.ColumnTypeNullable(index)
+	}
+
+	return false, false
+}
+
+func (r *sqlRows) ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool) {
+	if ct, ok := r.Rows.(driver.RowsColumnTypePrecisionScale); ok {
+		return ct.ColumnTypePrecisionScale(index)
+	}
+
+	return 0, 0, false
+}
+
+// rowsAffected returns the number of rows affected by a statement, or -1 when it failed or is unknown
+func rowsAffected(res driver.Result, err error) int64 {
+	if err != nil || res == nil {
+		return -1
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return -1
+	}
+
+	return n
+}
+
+// driverValues converts the arguments for drivers without context support, which do not support named arguments
+func driverValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("sql: driver does not support the use of Named Parameters")
+		}
+		values[i] = arg.Value
+	}
+
+	return values, nil
+}
@@ -0,0 +1,179 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+var errTestSQL = errors.New("syntax error")
+
+type testSQLDriver struct{}
+
+func (testSQLDriver) Open(string) (driver.Conn, error) {
+	return &testSQLConn{}, nil
+}
+
+type testSQLConn struct{}
+
+func (*testSQLConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (*testSQLConn) Close() error {
+	return nil
+}
+
+func (*testSQLConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (*testSQLConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if query == "bad" {
+		return nil, errTestSQL
+	}
+
+	return driver.RowsAffected(3), nil
+}
+
+func (*testSQLConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return &testSQLRows{n: 2}, nil
+}
+
+type testSQLRows struct {
+	n int
+}
+
+func (*testSQLRows) Columns() []string {
+	return []string{"id"}
+}
+
+func (*testSQLRows) Close() error {
+	return nil
+}
+
+func (r *testSQLRows) Next(dest []driver.Value) error {
+	if r.n == 0 {
+		return io.EOF
+	}
+	r.n--
+	dest[0] = int64(r.n)
+
+	return nil
+}
+
+func TestSQLDriver(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		logArgs bool
+		run     func(ctx context.Context, db *sql.DB) error
+		want    map[string]any
+	}{
+		{
+			name: "exec",
+			run: func(ctx context.Context, db *sql.DB) error {
+				_, err := db.ExecContext(ctx, "UPDATE users SET name = $1", "secret")
+
+				return err
+			},
+			want: map[string]any{
+				"level": "INFO", "msg": "sql statement", sqlStatementKey: "UPDATE users SET name = $1",
+				sqlArgsKey: []any{redactedValue}, sqlRowsKey: float64(3),
+			},
+		},
+		{
+			name:    "exec with args",
+			logArgs: true,
+			run: func(ctx context.Context, db *sql.DB) error {
+				_, err := db.ExecContext(ctx, "UPDATE users SET name = $1", "alice")
+
+				return err
+			},
+			want: map[string]any{
+				"level": "INFO", "msg": "sql statement", sqlStatementKey: "UPDATE users SET name = $1",
+				sqlArgsKey: []any{"alice"}, sqlRowsKey: float64(3),
+			},
+		},
+		{
+			name: "query",
+			run: func(ctx context.Context, db *sql.DB) error {
+				rows, err := db.QueryContext(ctx, "SELECT id FROM users")
+				if err != nil {
+					return err
+				}
+				defer rows.Close()
+				for rows.Next() {
+					var id int64
+					if err := rows.Scan(&id); err != nil {
+						return err
+					}
+				}
+
+				return rows.Err()
+			},
+			want: map[string]any{
+				"level": "INFO", "msg": "sql statement", sqlStatementKey: "SELECT id FROM users", sqlRowsKey: float64(2),
+			},
+		},
+		{
+			name: "error",
+			run: func(ctx context.Context, db *sql.DB) error {
+				if _, err := db.ExecContext(ctx, "bad"); !errors.Is(err, errTestSQL) {
+					return errors.New("error of the driver not returned")
+				}
+
+				return nil
+			},
+			want: map[string]any{
+				"level": "ERROR", "msg": "sql statement failed", sqlStatementKey: "bad",
+				errorMessageKey: errTestSQL.Error(), errorTypeKey: "*errors.errorString",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db := sql.OpenDB(NewSQLDriver(testSQLDriver{}).Level(LevelInfo).LogArgs(tt.logArgs).Connector(dsnConnector{drv: testSQLDriver{}}))
+			defer db.Close()
+
+			var buf bytes.Buffer
+			handler := NewConsoleExporter().Writer(&buf).Format(JSONFormat).Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				if err := tt.run(r.Context(), db); err != nil {
+					t.Errorf("run() error = %v", err)
+				}
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(lines) != 2 {
+				t.Fatalf("SQLDriver output = %q, want 2 lines", buf.String())
+			}
+			var got map[string]any
+			if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if _, ok := got[sqlDurationKey].(float64); !ok {
+				t.Errorf("SQLDriver %s = %v, want float64", sqlDurationKey, got[sqlDurationKey])
+			}
+			ignore := cmpopts.IgnoreMapEntries(func(k string, _ any) bool { return k == "time" || k == "traceID" || k == sqlDurationKey })
+			if diff := cmp.Diff(tt.want, got, ignore); diff != "" {
+				t.Errorf("SQLDriver log mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
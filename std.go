@@ -3,61 +3,202 @@ package logger
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const stdRequestGroup = "request"
+
+// FallbackMode controls what Ctx and Req do when the context does not contain a request logger,
+// which happens when the code runs before (or without) the middleware
+type FallbackMode int32
+
+const (
+	// FallbackLog writes the logs to the fallback handler (default)
+	FallbackLog FallbackMode = iota
+
+	// FallbackWarn writes a warning that the request logger is missing to the fallback handler,
+	// the first time it is missing, followed by the logs
+	FallbackWarn
+
+	// FallbackPanic panics, which is useful to catch missing middleware in tests. Only Ctx and Req
+	// panic, the adapters of this package (e.g. Transport, SQLDriver, NewLogSink) write to the fallback.
+	FallbackPanic
 )
 
+// stdErrSlog writes the logs of the stdErrLogger when no fallback handler is set
+var stdErrSlog = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+	Level: slog.LevelDebug,
+	ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+		if level, ok := a.Value.Any().(slog.Level); ok && len(groups) == 0 && a.Key == slog.LevelKey {
+			a.Value = slog.StringValue(levelName(level))
+		}
+
+		return a
+	},
+}))
+
+var (
+	fallbackSlog   atomic.Pointer[slog.Logger]
+	fallbackMode   atomic.Int32
+	fallbackWarned atomic.Bool // the FallbackWarn warning was written
+)
+
+// SetFallback sets the handler that logs are written to when the context does not contain a
+// request logger, e.g. to route them to the same backend as the exporter. A nil handler restores
+// the default, which writes text logs to os.Stderr.
+func SetFallback(h slog.Handler) {
+	if h == nil {
+		fallbackSlog.Store(nil)
+
+		return
+	}
+
+	fallbackSlog.Store(slog.New(h))
+}
+
+// SetFallbackMode controls what Ctx and Req do when the context does not contain a request logger (default: FallbackLog)
+func SetFallbackMode(m FallbackMode) {
+	fallbackMode.Store(int32(m))
+	fallbackWarned.Store(false)
+}
+
+// fallbackLogger returns the logger used when the context does not contain a request logger
+func fallbackLogger() *stdErrLogger {
+	l := newStdErrLogger()
+	switch FallbackMode(fallbackMode.Load()) {
+	case FallbackPanic:
+		panic("logger: request logger not found in context, the logger middleware did not run")
+	case FallbackWarn:
+		if fallbackWarned.CompareAndSwap(false, true) {
+			l.logger.Warn("logger: request logger not found in context, the logger middleware did not run")
+		}
+	}
+
+	return l
+}
+
 type stdErrLogger struct {
+	logger     *slog.Logger
+	attributes map[string]any // attributes for child (trace) logs
+	req        *stdRequest    // shared by the logger and its children
+}
+
+// stdRequest contains the request attributes of a stdErrLogger. Since there is no
+// parent request log, they are written with every log instead.
+type stdRequest struct {
+	mu         sync.Mutex
 	attributes map[string]any
 }
 
 // newStdErrLogger returns a new stdErrLogger
 func newStdErrLogger() *stdErrLogger {
-	return &stdErrLogger{attributes: map[string]any{}}
+	lg := fallbackSlog.Load()
+	if lg == nil {
+		lg = stdErrSlog
+	}
+
+	return &stdErrLogger{
+		logger:     lg,
+		attributes: map[string]any{},
+		req:        &stdRequest{attributes: map[string]any{}},
+	}
+}
+
+// newChild returns a new child stdErrLogger
+func (l *stdErrLogger) newChild() *stdErrLogger {
+	return &stdErrLogger{
+		logger:     l.logger,
+		attributes: map[string]any{},
+		req:        l.req,
+	}
+}
+
+// Trace logs a trace message.
+func (l *stdErrLogger) Trace(ctx context.Context, v any) {
+	l.log(ctx, LevelTrace, fmt.Sprint(v))
+}
+
+// Tracef logs a trace message with format.
+func (l *stdErrLogger) Tracef(ctx context.Context, format string, v ...any) {
+	l.log(ctx, LevelTrace, fmt.Sprintf(format, v...))
 }
 
 // Debug logs a debug message.
-func (l *stdErrLogger) Debug(_ context.Context, v any) {
-	l.std("DEBUG", fmt.Sprint(v))
+func (l *stdErrLogger) Debug(ctx context.Context, v any) {
+	l.log(ctx, slog.LevelDebug, fmt.Sprint(v))
 }
 
 // Debugf logs a debug message with format.
-func (l *stdErrLogger) Debugf(_ context.Context, format string, v ...any) {
-	l.std("DEBUG", fmt.Sprintf(format, v...))
+func (l *stdErrLogger) Debugf(ctx context.Context, format string, v ...any) {
+	l.log(ctx, slog.LevelDebug, fmt.Sprintf(format, v...))
 }
 
 // Info logs a info message.
-func (l *stdErrLogger) Info(_ context.Context, v any) {
-	l.std("INFO ", fmt.Sprint(v))
+func (l *stdErrLogger) Info(ctx context.Context, v any) {
+	l.log(ctx, slog.LevelInfo, fmt.Sprint(v))
 }
 
 // Infof logs a info message with format.
-func (l *stdErrLogger) Infof(_ context.Context, format string, v ...any) {
-	l.std("INFO ", fmt.Sprintf(format, v...))
+func (l *stdErrLogger) Infof(ctx context.Context, format string, v ...any) {
+	l.log(ctx, slog.LevelInfo, fmt.Sprintf(format, v...))
 }
 
 // Warn logs a warning message.
-func (l *stdErrLogger) Warn(_ context.Context, v any) {
-	l.std("WARN ", fmt.Sprint(v))
+func (l *stdErrLogger) Warn(ctx context.Context, v any) {
+	l.log(ctx, slog.LevelWarn, fmt.Sprint(v))
 }
 
 // Warnf logs a warning message with format.
-func (l *stdErrLogger) Warnf(_ context.Context, format string, v ...any) {
-	l.std("WARN ", fmt.Sprintf(format, v...))
+func (l *stdErrLogger) Warnf(ctx context.Context, format string, v ...any) {
+	l.log(ctx, slog.LevelWarn, fmt.Sprintf(format, v...))
 }
 
 // Error logs an error message.
-func (l *stdErrLogger) Error(_ context.Context, v any) {
-	l.std("ERROR", fmt.Sprint(v))
+func (l *stdErrLogger) Error(ctx context.Context, v any) {
+	l.log(ctx, slog.LevelError, fmt.Sprint(v))
 }
 
 // Errorf logs an error message with format.
-func (l *stdErrLogger) Errorf(_ context.Context, format string, v ...any) {
-	l.std("ERROR", fmt.Sprintf(format, v...))
+func (l *stdErrLogger) Errorf(ctx context.Context, format string, v ...any) {
+	l.log(ctx, slog.LevelError, fmt.Sprintf(format, v...))
+}
+
+// Critical logs a critical message.
+func (l *stdErrLogger) Critical(ctx context.Context, v any) {
+	l.log(ctx, LevelCritical, fmt.Sprint(v))
+}
+
+// Criticalf logs a critical message with format.
+func (l *stdErrLogger) Criticalf(ctx context.Context, format string, v ...any) {
+	l.log(ctx, LevelCritical, fmt.Sprintf(format, v...))
+}
+
+// Enabled reports whether logs at the level are written by the fallback handler
+func (l *stdErrLogger) Enabled(ctx context.Context, level Level) bool {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return l.logger.Enabled(ctx, level)
 }
 
+// Flush is a no-op, the std logs are not buffered
+func (l *stdErrLogger) Flush() {}
+
 // AddRequestAttribute adds an attribute (key, value) for the parent request log
-// For this std logger, there is no parent request log, so this is a no-op
-func (l *stdErrLogger) AddRequestAttribute(_ string, _ any) {}
+// For this std logger, there is no parent request log, so the request attributes
+// are written in the "request" group of every log of the logger and its children
+// If the key already exists, its value is overwritten
+func (l *stdErrLogger) AddRequestAttribute(key string, value any) {
+	l.req.mu.Lock()
+	defer l.req.mu.Unlock()
+	l.req.attributes[key] = value
+}
 
 // WithAttributes returns an attributer that can be used to add child (trace) log attributes
 func (l *stdErrLogger) WithAttributes() attributer {
@@ -69,17 +210,44 @@ func (l *stdErrLogger) WithAttributes() attributer {
 	return &stdAttributer{logger: l, attributes: attrs}
 }
 
+// Named returns a child ctxLogger for the component
+func (l *stdErrLogger) Named(name string) ctxLogger {
+	child := l.newChild()
+	for k, v := range l.attributes {
+		child.attributes[k] = v
+	}
+	parent, _ := l.attributes[componentKey].(string)
+	child.attributes[componentKey] = componentName(parent, name)
+
+	return child
+}
+
 // TraceID returns an empty string for the std logger
 func (l *stdErrLogger) TraceID() string {
 	return ""
 }
 
-func (l *stdErrLogger) std(level, msg string) {
-	for k, v := range l.attributes {
-		msg += fmt.Sprintf(", %s=%v", k, v)
+// RawTraceID returns an empty string for the std logger
+func (l *stdErrLogger) RawTraceID() string {
+	return ""
+}
+
+func (l *stdErrLogger) log(ctx context.Context, level slog.Level, msg string) {
+	attrs := consoleAttributes(l.attributes)
+	if l.req != nil {
+		l.req.mu.Lock()
+		if len(l.req.attributes) > 0 {
+			attrs = append(attrs, slog.Attr{Key: stdRequestGroup, Value: slog.GroupValue(consoleAttributes(l.req.attributes)...)})
+		}
+		l.req.mu.Unlock()
+	}
+	if ctx != nil {
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			attrs = append(attrs, slog.String(cslTraceID, sc.TraceID().String()), slog.String(cslSpanID, sc.SpanID().String()))
+		}
 	}
 
-	log.Printf(level+": %s", msg)
+	l.logger.LogAttrs(ctx, level, msg, attrs...)
 }
 
 type stdAttributer struct {
@@ -95,7 +263,7 @@ func (a *stdAttributer) AddAttribute(key string, value any) {
 
 // Logger returns a ctxLogger with the child (trace) attributes embedded
 func (a *stdAttributer) Logger() ctxLogger {
-	l := newStdErrLogger()
+	l := a.logger.newChild()
 	for k, v := range a.attributes {
 		l.attributes[k] = v
 	}
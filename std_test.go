@@ -4,19 +4,19 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"log"
-	"os"
+	"io"
+	"log/slog"
+	"net/http"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func Test_stdErrLogger(t *testing.T) {
-	var buf bytes.Buffer
-	log.SetOutput(&buf)
-	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	t.Parallel()
 
 	type args struct {
 		v  []any
@@ -42,21 +42,25 @@ func Test_stdErrLogger(t *testing.T) {
 				v2: "Message",
 			},
 			attributes: map[string]any{"test_key_1": "test_value_1", "test_key_2": "test_value_2"},
-			wantDebug:  "DEBUG: Message",
-			wantDebugf: "DEBUG: Formatted Message",
-			wantInfo:   "INFO : Message",
-			wantInfof:  "INFO : Formatted Message",
-			wantWarn:   "WARN : Message",
-			wantWarnf:  "WARN : Formatted Message",
-			wantError:  "ERROR: Message",
-			wantErrorf: "ERROR: Formatted Message",
+			wantDebug:  "level=DEBUG msg=Message",
+			wantDebugf: `level=DEBUG msg="Formatted Message"`,
+			wantInfo:   "level=INFO msg=Message",
+			wantInfof:  `level=INFO msg="Formatted Message"`,
+			wantWarn:   "level=WARN msg=Message",
+			wantWarnf:  `level=WARN msg="Formatted Message"`,
+			wantError:  "level=ERROR msg=Message",
+			wantErrorf: `level=ERROR msg="Formatted Message"`,
 		},
 	}
 	for _, tt := range tests {
+		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
 			ctx := context.Background()
 
-			l := &stdErrLogger{attributes: tt.attributes}
+			l := &stdErrLogger{logger: stdTestLogger(&buf), attributes: tt.attributes}
 			format := "Formatted %s"
 
 			verifyLog := func(log, methodName, expectedPrefix string) {
@@ -77,40 +81,76 @@ func Test_stdErrLogger(t *testing.T) {
 			}
 
 			l.Debug(ctx, tt.args.v2)
-			verifyLog(buf.String()[20:], "Debug", tt.wantDebug)
+			verifyLog(buf.String(), "Debug", tt.wantDebug)
 			buf.Reset()
 
 			l.Debugf(ctx, format, tt.args.v...)
-			verifyLog(buf.String()[20:], "Debugf", tt.wantDebugf)
+			verifyLog(buf.String(), "Debugf", tt.wantDebugf)
 			buf.Reset()
 
 			l.Info(ctx, tt.args.v2)
-			verifyLog(buf.String()[20:], "Info", tt.wantInfo)
+			verifyLog(buf.String(), "Info", tt.wantInfo)
 			buf.Reset()
 
 			l.Infof(ctx, format, tt.args.v...)
-			verifyLog(buf.String()[20:], "Infof", tt.wantInfof)
+			verifyLog(buf.String(), "Infof", tt.wantInfof)
 			buf.Reset()
 
 			l.Warn(ctx, tt.args.v2)
-			verifyLog(buf.String()[20:], "Warn", tt.wantWarn)
+			verifyLog(buf.String(), "Warn", tt.wantWarn)
 			buf.Reset()
 
 			l.Warnf(ctx, format, tt.args.v...)
-			verifyLog(buf.String()[20:], "Warnf", tt.wantWarnf)
+			verifyLog(buf.String(), "Warnf", tt.wantWarnf)
 			buf.Reset()
 
 			l.Error(ctx, tt.args.v2)
-			verifyLog(buf.String()[20:], "Error", tt.wantError)
+			verifyLog(buf.String(), "Error", tt.wantError)
 			buf.Reset()
 
 			l.Errorf(ctx, format, tt.args.v...)
-			verifyLog(buf.String()[20:], "Errorf", tt.wantErrorf)
+			verifyLog(buf.String(), "Errorf", tt.wantErrorf)
 			buf.Reset()
 		})
 	}
 }
 
+func Test_stdErrLogger_AddRequestAttribute(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	l := newStdErrLogger()
+	l.logger = stdTestLogger(&buf)
+	l.AddRequestAttribute("test_req_key", "test_req_value")
+	child := l.WithAttributes().Logger()
+	child.AddRequestAttribute("test_req_key_2", 2)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	})
+	l.Info(trace.ContextWithSpanContext(context.Background(), sc), "Message")
+
+	want := "level=INFO msg=Message request.test_req_key=test_req_value request.test_req_key_2=2 traceID=4bf92f3577b34da6a3ce929d0e0e4736 spanID=00f067aa0ba902b7\n"
+	if buf.String() != want {
+		t.Errorf("stdErrLogger.Info() = %q, want %q", buf.String(), want)
+	}
+}
+
+// stdTestLogger returns a slog.Logger that writes to w without timestamps
+func stdTestLogger(w io.Writer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}))
+}
+
 func Test_stdErrLogger_WithAttributes(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -228,11 +268,13 @@ func Test_stdAttributer_Logger(t *testing.T) {
 			fields: fields{
 				logger: &stdErrLogger{
 					attributes: map[string]any{"test_key_1": "test_value_1", "test_key_2": "test_value_2"},
+					req:        &stdRequest{attributes: map[string]any{"test_req_key": "test_req_value"}},
 				},
 				attributes: map[string]any{"test_key_3": "test_value_3", "test_key_4": "test_value_4"},
 			},
 			want: &stdErrLogger{
 				attributes: map[string]any{"test_key_3": "test_value_3", "test_key_4": "test_value_4"},
+				req:        &stdRequest{attributes: map[string]any{"test_req_key": "test_req_value"}},
 			},
 		},
 	}
@@ -246,9 +288,77 @@ func Test_stdAttributer_Logger(t *testing.T) {
 			}
 
 			got := a.Logger()
-			if diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(stdErrLogger{})); diff != "" {
+			if diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(stdErrLogger{}, stdRequest{}), cmpopts.IgnoreFields(stdRequest{}, "mu")); diff != "" {
 				t.Errorf("stdAttributer.Logger() mismatch (-want +got):\n%s", diff)
 			}
 		})
 	}
 }
+
+func TestSetFallback(t *testing.T) {
+	var buf bytes.Buffer
+	SetFallback(slog.NewTextHandler(&buf, nil))
+	t.Cleanup(func() {
+		SetFallback(nil)
+		SetFallbackMode(FallbackLog)
+	})
+
+	Ctx(context.Background()).Info("Message")
+	if want := "level=INFO msg=Message\n"; !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("Ctx().Info() = %q, missing suffix %q", buf.String(), want)
+	}
+	buf.Reset()
+
+	SetFallbackMode(FallbackWarn)
+	Req(&http.Request{}).Info("Message")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "level=WARN") || !strings.HasSuffix(lines[1], "level=INFO msg=Message") {
+		t.Errorf("Req().Info() = %q, want warning followed by log", buf.String())
+	}
+	buf.Reset()
+	Req(&http.Request{}).Info("Message")
+	if want := "level=INFO msg=Message\n"; strings.Count(buf.String(), "\n") != 1 || !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("Req().Info() = %q, want log without warning", buf.String())
+	}
+
+	SetFallbackMode(FallbackPanic)
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Ctx() did not panic with FallbackPanic")
+			}
+		}()
+		Ctx(context.Background())
+	}()
+	buf.Reset()
+	if _, err := StdWriterAt(context.Background(), slog.LevelInfo).Write([]byte("Message\n")); err != nil || !strings.Contains(buf.String(), "msg=Message") {
+		t.Errorf("StdWriterAt().Write() = %q, %v, want log without panic", buf.String(), err)
+	}
+
+	SetFallback(nil)
+	SetFallbackMode(FallbackLog)
+	if l, ok := fromCtx(context.Background()).(*stdErrLogger); !ok || l.logger != stdErrSlog {
+		t.Errorf("fromCtx() did not restore the default fallback")
+	}
+}
+
+func TestCtx_fallbackRequestAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	SetFallback(slog.NewTextHandler(&buf, nil))
+	t.Cleanup(func() { SetFallback(nil) })
+
+	ctx := context.Background()
+	Ctx(ctx).AddRequestAttribute("user", "alice")
+	Ctx(ctx).Info("Message")
+	if strings.Contains(buf.String(), "request.user=alice") {
+		t.Errorf("Ctx().Info() = %q, want request attributes of a separate fallback logger", buf.String())
+	}
+	buf.Reset()
+
+	ctx = NewCtx(ctx, Ctx(ctx))
+	Ctx(ctx).AddRequestAttribute("user", "alice")
+	Ctx(ctx).Info("Message")
+	if want := "request.user=alice"; !strings.Contains(buf.String(), want) {
+		t.Errorf("Ctx().Info() = %q, missing %q", buf.String(), want)
+	}
+}
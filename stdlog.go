@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+)
+
+// stdlogPkg is the function name prefix of the log package, whose frames are skipped to find the caller
+const stdlogPkg = "log."
+
+// StdWriterAt returns an io.Writer that writes each write as a child log at the level, with the logger of the
+// context (see Ctx), so the standard library logs of the request are correlated to the request log, e.g.
+//
+//	legacy := log.New(logger.StdWriterAt(r.Context(), logger.LevelWarn), "", 0)
+//	legacy.Printf("retrying %s", id)
+//
+// The trailing newline of the write is removed. Writes outside of a request are written to the fallback
+// handler, e.g. those of the http.Server.ErrorLog (see StdLoggerAt), which has no request context. The caller
+// written with IncludeCaller is the caller of the log.Logger.
+func StdWriterAt(ctx context.Context, level Level) io.Writer {
+	return &stdWriter{l: adapterCtx(ctx).withCaller(0, stdlogPkg), level: level}
+}
+
+// StdLoggerAt returns a log.Logger that writes each log as a child log at the level with the logger of the
+// context, e.g. for http.Server.ErrorLog. The date and time of the log are written by the logger, so the
+// log.Logger has no flags.
+func StdLoggerAt(ctx context.Context, level Level) *log.Logger {
+	return log.New(StdWriterAt(ctx, level), "", 0)
+}
+
+// stdWriter is the io.Writer returned by StdWriterAt
+type stdWriter struct {
+	l     *Logger
+	level Level
+}
+
+// Write writes p as a child log, it never returns an error
+func (w *stdWriter) Write(p []byte) (int, error) {
+	w.l.Log(w.level, string(bytes.TrimSuffix(p, []byte("\n"))))
+
+	return len(p), nil
+}
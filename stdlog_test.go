@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStdWriterAt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		level   Level
+		write   string
+		wantLog string
+	}{
+		{name: "trailing newline", level: LevelWarn, write: "retrying 42\n", wantLog: "Warn: retrying 42, testCtxValue"},
+		{name: "no trailing newline", level: LevelError, write: "http: TLS handshake error", wantLog: "Error: http: TLS handshake error, testCtxValue"},
+		{name: "only last newline", level: LevelInfo, write: "line 1\nline 2\n", wantLog: "Info: line 1\nline 2, testCtxValue"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			ctxLgr := &testCtxLogger{buf: &buf}
+			ctx := newContext(context.WithValue(context.Background(), ctxLgr, " testCtxValue"), ctxLgr)
+
+			n, err := StdWriterAt(ctx, tt.level).Write([]byte(tt.write))
+			if err != nil || n != len(tt.write) {
+				t.Fatalf("stdWriter.Write() = %d, %v, want %d, nil", n, err, len(tt.write))
+			}
+			if s := buf.String(); s != tt.wantLog {
+				t.Errorf("stdWriter.Write() log = %q, want %q", s, tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestStdLoggerAt(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	ctxLgr := &testCtxLogger{buf: &buf}
+	ctx := newContext(context.WithValue(context.Background(), ctxLgr, " testCtxValue"), ctxLgr)
+
+	StdLoggerAt(ctx, LevelWarn).Printf("retrying %d", 42)
+	if s, want := buf.String(), "Warn: retrying 42, testCtxValue"; s != want {
+		t.Errorf("StdLoggerAt().Printf() log = %q, want %q", s, want)
+	}
+}
+
+func TestStdLoggerAt_caller(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	h := NewConsoleExporter().Writer(&buf).Format(JSONFormat).IncludeCaller(true).Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		StdLoggerAt(r.Context(), LevelWarn).Printf("retrying %d", 42)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	child, _ := grpcLogs(t, &buf)
+	if source, _ := child[slog.SourceKey].(string); !strings.Contains(source, "stdlog_test.go:") {
+		t.Errorf("StdLoggerAt().Printf() child source = %q, want stdlog_test.go", source)
+	}
+}
@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the tracer of the server spans started by the middleware
+const tracerName = "github.com/cccteam/logger"
+
+// attributes of the server spans started by the middleware, in the OpenTelemetry semantic conventions
+const (
+	spanMethodKey = "http.request.method"
+	spanPathKey   = "url.path"
+	spanStatusKey = "http.response.status_code"
+)
+
+// startServerSpan starts a server span of the request with the tracer provider, and returns the request with
+// the context of the span. The parent of the span is the span of the context of the request, or the span
+// propagated in the headers of the request, extracted with the propagator. When the context already has a recording
+// span, e.g. of otelhttp, the application is instrumented, so no span is started and the span is nil, like when
+// the tracer provider is nil.
+func startServerSpan(r *http.Request, tp trace.TracerProvider, p propagation.TextMapPropagator) (*http.Request, trace.Span) {
+	ctx := r.Context()
+	if tp == nil || trace.SpanFromContext(ctx).IsRecording() {
+		return r, nil
+	}
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		if sc := remoteSpanContext(r, p); sc.IsValid() {
+			ctx = trace.ContextWithRemoteSpanContext(ctx, sc)
+		}
+	}
+
+	ctx, span := tp.Tracer(tracerName).Start(ctx, r.Method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String(spanMethodKey, r.Method), attribute.String(spanPathKey, r.URL.Path)),
+	)
+
+	return r.WithContext(ctx), span
+}
+
+// endServerSpan ends the server span with the status of the response, server errors (5xx) set the status of the
+// span to Error. It is a no-op when the span is nil.
+func endServerSpan(span trace.Span, status int) {
+	if span == nil {
+		return
+	}
+
+	span.SetAttributes(attribute.Int(spanStatusKey, status))
+	if status >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+	span.End()
+}
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Test_startServerSpan(t *testing.T) {
+	t.Parallel()
+
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	tests := []struct {
+		name         string
+		nilProvider  bool
+		instrumented bool
+		traceparent  string
+		wantSpan     bool
+		wantTraceID  string
+	}{
+		{name: "nil tracer provider", nilProvider: true},
+		{name: "instrumented", instrumented: true},
+		{name: "propagated", traceparent: traceparent, wantSpan: true, wantTraceID: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{name: "new trace", wantSpan: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+			ctx := context.Background()
+			if tt.instrumented {
+				var appSpan trace.Span
+				ctx, appSpan = tp.Tracer("app").Start(ctx, "app")
+				defer appSpan.End()
+			}
+			r := httptest.NewRequest(http.MethodGet, "/users/42", http.NoBody).WithContext(ctx)
+			if tt.traceparent != "" {
+				r.Header.Set("traceparent", tt.traceparent)
+			}
+
+			var provider trace.TracerProvider = tp
+			if tt.nilProvider {
+				provider = nil
+			}
+			r, span := startServerSpan(r, provider, consolePropagator)
+			if (span != nil) != tt.wantSpan {
+				t.Fatalf("startServerSpan() span = %v, want span %v", span, tt.wantSpan)
+			}
+			if !tt.wantSpan {
+				return
+			}
+
+			sc := trace.SpanContextFromContext(r.Context())
+			if !sc.Equal(span.SpanContext()) {
+				t.Errorf("startServerSpan() context span = %v, want %v", sc, span.SpanContext())
+			}
+			if tt.wantTraceID != "" && sc.TraceID().String() != tt.wantTraceID {
+				t.Errorf("startServerSpan() trace ID = %s, want %s", sc.TraceID(), tt.wantTraceID)
+			}
+
+			endServerSpan(span, http.StatusServiceUnavailable)
+			ended := recorder.Ended()
+			if len(ended) != 1 {
+				t.Fatalf("endServerSpan() ended spans = %d, want 1", len(ended))
+			}
+			if got := ended[0].SpanKind(); got != trace.SpanKindServer {
+				t.Errorf("startServerSpan() span kind = %v, want %v", got, trace.SpanKindServer)
+			}
+			if got := ended[0].Status().Code; got != codes.Error {
+				t.Errorf("endServerSpan() span status = %v, want %v", got, codes.Error)
+			}
+		})
+	}
+}
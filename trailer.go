@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const responseTrailerPrefix = "http.response.trailer."
+
+// keys of the gRPC status attributes of the parent log, e.g. for grpc-web and connect requests
+const (
+	grpcStatusKey  = "grpc.status"
+	grpcMessageKey = "grpc.message"
+)
+
+// trailerAttributes returns the attributes of the response trailers in h, the header map of the response after
+// the handler returned, and the minimum level of the parent log for the gRPC status of the response. The trailers
+// are the headers declared in the Trailer header and the headers with the http.TrailerPrefix. gRPC responses
+// without a body send the grpc-status in the headers, so it is also read from h.
+func trailerAttributes(h http.Header) (map[string]any, slog.Level) {
+	trailers := make(http.Header)
+	names := make([]string, 0)
+	for _, v := range h.Values("Trailer") {
+		for _, name := range strings.Split(v, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if values := h.Values(name); name != "" && len(values) > 0 {
+				trailers[name] = values
+				names = append(names, name)
+			}
+		}
+	}
+	for k, values := range h {
+		if name, ok := strings.CutPrefix(k, http.TrailerPrefix); ok {
+			name = http.CanonicalHeaderKey(name)
+			if _, ok := trailers[name]; !ok {
+				names = append(names, name)
+			}
+			trailers[name] = append(trailers[name], values...)
+		}
+	}
+
+	attrs := headerAttributes(responseTrailerPrefix, trailers, names)
+
+	status := trailers.Get("Grpc-Status")
+	if status == "" {
+		status = h.Get("Grpc-Status")
+	}
+	code, err := strconv.Atoi(status)
+	if err != nil {
+		return attrs, slog.LevelInfo
+	}
+	attrs[grpcStatusKey] = code
+	if msg := trailers.Get("Grpc-Message"); msg != "" {
+		attrs[grpcMessageKey] = msg
+	} else if msg := h.Get("Grpc-Message"); msg != "" {
+		attrs[grpcMessageKey] = msg
+	}
+
+	return attrs, grpcStatusLevel(code)
+}
+
+// grpcStatusLevel maps the gRPC status code to the minimum level of the parent request log: slog.LevelInfo for
+// OK, slog.LevelWarn for errors caused by the client, e.g. NotFound, and slog.LevelError for the other codes
+func grpcStatusLevel(code int) slog.Level {
+	switch code {
+	case 0: // OK
+		return slog.LevelInfo
+	case 1, 3, 5, 6, 7, 9, 11, 16: // Canceled, InvalidArgument, NotFound, AlreadyExists, PermissionDenied, FailedPrecondition, OutOfRange, Unauthenticated
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func Test_trailerAttributes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		h         http.Header
+		want      map[string]any
+		wantLevel slog.Level
+	}{
+		{
+			name:      "no trailers",
+			h:         http.Header{"Content-Type": {"application/json"}},
+			want:      map[string]any{},
+			wantLevel: slog.LevelInfo,
+		},
+		{
+			name: "declared trailers",
+			h: http.Header{
+				"Trailer":      {"X-Checksum, Grpc-Status", "Grpc-Message"},
+				"X-Checksum":   {"abc"},
+				"Grpc-Status":  {"0"},
+				"Content-Type": {"application/grpc-web"},
+			},
+			want: map[string]any{
+				"http.response.trailer.x-checksum":  "abc",
+				"http.response.trailer.grpc-status": "0",
+				grpcStatusKey:                       0,
+			},
+			wantLevel: slog.LevelInfo,
+		},
+		{
+			name: "prefixed trailers",
+			h: http.Header{
+				http.TrailerPrefix + "Grpc-Status":  {"13"},
+				http.TrailerPrefix + "Grpc-Message": {"internal error"},
+			},
+			want: map[string]any{
+				"http.response.trailer.grpc-status":  "13",
+				"http.response.trailer.grpc-message": "internal error",
+				grpcStatusKey:                        13,
+				grpcMessageKey:                       "internal error",
+			},
+			wantLevel: slog.LevelError,
+		},
+		{
+			name: "trailers-only response",
+			h: http.Header{
+				"Grpc-Status":  {"5"},
+				"Grpc-Message": {"not found"},
+			},
+			want: map[string]any{
+				grpcStatusKey:  5,
+				grpcMessageKey: "not found",
+			},
+			wantLevel: slog.LevelWarn,
+		},
+		{
+			name: "sensitive trailer",
+			h: http.Header{
+				http.TrailerPrefix + "Set-Cookie": {"session=secret"},
+			},
+			want: map[string]any{
+				"http.response.trailer.set-cookie": redactedValue,
+			},
+			wantLevel: slog.LevelInfo,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, gotLevel := trailerAttributes(tt.h)
+			if diff := deep.Equal(got, tt.want); diff != nil {
+				t.Errorf("trailerAttributes() attrs = %v", diff)
+			}
+			if gotLevel != tt.wantLevel {
+				t.Errorf("trailerAttributes() level = %v, want %v", gotLevel, tt.wantLevel)
+			}
+		})
+	}
+}
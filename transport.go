@@ -0,0 +1,194 @@
+package logger
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/go-playground/errors/v5"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// keys of the attributes of the child logs of outbound requests written by Transport
+const (
+	outboundMethodKey  = "http.client.method"
+	outboundURLKey     = "http.client.url"
+	outboundStatusKey  = "http.client.status_code"
+	outboundLatencyKey = "http.client.latency_ms"
+)
+
+// keys of the timing attributes of the phases of outbound requests, added when the phase occurred
+const (
+	outboundDNSKey       = "http.client.dns_ms"
+	outboundConnectKey   = "http.client.connect_ms"
+	outboundTLSKey       = "http.client.tls_ms"
+	outboundFirstByteKey = "http.client.first_byte_ms"
+	outboundReusedKey    = "http.client.conn_reused"
+)
+
+// Transport is an http.RoundTripper that writes a child log for each outbound request, correlated to the
+// request logs of the context of the outbound request, and propagates the trace in the headers of the Propagator of
+// the exporter (the global propagator of OpenTelemetry outside of requests), and the correlation ID of the
+// CorrelationID option of the exporter in its header, e.g.
+//
+//	client := &http.Client{Transport: logger.NewTransport(nil)}
+//	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, "https://example.com", http.NoBody)
+//	resp, err := client.Do(req)
+type Transport struct {
+	base http.RoundTripper
+}
+
+// NewTransport returns a Transport that sends the requests with base, or http.DefaultTransport when base is nil
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &Transport{base: base}
+}
+
+// RoundTrip implements http.RoundTripper. Requests that fail, or with a server error response (5xx), are
+// logged as errors, other requests are logged as info.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	l := adapterCtx(req.Context())
+	p := propagatorFromCtx(req.Context())
+	sc, propagateTrace := outboundSpanContext(req, p, l.RawTraceID())
+	c, propagateCorrelation := outboundCorrelation(req)
+	if propagateTrace || propagateCorrelation {
+		// a RoundTripper must not modify the request
+		req = req.Clone(req.Context())
+	}
+	if propagateTrace {
+		p.Inject(trace.ContextWithSpanContext(req.Context(), sc), propagation.HeaderCarrier(req.Header))
+	}
+	if propagateCorrelation {
+		req.Header.Set(c.header, c.id)
+	}
+
+	timing := &egressTiming{begin: time.Now()}
+	resp, err := t.base.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), timing.clientTrace())))
+	a := l.WithAttributes().
+		AddAttribute(outboundMethodKey, req.Method).
+		AddAttribute(outboundURLKey, req.URL.Redacted()).
+		AddAttribute(outboundLatencyKey, float64(time.Since(timing.begin))/float64(time.Millisecond))
+	for k, v := range timing.attributes() {
+		a.AddAttribute(k, v)
+	}
+	if err != nil {
+		a.Logger().WithError(err).Errorf("%s %s failed", req.Method, req.URL.Redacted())
+
+		return nil, errors.Wrap(err, "http.RoundTripper.RoundTrip()")
+	}
+
+	a.AddAttribute(outboundStatusKey, resp.StatusCode)
+	a.Logger().Log(DefaultStatusLevel(resp.StatusCode), fmt.Sprintf("%s %s %d", req.Method, req.URL.Redacted(), resp.StatusCode))
+
+	return resp, nil
+}
+
+// outboundSpanContext returns the span context propagated to the outbound request: the span of the context of
+// the request, or a new span of the trace of the request logs. It returns false when the request already has a
+// header of the propagator p, p has no headers, or there is no trace to propagate.
+func outboundSpanContext(req *http.Request, p propagation.TextMapPropagator, rawTraceID string) (trace.SpanContext, bool) {
+	fields := p.Fields()
+	if len(fields) == 0 {
+		return trace.SpanContext{}, false
+	}
+	for _, field := range fields {
+		if req.Header.Get(field) != "" {
+			return trace.SpanContext{}, false
+		}
+	}
+	if sc := trace.SpanContextFromContext(req.Context()); sc.IsValid() {
+		return sc, true
+	}
+
+	traceID, err := trace.TraceIDFromHex(rawTraceID)
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+	var spanID trace.SpanID
+	_, _ = rand.Read(spanID[:])
+
+	return trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}), true
+}
+
+// outboundCorrelation returns the correlation ID of the context of the outbound request and its header. It returns
+// false when the request already has the header, or there is no correlation ID to propagate.
+func outboundCorrelation(req *http.Request) (correlation, bool) {
+	c, ok := correlationFromCtx(req.Context())
+	if !ok || req.Header.Get(c.header) != "" {
+		return correlation{}, false
+	}
+
+	return c, true
+}
+
+// egressTiming records the timing of the phases of an outbound request with httptrace. The hooks of the
+// connection may be called concurrently, e.g. when dialing several addresses, so the fields are locked.
+type egressTiming struct {
+	begin        time.Time
+	mu           sync.Mutex
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	firstByte    time.Time
+	gotConn      bool
+	reused       bool
+}
+
+// clientTrace returns the httptrace.ClientTrace that records the timing
+func (e *egressTiming) clientTrace() *httptrace.ClientTrace {
+	record := func(t *time.Time, first bool) {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if !first || t.IsZero() {
+			*t = time.Now()
+		}
+	}
+
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { record(&e.dnsStart, true) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { record(&e.dnsDone, false) },
+		ConnectStart:         func(string, string) { record(&e.connectStart, true) },
+		ConnectDone:          func(string, string, error) { record(&e.connectDone, false) },
+		TLSHandshakeStart:    func() { record(&e.tlsStart, true) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { record(&e.tlsDone, false) },
+		GotFirstResponseByte: func() { record(&e.firstByte, true) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			e.gotConn, e.reused = true, info.Reused
+		},
+	}
+}
+
+// attributes returns the timing attributes in milliseconds of the phases of the request that occurred
+func (e *egressTiming) attributes() map[string]any {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	attrs := make(map[string]any)
+	phase := func(key string, start, done time.Time) {
+		if !start.IsZero() && !done.IsZero() {
+			attrs[key] = float64(done.Sub(start)) / float64(time.Millisecond)
+		}
+	}
+	phase(outboundDNSKey, e.dnsStart, e.dnsDone)
+	phase(outboundConnectKey, e.connectStart, e.connectDone)
+	phase(outboundTLSKey, e.tlsStart, e.tlsDone)
+	phase(outboundFirstByteKey, e.begin, e.firstByte)
+	if e.gotConn {
+		attrs[outboundReusedKey] = e.reused
+	}
+
+	return attrs
+}
@@ -0,0 +1,278 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/contrib/propagators/b3"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestTransport_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		status      int
+		err         error
+		traceparent string
+		wantLog     string
+	}{
+		{
+			name:    "success",
+			status:  http.StatusOK,
+			wantLog: "INFO : GET http://example.com/path 200",
+		},
+		{
+			name:    "server error",
+			status:  http.StatusBadGateway,
+			wantLog: "ERROR: GET http://example.com/path 502",
+		},
+		{
+			name:    "request error",
+			err:     errors.New("connection refused"),
+			wantLog: "ERROR: GET http://example.com/path failed",
+		},
+		{
+			name:        "traceparent of the request",
+			status:      http.StatusOK,
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			wantLog:     "INFO : GET http://example.com/path 200",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotTraceparent string
+			client := &http.Client{Transport: NewTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				gotTraceparent = req.Header.Get("traceparent")
+				if tt.err != nil {
+					return nil, tt.err
+				}
+
+				return &http.Response{StatusCode: tt.status, Body: http.NoBody, Request: req}, nil
+			}))}
+
+			var buf bytes.Buffer
+			var traceID string
+			handler := NewConsoleExporter().NoColor(true).Writer(&buf).Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				traceID = Req(r).RawTraceID()
+				req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, "http://example.com/path", http.NoBody)
+				if err != nil {
+					t.Errorf("http.NewRequestWithContext() error = %v", err)
+
+					return
+				}
+				if tt.traceparent != "" {
+					req.Header.Set("traceparent", tt.traceparent)
+				}
+				resp, err := client.Do(req)
+				if (err != nil) != (tt.err != nil) {
+					t.Errorf("http.Client.Do() error = %v, wantErr %v", err, tt.err)
+				}
+				if err == nil {
+					_ = resp.Body.Close()
+				}
+				if req.Header.Get("traceparent") != tt.traceparent {
+					t.Errorf("Transport.RoundTrip() modified the request headers: %v", req.Header)
+				}
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+			if !strings.Contains(buf.String(), tt.wantLog) {
+				t.Errorf("Transport.RoundTrip() output = %q, missing %q", buf.String(), tt.wantLog)
+			}
+			wantTraceparent := tt.traceparent
+			if wantTraceparent == "" {
+				wantTraceparent = "00-" + traceID + "-"
+			}
+			if !strings.HasPrefix(gotTraceparent, wantTraceparent) {
+				t.Errorf("Transport.RoundTrip() traceparent = %q, want prefix %q", gotTraceparent, wantTraceparent)
+			}
+		})
+	}
+}
+
+func TestTransport_RoundTrip_propagator(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		b3TraceID   string
+		wantTraceID func(traceID string) string
+	}{
+		{
+			name:        "trace of the request logs",
+			wantTraceID: func(traceID string) string { return traceID },
+		},
+		{
+			name:        "b3 header of the request",
+			b3TraceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+			wantTraceID: func(string) string { return "4bf92f3577b34da6a3ce929d0e0e4736" },
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got http.Header
+			client := &http.Client{Transport: NewTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				got = req.Header
+
+				return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+			}))}
+
+			var traceID string
+			exporter := NewConsoleExporter().NoColor(true).Writer(&bytes.Buffer{}).Propagator(b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)))
+			handler := exporter.Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				traceID = Req(r).RawTraceID()
+				req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, "http://example.com/path", http.NoBody)
+				if err != nil {
+					t.Errorf("http.NewRequestWithContext() error = %v", err)
+
+					return
+				}
+				if tt.b3TraceID != "" {
+					req.Header.Set("X-B3-TraceId", tt.b3TraceID)
+				}
+				resp, err := client.Do(req)
+				if err != nil {
+					t.Errorf("http.Client.Do() error = %v", err)
+
+					return
+				}
+				_ = resp.Body.Close()
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+			if want := tt.wantTraceID(traceID); got.Get("X-B3-TraceId") != want {
+				t.Errorf("Transport.RoundTrip() X-B3-TraceId = %q, want %q", got.Get("X-B3-TraceId"), want)
+			}
+			if got.Get("traceparent") != "" {
+				t.Errorf("Transport.RoundTrip() traceparent = %q, want none with the B3 propagator", got.Get("traceparent"))
+			}
+		})
+	}
+}
+
+func TestTransport_RoundTrip_correlationID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		incoming string
+		outgoing string
+		want     string
+	}{
+		{
+			name:     "propagated",
+			incoming: "corr-123",
+			want:     "corr-123",
+		},
+		{
+			name:     "header of the outbound request",
+			incoming: "corr-123",
+			outgoing: "corr-456",
+			want:     "corr-456",
+		},
+		{
+			name: "no correlation ID",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got string
+			client := &http.Client{Transport: NewTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				got = req.Header.Get("X-Correlation-ID")
+
+				return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+			}))}
+
+			var buf bytes.Buffer
+			handler := NewConsoleExporter().NoColor(true).Writer(&buf).CorrelationID("X-Correlation-ID").Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				if id := Req(r).CorrelationID(); id != tt.incoming {
+					t.Errorf("Logger.CorrelationID() = %q, want %q", id, tt.incoming)
+				}
+				req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, "http://example.com/path", http.NoBody)
+				if err != nil {
+					t.Errorf("http.NewRequestWithContext() error = %v", err)
+
+					return
+				}
+				if tt.outgoing != "" {
+					req.Header.Set("X-Correlation-ID", tt.outgoing)
+				}
+				resp, err := client.Do(req)
+				if err != nil {
+					t.Errorf("http.Client.Do() error = %v", err)
+
+					return
+				}
+				_ = resp.Body.Close()
+				if req.Header.Get("X-Correlation-ID") != tt.outgoing {
+					t.Errorf("Transport.RoundTrip() modified the request headers: %v", req.Header)
+				}
+			}))
+			r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			if tt.incoming != "" {
+				r.Header.Set("X-Correlation-ID", tt.incoming)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+
+			if got != tt.want {
+				t.Errorf("Transport.RoundTrip() X-Correlation-ID = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_egressTiming(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	timing := &egressTiming{begin: time.Now()}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), timing.clientTrace()), http.MethodGet, srv.URL, http.NoBody)
+	if err != nil {
+		t.Fatalf("http.NewRequestWithContext() error = %v", err)
+	}
+	resp, err := srv.Client().Transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	_ = resp.Body.Close()
+
+	attrs := timing.attributes()
+	for _, k := range []string{outboundConnectKey, outboundTLSKey, outboundFirstByteKey} {
+		if _, ok := attrs[k].(float64); !ok {
+			t.Errorf("egressTiming.attributes()[%q] = %v, want float64", k, attrs[k])
+		}
+	}
+	if _, ok := attrs[outboundDNSKey]; ok {
+		t.Errorf("egressTiming.attributes()[%q] = %v, want none for an IP address", outboundDNSKey, attrs[outboundDNSKey])
+	}
+	if attrs[outboundReusedKey] != false {
+		t.Errorf("egressTiming.attributes()[%q] = %v, want false", outboundReusedKey, attrs[outboundReusedKey])
+	}
+}
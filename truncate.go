@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"encoding/json"
+	"log/slog"
+	"unicode/utf8"
+)
+
+// truncatedMarker is appended to messages and attributes that are truncated
+const truncatedMarker = "...[truncated]"
+
+// truncate returns s cut to at most max bytes, ending with the truncatedMarker. The
+// cut is made at a rune boundary. When max is shorter than the truncatedMarker, the marker is cut to max
+// bytes. A max of 0 or less disables truncation.
+func truncate(s string, max int) string {
+	if max <= 0 || len(s) <= max {
+		return s
+	}
+
+	i := max - len(truncatedMarker)
+	if i < 0 {
+		return truncatedMarker[:max]
+	}
+	for i > 0 && !utf8.RuneStart(s[i]) {
+		i--
+	}
+
+	return s[:i] + truncatedMarker
+}
+
+// truncateValue returns the attribute value v, or its JSON serialization truncated to max bytes
+// when the serialization is larger than max. A max of 0 or less disables truncation.
+func truncateValue(v any, max int) any {
+	if max <= 0 {
+		return v
+	}
+	if s, ok := v.(string); ok {
+		return truncate(s, max)
+	}
+
+	b, err := json.Marshal(resolveValue(v))
+	if err != nil || len(b) <= max {
+		return v
+	}
+
+	return truncate(string(b), max)
+}
+
+// resolveValue resolves slog.Value and slog.LogValuer values to a value that encodes as JSON, with
+// groups encoded as nested objects, and errors as their message. Other values are returned as is.
+func resolveValue(v any) any {
+	var value slog.Value
+	switch t := v.(type) {
+	case slog.Value:
+		value = t
+	case slog.LogValuer:
+		value = slog.AnyValue(t)
+	case error:
+		return t.Error()
+	default:
+		return v
+	}
+
+	value = value.Resolve()
+	if value.Kind() != slog.KindGroup {
+		return resolveValue(value.Any())
+	}
+
+	group := make(map[string]any)
+	resolveGroup(group, value.Group())
+
+	return group
+}
+
+// resolveGroup adds the attrs to group, inlining groups with an empty key as slog does
+func resolveGroup(group map[string]any, attrs []slog.Attr) {
+	for _, a := range attrs {
+		a.Value = a.Value.Resolve()
+		switch {
+		case a.Equal(slog.Attr{}):
+		case a.Key == "" && a.Value.Kind() == slog.KindGroup:
+			resolveGroup(group, a.Value.Group())
+		default:
+			group[a.Key] = resolveValue(a.Value)
+		}
+	}
+}
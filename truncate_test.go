@@ -0,0 +1,145 @@
+package logger
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_truncate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		s    string
+		max  int
+		want string
+	}{
+		{name: "disabled", s: "message", max: 0, want: "message"},
+		{name: "short", s: "message", max: 7, want: "message"},
+		{name: "long", s: strings.Repeat("a", 30), max: 20, want: "aaaaaa...[truncated]"},
+		{name: "rune boundary", s: "aaaaa€€€€€€€€€€", max: 22, want: "aaaaa€...[truncated]"},
+		{name: "max equal to marker", s: strings.Repeat("a", 30), max: 14, want: "...[truncated]"},
+		{name: "max less than marker", s: strings.Repeat("a", 30), max: 5, want: "...[t"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := truncate(tt.s, tt.max); got != tt.want {
+				t.Errorf("truncate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_truncateValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		v    any
+		max  int
+		want any
+	}{
+		{name: "disabled", v: []int{1, 2, 3}, max: 0, want: []int{1, 2, 3}},
+		{name: "string", v: strings.Repeat("a", 30), max: 20, want: "aaaaaa...[truncated]"},
+		{name: "small value", v: map[string]int{"a": 1}, max: 20, want: map[string]int{"a": 1}},
+		{name: "large value", v: map[string]string{"key": strings.Repeat("a", 30)}, max: 24, want: `{"key":"aa...[truncated]`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(tt.want, truncateValue(tt.v, tt.max)); diff != "" {
+				t.Errorf("truncateValue() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type testLogValuer struct {
+	name string
+}
+
+func (v testLogValuer) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("name", v.name))
+}
+
+type testJSONValue struct {
+	Name string `json:"name"`
+}
+
+func Test_resolveValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		v    any
+		want any
+	}{
+		{
+			name: "plain value",
+			v:    "value",
+			want: "value",
+		},
+		{
+			name: "slog value",
+			v:    slog.IntValue(1),
+			want: int64(1),
+		},
+		{
+			name: "group",
+			v:    slog.GroupValue(slog.String("a", "1"), slog.Group("b", slog.Bool("c", true))),
+			want: map[string]any{"a": "1", "b": map[string]any{"c": true}},
+		},
+		{
+			name: "inline group",
+			v:    slog.GroupValue(slog.String("a", "1"), slog.Group("", slog.String("b", "2"))),
+			want: map[string]any{"a": "1", "b": "2"},
+		},
+		{
+			name: "log valuer",
+			v:    testLogValuer{name: "test"},
+			want: map[string]any{"name": "test"},
+		},
+		{
+			name: "log valuer in group",
+			v:    slog.GroupValue(slog.Any("user", testLogValuer{name: "test"})),
+			want: map[string]any{"user": map[string]any{"name": "test"}},
+		},
+		{
+			name: "struct",
+			v:    testJSONValue{Name: "test"},
+			want: testJSONValue{Name: "test"},
+		},
+		{
+			name: "error",
+			v:    errors.New("failed"),
+			want: "failed",
+		},
+		{
+			name: "error in slog value",
+			v:    slog.AnyValue(errors.New("failed")),
+			want: "failed",
+		},
+		{
+			name: "struct in group",
+			v:    slog.GroupValue(slog.Any("user", testJSONValue{Name: "test"}), slog.Any("err", errors.New("failed"))),
+			want: map[string]any{"user": testJSONValue{Name: "test"}, "err": "failed"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tt.want, resolveValue(tt.v)); diff != "" {
+				t.Errorf("resolveValue() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// zerologPkg is the function name prefix of zerolog, whose frames are skipped to find the caller
+const zerologPkg = "github.com/rs/zerolog."
+
+// field names of the JSON logs of zerolog, the defaults of zerolog.LevelFieldName, zerolog.MessageFieldName,
+// zerolog.TimestampFieldName, and zerolog.ErrorFieldName
+const (
+	zerologLevelField   = "level"
+	zerologMessageField = "message"
+	zerologTimeField    = "time"
+	zerologErrorField   = "error"
+)
+
+// NewZerologWriter returns an io.Writer for zerolog that writes the JSON logs of zerolog as child logs with the
+// logger of the context (see Ctx), so services using zerolog get the middleware and exporters of this package, e.g.
+//
+//	zl := zerolog.New(logger.NewZerologWriter(r.Context()))
+//	zl.Info().Str("user", id).Msg("signed in")
+//
+// The level and message are those of the child log, the time is the time of the child log, the error is the
+// error.message attribute, and the other fields are child log attributes. The fatal and panic levels are
+// logged as Critical, and logs without a level as Info. Writes that are not JSON, e.g. of zerolog.ConsoleWriter,
+// are logged as Info messages. The field names must be the defaults of zerolog. The caller written with
+// IncludeCaller is the caller of zerolog.
+func NewZerologWriter(ctx context.Context) io.Writer {
+	return &zerologWriter{l: adapterCtx(ctx).withCaller(0, zerologPkg)}
+}
+
+// zerologWriter is the io.Writer returned by NewZerologWriter
+type zerologWriter struct {
+	l *Logger
+}
+
+// Write writes the zerolog JSON log in p as a child log, it never returns an error
+func (w *zerologWriter) Write(p []byte) (int, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(p, &fields); err != nil {
+		w.l.Info(string(bytes.TrimSuffix(p, []byte("\n"))))
+
+		return len(p), nil
+	}
+
+	level, _ := fields[zerologLevelField].(string)
+	msg, _ := fields[zerologMessageField].(string)
+	delete(fields, zerologLevelField)
+	delete(fields, zerologMessageField)
+	delete(fields, zerologTimeField)
+
+	l := w.l
+	if len(fields) > 0 {
+		a := w.l.WithAttributes()
+		for k, v := range fields {
+			if k == zerologErrorField {
+				k = errorMessageKey
+			}
+			a.AddAttribute(k, v)
+		}
+		l = a.Logger()
+	}
+	l.Log(zerologLevel(level), msg)
+
+	return len(p), nil
+}
+
+// zerologLevel returns the level of the zerolog level name
+func zerologLevel(level string) Level {
+	switch level {
+	case "trace":
+		return LevelTrace
+	case "debug":
+		return LevelDebug
+	case "warn":
+		return LevelWarn
+	case "error":
+		return LevelError
+	case "fatal", "panic":
+		return LevelCritical
+	default:
+		return LevelInfo
+	}
+}
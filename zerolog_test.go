@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewZerologWriter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		write     string
+		wantChild map[string]any
+		wantNot   []string
+	}{
+		{
+			name:      "info with fields",
+			write:     `{"level":"info","user":"42","attempt":2,"time":"2024-07-01T10:00:00Z","message":"signed in"}` + "\n",
+			wantChild: map[string]any{"level": "INFO", "msg": "signed in", "user": "42", "attempt": float64(2)},
+			wantNot:   []string{"message"},
+		},
+		{
+			name:      "error",
+			write:     `{"level":"error","error":"connection refused","message":"query failed"}`,
+			wantChild: map[string]any{"level": "ERROR", "msg": "query failed", errorMessageKey: "connection refused"},
+			wantNot:   []string{"error"},
+		},
+		{
+			name:      "fatal",
+			write:     `{"level":"fatal","message":"exiting"}`,
+			wantChild: map[string]any{"level": "CRITICAL", "msg": "exiting"},
+		},
+		{
+			name:      "no level",
+			write:     `{"message":"no level"}`,
+			wantChild: map[string]any{"level": "INFO", "msg": "no level"},
+		},
+		{
+			name:      "not json",
+			write:     "10:00AM INF signed in user=42\n",
+			wantChild: map[string]any{"level": "INFO", "msg": "10:00AM INF signed in user=42"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			h := NewConsoleExporter().Writer(&buf).Format(JSONFormat).Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				if n, err := NewZerologWriter(r.Context()).Write([]byte(tt.write)); err != nil || n != len(tt.write) {
+					t.Errorf("zerologWriter.Write() = %d, %v, want %d, nil", n, err, len(tt.write))
+				}
+			}))
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+			child, _ := grpcLogs(t, &buf)
+			for k, want := range tt.wantChild {
+				if got := child[k]; got != want {
+					t.Errorf("zerologWriter.Write() child %s = %v, want %v", k, got, want)
+				}
+			}
+			for _, k := range tt.wantNot {
+				if got, ok := child[k]; ok {
+					t.Errorf("zerologWriter.Write() child %s = %v, want no attribute", k, got)
+				}
+			}
+		})
+	}
+}
+
+func TestNewZerologWriter_caller(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	h := NewConsoleExporter().Writer(&buf).Format(JSONFormat).IncludeCaller(true).Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		_, _ = NewZerologWriter(r.Context()).Write([]byte(`{"level":"info","message":"signed in"}`))
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	child, _ := grpcLogs(t, &buf)
+	if source, _ := child[slog.SourceKey].(string); !strings.Contains(source, "zerolog_test.go:") {
+		t.Errorf("zerologWriter.Write() child source = %q, want zerolog_test.go", source)
+	}
+}